@@ -13,7 +13,22 @@ touch $RESULT_FILE
 cur_branch=`git rev-parse HEAD`
 echo Current branch is \"$cur_branch\"
 echo Checking version for rules file \"$RULES_FILE\"...
-cp $RULES_FILE tmp_rule_file.yaml
+mkdir -p tmp_rules
+cp $RULES_FILE tmp_rules/
+rm -rf tmp_tests
+if [ -d tests ]
+then
+    cp -r tests tmp_tests
+fi
+# the deprecated rules file is optional, and removed rules can only be moved
+# to it if the current branch defines it
+DEPRECATED_RULES_FILE=rules/falco-deprecated_rules.yaml
+rm -rf tmp_deprecated
+mkdir -p tmp_deprecated
+if [ -f $DEPRECATED_RULES_FILE ]
+then
+    cp $DEPRECATED_RULES_FILE tmp_deprecated/
+fi
 
 rules_name=`echo $RULES_FILE | sed -re 's/rules\/(.*)_rules\.yaml/\1/'`
 echo Searching tag with prefix prefix \"$rules_name-rules-\"...
@@ -35,14 +50,29 @@ else
     echo Most recent tag found is \"$latest_tag\"
 fi
 
+tests_flags=""
+if [ -d tmp_tests ]
+then
+    tests_flags="--tests tmp_tests --base-tests tests"
+fi
+
+deprecations_flags="--check-deprecations"
+if [ -f tmp_deprecated/$(basename $DEPRECATED_RULES_FILE) ]
+then
+    deprecations_flags="$deprecations_flags --deprecated tmp_deprecated/$(basename $DEPRECATED_RULES_FILE) --allow-untested $(basename $DEPRECATED_RULES_FILE)"
+fi
+
 git checkout tags/$latest_tag
 chmod +x $CHECKER_TOOL
+compare_status=0
 $CHECKER_TOOL \
     compare \
     --falco-image=$FALCO_DOCKER_IMAGE \
     -l $RULES_FILE \
-    -r tmp_rule_file.yaml \
-1>tmp_res.txt
+    -r tmp_rules/$(basename $RULES_FILE) \
+    $tests_flags \
+    $deprecations_flags \
+1>tmp_res.txt || compare_status=$?
 git switch --detach $cur_branch
 
 echo '##' $(basename $RULES_FILE) >> $RESULT_FILE
@@ -67,5 +97,9 @@ if $(git branch --show-current | grep -q "release/"); then
     fi
 fi
 
-rm -f tmp_rule_file.yaml
+rm -rf tmp_rules
+rm -rf tmp_tests
+rm -rf tmp_deprecated
 rm -f tmp_res.txt
+
+exit $compare_status
@@ -5,24 +5,54 @@ import datetime
 import os
 import sys
 import re
+import subprocess
 
 """
 Usage:
 pip install -r .github/scripts/requirements.txt
 python .github/scripts/rules_overview_generator.py --rules_dir=rules > docs/index.md
+
+To also emit one documentation page per rule, macro and list, and the related
+navigation entries in mkdocs.yml:
+python .github/scripts/rules_overview_generator.py --rules_dir=rules --docs_dir=docs --mkdocs_file=mkdocs.yml > docs/index.md
 """
 
 BASE_MITRE_URL_TECHNIQUE="https://attack.mitre.org/techniques/"
 BASE_MITRE_URL_TACTIC="https://attack.mitre.org/tactics/"
 BASE_PCI_DSS="https://docs-prv.pcisecuritystandards.org/PCI%20DSS/Standard/PCI-DSS-v4_0.pdf"
 BASE_NIST="https://csf.tools/reference/nist-sp-800-53/r5/"
+BASE_RULES_URL="https://github.com/falcosecurity/rules/blob/main/rules/"
+# Rules files are loaded in this order, which mirrors the maturity levels, when
+# resolving macros and lists and when detecting overrides across files
+RULES_FILES_ORDER=['falco_rules.yaml', 'falco-incubating_rules.yaml', 'falco-sandbox_rules.yaml', 'falco-deprecated_rules.yaml']
+MATURITY_LEVELS=['maturity_stable', 'maturity_incubating', 'maturity_sandbox', 'maturity_deprecated']
 COLUMNS=['maturity', 'rule', 'desc', 'workload', 'mitre_phase', 'mitre_ttp', 'extra_tags', 'compliance_pci_dss', 'compliance_nist', 'extra_tags_list', 'mitre_phase_list', 'compliance_pci_dss_list', 'compliance_nist_list', 'enabled']
 
 def arg_parser():
     parser = argparse.ArgumentParser()
     parser.add_argument('--rules_dir', help='Path to falco rules directory containing all rules yaml files.')
+    parser.add_argument('--docs_dir', help='Optional path to the mkdocs docs directory in which one page per rule, macro, and list is generated.')
+    parser.add_argument('--mkdocs_file', help='Optional path to the mkdocs.yml file in which the navigation entries of the generated pages are written.')
     return parser.parse_args()
 
+def tag_to_markdown(tag):
+    """Returns the tag as a Markdown link to its MITRE or compliance reference, or the plain tag if it has none."""
+    if tag.startswith('PCI_DSS_'):
+        return '[{}]({})'.format(tag, BASE_PCI_DSS)
+    if tag.startswith('NIST_800-53_'):
+        # NIST links: revisit in the future, could be fragile
+        return '[{}]({}{}/{})'.format(tag, BASE_NIST, re.search('NIST_800-53_(.*)-', tag, re.IGNORECASE).group(1).lower(), \
+            tag.replace('NIST_800-53_', '').lower())
+    if tag.startswith('TA'):
+        return '[{}]({}{})'.format(tag, BASE_MITRE_URL_TACTIC, tag.replace('.', '/'))
+    if tag.startswith('T'):
+        return '[{}]({}{})'.format(tag, BASE_MITRE_URL_TECHNIQUE, tag.replace('.', '/'))
+    return tag
+
+def md_cell(value):
+    """Returns the value as text that can be put in a Markdown table cell, escaping the pipes and joining the lines."""
+    return ' '.join(str(value).split('\n')).replace('|', '\\|')
+
 def rules_to_df(rules_dir):
     l = []
     for rules_filename in os.listdir(rules_dir):
@@ -38,20 +68,15 @@ def rules_to_df(rules_dir):
                             if i.startswith('maturity_'):
                                 item['maturity'].append(i) # should be just one per rule, be resilient and treat as list as well
                             elif i.startswith('PCI_DSS_'):
-                                item['compliance_pci_dss'].append('[{}]({})'.format(i, BASE_PCI_DSS))
+                                item['compliance_pci_dss'].append(tag_to_markdown(i))
                             elif i.startswith('NIST_800-53_'):
-                                # NIST links: revisit in the future, could be fragile
-                                item['compliance_nist'].append('[{}]({}{}/{})'.format(i, BASE_NIST, re.search('NIST_800-53_(.*)-', i, re.IGNORECASE).group(1).lower(), \
-                                    i.replace('NIST_800-53_', '').lower()))
+                                item['compliance_nist'].append(tag_to_markdown(i))
                             elif i in ['host', 'container']:
                                 item['workload'].append(i)
                             elif i.startswith('mitre_'):
                                 item['mitre_phase'].append(i)
                             elif i.startswith('T'):
-                                if i.startswith('TA'):
-                                    item['mitre_ttp'].append('[{}]({}{})'.format(i, BASE_MITRE_URL_TACTIC, i.replace('.', '/')))
-                                else:
-                                    item['mitre_ttp'].append('[{}]({}{})'.format(i, BASE_MITRE_URL_TECHNIQUE, i.replace('.', '/')))
+                                item['mitre_ttp'].append(tag_to_markdown(i))
                             else:
                                 item['extra_tags'].append(i)
                         item['workload'].sort()
@@ -64,6 +89,7 @@ def rules_to_df(rules_dir):
                         item['compliance_pci_dss_list'] = item['compliance_pci_dss']
                         item['compliance_nist_list'] = item['compliance_nist']
                         item['enabled'] = (item['enabled'] if 'enabled' in item else True)
+                        item['rule'], item['desc'] = md_cell(item['rule']), md_cell(item['desc'])
                         l.append([', '.join(item[x]) if x in ['maturity', 'workload', 'mitre_phase', 'mitre_ttp', 'compliance_pci_dss', 'compliance_nist', 'extra_tags'] else item[x] for x in COLUMNS])
 
     if not l:
@@ -150,6 +176,263 @@ def print_markdown(df):
     print(df3.drop('rule', axis=1).to_markdown(index=True))
 
 
+def rules_files_sort_key(rules_filename):
+    if rules_filename in RULES_FILES_ORDER:
+        return (RULES_FILES_ORDER.index(rules_filename), rules_filename)
+    return (len(RULES_FILES_ORDER), rules_filename)
+
+def ruleset_name(rules_filename):
+    """Returns the ruleset name of a rules file, e.g. `falco-incubating` for `falco-incubating_rules.yaml`."""
+    return re.sub(r'_rules\.yaml$', '', rules_filename)
+
+def item_kind(item):
+    for kind in ['rule', 'macro', 'list']:
+        if kind in item:
+            return kind
+    return None
+
+def item_is_override(item):
+    return item.get('append', False) or 'override' in item
+
+def load_rules_files(rules_dir):
+    """Returns a list of (filename, content, items) tuples for all the falco rules files in rules_dir, in load order."""
+    res = []
+    for rules_filename in sorted(os.listdir(rules_dir), key=rules_files_sort_key):
+        if not 'falco' in rules_filename:
+            continue
+        with open(os.path.join(rules_dir, rules_filename), 'r') as f:
+            content = f.read()
+            res.append((rules_filename, content, yaml.safe_load(content) or []))
+    return res
+
+def item_line(content, kind, name):
+    for num, line in enumerate(content.splitlines(), start=1):
+        if re.match(r'^-\s+{}:\s+["\']?{}["\']?\s*$'.format(kind, re.escape(name)), line):
+            return num
+    return 0
+
+def collect_objects(rules_files):
+    """
+    Returns a dict mapping each (kind, name) to its effective definition after applying
+    all the overrides and appends in load order, plus the list of places where it is defined
+    or overridden.
+    """
+    objs = {}
+    for rules_filename, content, items in rules_files:
+        for item in items:
+            kind = item_kind(item)
+            if kind is None:
+                continue
+            name = item[kind]
+            obj = objs.setdefault((kind, name), {'kind': kind, 'name': name, 'item': {}, 'defined_in': [], 'overridden_in': []})
+            location = (rules_filename, item_line(content, kind, name))
+            if not item_is_override(item):
+                obj['item'] = dict(item)
+                obj['defined_in'].append(location)
+                continue
+            obj['overridden_in'].append(location)
+            overrides = item.get('override', {})
+            for key, value in item.items():
+                if key in [kind, 'append', 'override']:
+                    continue
+                mode = 'append' if item.get('append', False) else overrides.get(key, 'replace')
+                if mode == 'append' and key in obj['item']:
+                    if isinstance(value, list):
+                        obj['item'][key] = obj['item'][key] + value
+                    else:
+                        obj['item'][key] = '{} {}'.format(str(obj['item'][key]).rstrip(), value)
+                else:
+                    obj['item'][key] = value
+    return objs
+
+# Tokens of a condition: quoted strings, parentheses, commas, comparison operators, and bare words
+CONDITION_TOKEN_RE = re.compile(r'"(?:[^"\\]|\\.)*"|\'(?:[^\'\\]|\\.)*\'|[()]|,|[=<>!]=?|[^\s()=<>!,"\']+|\s+')
+CONDITION_BOOL_OPS = ['and', 'or', 'not']
+CONDITION_LIST_OPS = ['in', 'intersects', 'pmatch']
+
+def expand_macros(condition, macros, visiting=None):
+    """
+    Returns the condition with all the macro references recursively replaced
+    by their parenthesized conditions. Values of comparisons and of list operators
+    are never considered as macro references.
+    """
+    visiting = visiting or []
+    res = ''
+    prev = None
+    depth = 0
+    values_depth = None
+    for token in CONDITION_TOKEN_RE.findall(str(condition).strip()):
+        if token.isspace():
+            res += ' '
+            continue
+        if token == '(':
+            depth += 1
+            if prev is not None and prev.lower() in CONDITION_LIST_OPS and values_depth is None:
+                values_depth = depth
+        elif token == ')':
+            if values_depth == depth:
+                values_depth = None
+            depth -= 1
+        elif values_depth is None and (prev is None or prev == '(' or prev.lower() in CONDITION_BOOL_OPS) \
+                and token in macros and token not in visiting:
+            token = '({})'.format(expand_macros(macros[token], macros, visiting + [token]))
+        res += token
+        prev = token
+    return res.strip()
+
+def release_tags(ruleset):
+    """Returns the git release tags of a ruleset sorted by version, or an empty list if git is not available."""
+    try:
+        out = subprocess.run(['git', 'tag', '--list', '{}-rules-*'.format(ruleset)], capture_output=True, text=True, check=True).stdout
+    except (OSError, subprocess.CalledProcessError):
+        return []
+    versions = []
+    for tag in out.split():
+        m = re.match(r'^{}-rules-(\d+)\.(\d+)\.(\d+)$'.format(re.escape(ruleset)), tag)
+        if m:
+            versions.append((tuple(int(x) for x in m.groups()), tag))
+    return [tag for _, tag in sorted(versions)]
+
+def tags_history(rules_dir, rules_files):
+    """
+    Returns a dict mapping each rule name to the list of (release, rules file, tags) entries
+    in which the rule tags changed, across all the release tags of each rules file.
+    """
+    history = {}
+    rules_path = os.path.relpath(os.path.abspath(rules_dir), subprocess.run(['git', 'rev-parse', '--show-toplevel'], \
+        capture_output=True, text=True).stdout.strip() or '.')
+    for rules_filename, _, _ in rules_files:
+        previous = {}
+        for tag in release_tags(ruleset_name(rules_filename)):
+            out = subprocess.run(['git', 'show', '{}:{}'.format(tag, os.path.join(rules_path, rules_filename))], capture_output=True, text=True)
+            if out.returncode != 0:
+                continue
+            current = {}
+            for item in yaml.safe_load(out.stdout) or []:
+                if 'rule' in item and not item_is_override(item):
+                    current[item['rule']] = item.get('tags', [])
+            for name in sorted(set(previous) | set(current)):
+                if previous.get(name) != current.get(name):
+                    tags = ', '.join(current[name]) if name in current else '_removed_'
+                    history.setdefault(name, []).append((tag, rules_filename, tags))
+            previous = current
+    return history
+
+def slugify(name):
+    return re.sub(r'[^a-z0-9]+', '-', name.lower()).strip('-')
+
+def page_path(kind, name):
+    return '{}s/{}.md'.format(kind, slugify(name))
+
+def locations_to_markdown(locations):
+    return ['- [{}]({}{}#L{})'.format(f, BASE_RULES_URL, f, line) if line > 0 else '- {}'.format(f) for f, line in locations]
+
+def object_to_markdown(obj, macros, history):
+    kind, name, item = obj['kind'], obj['name'], obj['item']
+    lines = ['# {}\n'.format(name)]
+    if kind == 'rule':
+        lines.append('| priority | enabled | source |')
+        lines.append('|---|---|---|')
+        lines.append('| {} | {} | {} |\n'.format(md_cell(item.get('priority', '')), str(item.get('enabled', True)).lower(), md_cell(item.get('source', 'syscall'))))
+        lines.append('## Description\n')
+        lines.append('{}\n'.format(str(item.get('desc', '')).strip()))
+    if kind in ['rule', 'macro']:
+        lines.append('## Condition\n')
+        lines.append('```\n{}\n```\n'.format(str(item.get('condition', '')).strip()))
+        lines.append('## Condition with macros expanded\n')
+        lines.append('```\n{}\n```\n'.format(expand_macros(item.get('condition', ''), macros)))
+    if kind == 'list':
+        lines.append('## Items\n')
+        lines.append('```\n{}\n```\n'.format(', '.join(str(i) for i in item.get('items', []))))
+    if kind == 'rule':
+        lines.append('## Output\n')
+        lines.append('```\n{}\n```\n'.format(str(item.get('output', '')).strip()))
+        lines.append('## Tags\n')
+        lines.append('{}\n'.format(', '.join(tag_to_markdown(t) for t in item.get('tags', [])) or '_none_'))
+        lines.append('## Exceptions\n')
+        exceptions = item.get('exceptions', [])
+        if not exceptions:
+            lines.append('_none_\n')
+        else:
+            lines.append('| name | fields | comps | values |')
+            lines.append('|---|---|---|---|')
+            for e in exceptions:
+                lines.append('| {} | `{}` | `{}` | `{}` |'.format(*(md_cell(v) for v in [e.get('name', ''), e.get('fields', ''), e.get('comps', ''), e.get('values', [])])))
+            lines.append('')
+    lines.append('## Defined in\n')
+    lines.extend(locations_to_markdown(obj['defined_in']) or ['_nowhere, only overridden_'])
+    lines.append('')
+    if obj['overridden_in']:
+        lines.append('## Overridden in\n')
+        lines.extend(locations_to_markdown(obj['overridden_in']))
+        lines.append('')
+    if kind == 'rule':
+        lines.append('## Tags history\n')
+        if name not in history:
+            lines.append('_no release found_\n')
+        else:
+            lines.append('| release | rules file | tags |')
+            lines.append('|---|---|---|')
+            for tag, rules_filename, tags in history[name]:
+                lines.append('| {} | {} | {} |'.format(tag, rules_filename, md_cell(tags)))
+            lines.append('')
+    return '\n'.join(lines)
+
+def rule_maturity(obj):
+    for tag in obj['item'].get('tags', []):
+        if tag in MATURITY_LEVELS:
+            return tag
+    return None
+
+def write_pages(rules_dir, docs_dir):
+    """Writes one Markdown page per rule, macro, and list in docs_dir and returns the related mkdocs navigation entries."""
+    rules_files = load_rules_files(rules_dir)
+    objs = collect_objects(rules_files)
+    macros = {name: obj['item'].get('condition', '') for (kind, name), obj in objs.items() if kind == 'macro'}
+    history = tags_history(rules_dir, rules_files)
+    for (kind, name), obj in objs.items():
+        path = os.path.join(docs_dir, page_path(kind, name))
+        os.makedirs(os.path.dirname(path), exist_ok=True)
+        with open(path, 'w') as f:
+            f.write(object_to_markdown(obj, macros, history))
+
+    nav = []
+    rules_nav = []
+    for maturity in MATURITY_LEVELS + [None]:
+        entries = [{name: page_path(kind, name)} for (kind, name), obj in sorted(objs.items()) if kind == 'rule' and rule_maturity(obj) == maturity]
+        if entries:
+            rules_nav.append({maturity.replace('maturity_', '').capitalize() if maturity else 'Other': entries})
+    nav.append({'Rules': rules_nav})
+    for kind in ['macro', 'list']:
+        nav.append({'{}s'.format(kind.capitalize()): [{name: page_path(k, name)} for (k, name) in sorted(objs) if k == kind]})
+    return nav
+
+def update_mkdocs_nav(mkdocs_file, nav):
+    """
+    Replaces the navigation of mkdocs_file with the overview page followed by the given entries.
+    Only the top-level nav section is rewritten, so that the comments and the order of the
+    other settings are preserved.
+    """
+    with open(mkdocs_file, 'r') as f:
+        lines = f.read().splitlines(keepends=True)
+    section = yaml.safe_dump({'nav': [{'Home': 'index.md'}] + nav}, sort_keys=False, allow_unicode=True, default_flow_style=False)
+    start = next((i for i, line in enumerate(lines) if re.match(r'^nav\s*:', line)), None)
+    if start is None:
+        if lines and not lines[-1].endswith('\n'):
+            lines[-1] += '\n'
+        lines.append(section)
+    else:
+        end = start + 1
+        # the section ends at the next top-level key, keeping the blank and
+        # comment lines that precede it, whereas its items may not be indented
+        while end < len(lines) and not re.match(r'^[^\s#-]', lines[end]):
+            end += 1
+        while end > start + 1 and (not lines[end - 1].strip() or lines[end - 1].startswith('#')):
+            end -= 1
+        lines[start:end] = [section]
+    with open(mkdocs_file, 'w') as f:
+        f.writelines(lines)
+
 if __name__ == '__main__':
     args_parsed = arg_parser()
     rules_dir = args_parsed.rules_dir
@@ -158,3 +441,8 @@ if __name__ == '__main__':
         sys.exit('No valid rules directory provided via --rules_dir arg, exiting ...')
 
     print_markdown(rules_to_df(rules_dir))
+
+    if args_parsed.docs_dir:
+        nav = write_pages(rules_dir, args_parsed.docs_dir)
+        if args_parsed.mkdocs_file:
+            update_mkdocs_nav(args_parsed.mkdocs_file, nav)
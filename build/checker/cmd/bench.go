@@ -0,0 +1,487 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/falcosecurity/testing/pkg/falco"
+	"github.com/falcosecurity/testing/pkg/run"
+	"github.com/spf13/cobra"
+)
+
+// benchStatsRegex matches the event processing stats that Falco prints in
+// verbose mode when reading a capture file. The elapsed time is measured by
+// Falco as the CPU time spent processing events.
+var benchStatsRegex = regexp.MustCompile(`Elapsed time: ([0-9.]+), Captured Events: ([0-9]+)`)
+
+// benchMeasure is the outcome of replaying a capture file through Falco,
+// either with all the rules or with a single rule enabled in isolation.
+type benchMeasure struct {
+	Capture string `json:"capture"`
+	// Rule is empty when all the rules are enabled
+	Rule         string         `json:"rule,omitempty"`
+	Events       uint64         `json:"events"`
+	CPUSeconds   float64        `json:"cpu_seconds"`
+	EventsPerSec float64        `json:"events_per_sec"`
+	Alerts       map[string]int `json:"alerts"`
+	// Evaluations is the number of events the rule has been evaluated on,
+	// which is only known for the rules in isolation
+	Evaluations uint64 `json:"evaluations,omitempty"`
+}
+
+// Key identifies the measures to be compared across rules versions.
+func (m *benchMeasure) Key() string {
+	return m.Capture + "\n" + m.Rule
+}
+
+// parseBenchStats parses the output of a verbose Falco run on a capture file
+// and sets the events and CPU time of the measure.
+func parseBenchStats(m *benchMeasure, output string) error {
+	match := benchStatsRegex.FindStringSubmatch(output)
+	if match == nil {
+		return fmt.Errorf("can't find the event processing stats in the Falco output")
+	}
+	var err error
+	m.CPUSeconds, err = strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return err
+	}
+	m.Events, err = strconv.ParseUint(match[2], 10, 64)
+	if err != nil {
+		return err
+	}
+	if m.CPUSeconds > 0 {
+		m.EventsPerSec = float64(m.Events) / m.CPUSeconds
+	}
+	return nil
+}
+
+// benchRunner replays a capture file with the given rules files, enabling only
+// the given rule if not empty.
+type benchRunner func(rulesFiles []string, capture, rule string) (*benchMeasure, error)
+
+// benchCounter returns the number of events of the capture file of a measure
+// that its rule is evaluated on, loading the given rules files.
+type benchCounter func(rulesFiles []string, m *benchMeasure) (uint64, error)
+
+// benchEvaluationsRule is the name of the rule used to count the events of
+// a capture file that a rule is evaluated on.
+const benchEvaluationsRule = "Bench evaluations"
+
+// benchEvaluationsRulesFile returns a rules file with a single rule matching
+// all the events of the given event types. Falco only evaluates a rule on the
+// events of the event types of its condition, so the alerts of this rule are
+// the evaluations of any rule with the same event types.
+func benchEvaluationsRulesFile(eventTypes []string) string {
+	return fmt.Sprintf(`- rule: %s
+  desc: Matches the events a rule is evaluated on
+  condition: evt.type in (%s)
+  output: "%%evt.num"
+  priority: DEBUG
+`, benchEvaluationsRule, strings.Join(eventTypes, ", "))
+}
+
+// runFalcoBench replays a capture file through Falco with the given options,
+// in verbose mode and with the JSON output enabled.
+func runFalcoBench(falcoImage, falcoConfigPath string, falcoFilesPaths []string, capture string, options ...falco.TestOption) (*falco.TestOutput, error) {
+	options = append(options,
+		falco.WithOutputJSON(),
+		falco.WithCaptureFile(run.NewLocalFileAccessor(capture, capture)),
+		falco.WithArgs("-v"),
+	)
+	if len(falcoConfigPath) > 0 {
+		options = append(options, falco.WithConfig(run.NewLocalFileAccessor(falcoConfigPath, falcoConfigPath)))
+	}
+	for _, path := range falcoFilesPaths {
+		options = append(options, falco.WithExtraFiles(run.NewLocalFileAccessor(path, path)))
+	}
+
+	runner, err := run.NewDockerRunner(falcoImage, defaultFalcoDockerEntrypoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	res := falco.Test(runner, options...)
+	err = res.Err()
+	if res.ExitCode() != 0 {
+		err = errAppend(err, fmt.Errorf("unexpected exit code (%d)", res.ExitCode()))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("running falco: %s", err.Error())
+	}
+	return res, nil
+}
+
+func newFalcoBenchRunner(falcoImage, falcoConfigPath string, falcoFilesPaths []string) benchRunner {
+	return func(rulesFiles []string, capture, rule string) (*benchMeasure, error) {
+		var options []falco.TestOption
+		if len(rule) > 0 {
+			options = append(options, falco.WithArgs("-o", "rules[].disable.rule=*", "-o", "rules[].enable.rule="+rule))
+		}
+		for _, rf := range rulesFiles {
+			options = append(options, falco.WithRules(run.NewLocalFileAccessor(rf, rf)))
+		}
+		res, err := runFalcoBench(falcoImage, falcoConfigPath, falcoFilesPaths, capture, options...)
+		if err != nil {
+			return nil, err
+		}
+
+		m := &benchMeasure{Capture: capture, Rule: rule, Alerts: make(map[string]int)}
+		if err := parseBenchStats(m, res.Stderr()+res.Stdout()); err != nil {
+			return nil, err
+		}
+		for _, a := range res.Detections() {
+			m.Alerts[a.Rule]++
+		}
+		return m, nil
+	}
+}
+
+// newFalcoBenchCounter returns a counter that replays a capture file with a
+// rule matching the event types of the counted rule. Rules that are not
+// restricted to some event types are evaluated on all the captured events.
+func newFalcoBenchCounter(falcoImage, falcoConfigPath string, falcoFilesPaths []string) benchCounter {
+	return func(rulesFiles []string, m *benchMeasure) (uint64, error) {
+		rs, err := loadRuleset(rulesFiles...)
+		if err != nil {
+			return 0, err
+		}
+		r := rs.Rule(m.Rule)
+		if r == nil {
+			return 0, fmt.Errorf("no rule found with name `%s`", m.Rule)
+		}
+		eventTypes := rulesetEntryEventTypes(rs, r)
+		if r.RuleSource() != defaultRuleSource || eventTypes == nil {
+			return m.Events, nil
+		}
+
+		rf := run.NewStringFileAccessor("bench_evaluations.yaml", benchEvaluationsRulesFile(eventTypes))
+		res, err := runFalcoBench(falcoImage, falcoConfigPath, falcoFilesPaths, m.Capture, falco.WithRules(rf))
+		if err != nil {
+			return 0, err
+		}
+		return uint64(res.Detections().OfRule(benchEvaluationsRule).Count()), nil
+	}
+}
+
+// runBench replays each capture file with all the rules, and then with each
+// of the given rules in isolation, counting the events each of them is
+// evaluated on. Each measure is repeated the given number of times, keeping
+// the run with the lowest CPU time to reduce noise.
+func runBench(runner benchRunner, counter benchCounter, rulesFiles, captures, isolated []string, repeat int) ([]*benchMeasure, error) {
+	var res []*benchMeasure
+	for _, capture := range captures {
+		for _, rule := range append([]string{""}, isolated...) {
+			var best *benchMeasure
+			for i := 0; i < repeat; i++ {
+				m, err := runner(rulesFiles, capture, rule)
+				if err != nil {
+					if len(rule) > 0 {
+						return nil, fmt.Errorf("%s (rule `%s`): %s", capture, rule, err.Error())
+					}
+					return nil, fmt.Errorf("%s: %s", capture, err.Error())
+				}
+				if best == nil || m.CPUSeconds < best.CPUSeconds {
+					best = m
+				}
+			}
+			if len(rule) > 0 {
+				n, err := counter(rulesFiles, best)
+				if err != nil {
+					return nil, fmt.Errorf("%s (rule `%s`): %s", capture, rule, err.Error())
+				}
+				best.Evaluations = n
+			}
+			res = append(res, best)
+		}
+	}
+	return res, nil
+}
+
+// benchRegressions returns the measures of the right-hand side whose CPU time
+// increased more than the given percentage with respect to the left-hand side.
+func benchRegressions(left, right []*benchMeasure, maxPerc float64) []string {
+	prev := make(map[string]*benchMeasure)
+	for _, m := range left {
+		prev[m.Key()] = m
+	}
+	var res []string
+	for _, m := range right {
+		l, ok := prev[m.Key()]
+		if !ok || l.CPUSeconds == 0 {
+			continue
+		}
+		delta := (m.CPUSeconds - l.CPUSeconds) * 100 / l.CPUSeconds
+		if delta > maxPerc {
+			what := "all rules"
+			if len(m.Rule) > 0 {
+				what = fmt.Sprintf("rule `%s`", m.Rule)
+			}
+			res = append(res, fmt.Sprintf("CPU time of %s on %s increased by %.1f%%", what, filepath.Base(m.Capture), delta))
+		}
+	}
+	return res
+}
+
+func formatBenchDelta(left, right float64) string {
+	if left == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", (right-left)*100/left)
+}
+
+// formatBenchEvaluations formats the evaluations of a measure, which are not
+// counted when all the rules are enabled.
+func formatBenchEvaluations(m *benchMeasure) string {
+	if len(m.Rule) == 0 {
+		return "-"
+	}
+	return strconv.FormatUint(m.Evaluations, 10)
+}
+
+// writeBenchReport writes a Markdown table for each capture file, comparing
+// the right-hand side measures with the left-hand side ones if any.
+func writeBenchReport(w io.Writer, left, right []*benchMeasure) {
+	prev := make(map[string]*benchMeasure)
+	for _, m := range left {
+		prev[m.Key()] = m
+	}
+	capture := ""
+	for _, m := range right {
+		if m.Capture != capture {
+			if len(capture) > 0 {
+				fmt.Fprintln(w)
+			}
+			capture = m.Capture
+			fmt.Fprintf(w, "## %s\n\n", capture)
+			if len(left) > 0 {
+				fmt.Fprintln(w, "| Rules | Events | Evaluations | Events/sec | CPU time | Alerts | Evaluations (before) | Events/sec (before) | CPU time (before) | Alerts (before) | CPU time change |")
+				fmt.Fprintln(w, "|-------|--------|-------------|------------|----------|--------|----------------------|---------------------|-------------------|-----------------|-----------------|")
+			} else {
+				fmt.Fprintln(w, "| Rules | Events | Evaluations | Events/sec | CPU time | Alerts |")
+				fmt.Fprintln(w, "|-------|--------|-------------|------------|----------|--------|")
+			}
+		}
+		name := "all"
+		if len(m.Rule) > 0 {
+			name = "`" + m.Rule + "`"
+		}
+		fmt.Fprintf(w, "| %s | %d | %s | %.0f | %.3fs | %d |", name, m.Events, formatBenchEvaluations(m), m.EventsPerSec, m.CPUSeconds, countBenchAlerts(m))
+		if len(left) > 0 {
+			if l, ok := prev[m.Key()]; ok {
+				fmt.Fprintf(w, " %s | %.0f | %.3fs | %d | %s |", formatBenchEvaluations(l), l.EventsPerSec, l.CPUSeconds, countBenchAlerts(l), formatBenchDelta(l.CPUSeconds, m.CPUSeconds))
+			} else {
+				fmt.Fprint(w, " - | - | - | - | - |")
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	// alerts by rule, for the runs with all the rules enabled
+	for _, m := range right {
+		if len(m.Rule) > 0 {
+			continue
+		}
+		var rules []string
+		for r := range m.Alerts {
+			rules = append(rules, r)
+		}
+		if l, ok := prev[m.Key()]; ok {
+			for r := range l.Alerts {
+				if _, ok := m.Alerts[r]; !ok {
+					rules = append(rules, r)
+				}
+			}
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		sort.Strings(rules)
+		fmt.Fprintf(w, "\n**Alerts** by rule on %s:\n", m.Capture)
+		for _, r := range rules {
+			if l, ok := prev[m.Key()]; ok {
+				fmt.Fprintf(w, "* `%s`: %d (before: %d)\n", r, m.Alerts[r], l.Alerts[r])
+			} else {
+				fmt.Fprintf(w, "* `%s`: %d\n", r, m.Alerts[r])
+			}
+		}
+	}
+}
+
+func countBenchAlerts(m *benchMeasure) int {
+	res := 0
+	for _, n := range m.Alerts {
+		res += n
+	}
+	return res
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Benchmark rules files by replaying capture files through Falco, comparing two versions of them",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		leftRules, err := cmd.Flags().GetStringArray("left")
+		if err != nil {
+			return err
+		}
+
+		rightRules, err := cmd.Flags().GetStringArray("right")
+		if err != nil {
+			return err
+		}
+
+		if len(rightRules) == 0 {
+			return fmt.Errorf("you must specify at least one rules file for the right-hand side of the benchmark")
+		}
+
+		captures, err := cmd.Flags().GetStringArray("capture")
+		if err != nil {
+			return err
+		}
+
+		if len(captures) == 0 {
+			return fmt.Errorf("you must specify at least one capture file")
+		}
+
+		falcoImage, err := cmd.Flags().GetString("falco-image")
+		if err != nil {
+			return err
+		}
+
+		falcoConfigPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		falcoFilesPaths, err := cmd.Flags().GetStringArray("file")
+		if err != nil {
+			return err
+		}
+
+		isolate, err := cmd.Flags().GetBool("isolate")
+		if err != nil {
+			return err
+		}
+
+		ruleNames, err := cmd.Flags().GetStringArray("rule-name")
+		if err != nil {
+			return err
+		}
+
+		repeat, err := cmd.Flags().GetInt("repeat")
+		if err != nil {
+			return err
+		}
+
+		if repeat < 1 {
+			return fmt.Errorf("the number of repetitions must be at least 1")
+		}
+
+		maxRegression, err := cmd.Flags().GetFloat64("max-regression")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		// with --isolate and no explicit rule names, all the rules of the
+		// right-hand side are benchmarked in isolation
+		if isolate && len(ruleNames) == 0 {
+			rs, err := loadRuleset(rightRules...)
+			if err != nil {
+				return err
+			}
+			for _, r := range rs.Rules {
+				ruleNames = append(ruleNames, r.Rule)
+			}
+		}
+
+		runner := newFalcoBenchRunner(falcoImage, falcoConfigPath, falcoFilesPaths)
+		counter := newFalcoBenchCounter(falcoImage, falcoConfigPath, falcoFilesPaths)
+		var left []*benchMeasure
+		if len(leftRules) > 0 {
+			rs, err := loadRuleset(leftRules...)
+			if err != nil {
+				return err
+			}
+			var leftNames []string
+			for _, name := range ruleNames {
+				if rs.Rule(name) != nil {
+					leftNames = append(leftNames, name)
+				}
+			}
+			left, err = runBench(runner, counter, leftRules, captures, leftNames, repeat)
+			if err != nil {
+				return err
+			}
+		}
+		right, err := runBench(runner, counter, rightRules, captures, ruleNames, repeat)
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case "text":
+			writeBenchReport(cmd.OutOrStdout(), left, right)
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			out := struct {
+				Left  []*benchMeasure `json:"left,omitempty"`
+				Right []*benchMeasure `json:"right"`
+			}{left, right}
+			if err := enc.Encode(out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+
+		if maxRegression > 0 {
+			for _, r := range benchRegressions(left, right, maxRegression) {
+				err = errAppend(err, fmt.Errorf("%s", r))
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	benchCmd.Flags().StringP("falco-image", "i", defaultFalcoDockerImage, "Docker image of Falco to be used for the benchmark")
+	benchCmd.Flags().StringP("config", "c", "", "Config file to be used for running Falco")
+	benchCmd.Flags().StringArrayP("file", "f", []string{}, "Extra files required by Falco for running")
+	benchCmd.Flags().StringArrayP("left", "l", []string{}, "Rules files to be loaded for the left-hand side of the comparison")
+	benchCmd.Flags().StringArrayP("right", "r", []string{}, "Rules files to be loaded for the right-hand side of the comparison")
+	benchCmd.Flags().StringArray("capture", []string{}, "Capture files to be replayed")
+	benchCmd.Flags().Bool("isolate", false, "Also benchmark each rule in isolation, with all the other rules disabled, and count the events it is evaluated on")
+	benchCmd.Flags().StringArray("rule-name", []string{}, "Rules to be benchmarked in isolation (defaults to all the rules with --isolate)")
+	benchCmd.Flags().Int("repeat", 1, "Number of runs of each benchmark, keeping the one with the lowest CPU time")
+	benchCmd.Flags().Float64("max-regression", 0, "Fail if the CPU time of the right-hand side increases more than the given percentage (0 to disable)")
+	benchCmd.Flags().StringP("output", "o", "text", "Output format, either text or json")
+	rootCmd.AddCommand(benchCmd)
+}
@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBenchStats(t *testing.T) {
+	t.Parallel()
+
+	var m benchMeasure
+	out := "Driver Events:1000\nDriver Drops:0\nElapsed time: 0.250, Captured Events: 1000, 4000.00 eps\n"
+	require.NoError(t, parseBenchStats(&m, out))
+	assert.Equal(t, uint64(1000), m.Events)
+	assert.Equal(t, 0.25, m.CPUSeconds)
+	assert.Equal(t, 4000.0, m.EventsPerSec)
+
+	assert.Error(t, parseBenchStats(&m, "Events detected: 0\n"))
+}
+
+func TestRunBench(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	runner := func(rulesFiles []string, capture, rule string) (*benchMeasure, error) {
+		calls++
+		if rule == "broken" {
+			return nil, fmt.Errorf("exit code 1")
+		}
+		cpu := 1.0
+		if calls%2 == 0 {
+			cpu = 0.5
+		}
+		return &benchMeasure{Capture: capture, Rule: rule, Events: 10, CPUSeconds: cpu}, nil
+	}
+
+	counted := 0
+	counter := func(rulesFiles []string, m *benchMeasure) (uint64, error) {
+		counted++
+		if m.Rule == "uncountable" {
+			return 0, fmt.Errorf("no rule found")
+		}
+		return m.Events / 2, nil
+	}
+
+	res, err := runBench(runner, counter, []string{"a.yaml"}, []string{"a.scap", "b.scap"}, []string{"r1"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 8, calls)
+	// evaluations are counted once for each rule in isolation
+	assert.Equal(t, 2, counted)
+	require.Len(t, res, 4)
+	for _, m := range res {
+		assert.Equal(t, 0.5, m.CPUSeconds)
+	}
+	assert.Equal(t, "", res[0].Rule)
+	assert.Equal(t, uint64(0), res[0].Evaluations)
+	assert.Equal(t, "r1", res[1].Rule)
+	assert.Equal(t, uint64(5), res[1].Evaluations)
+	assert.Equal(t, "b.scap", res[2].Capture)
+
+	_, err = runBench(runner, counter, []string{"a.yaml"}, []string{"a.scap"}, []string{"broken"}, 1)
+	assert.EqualError(t, err, "a.scap (rule `broken`): exit code 1")
+	_, err = runBench(runner, counter, []string{"a.yaml"}, []string{"a.scap"}, []string{"uncountable"}, 1)
+	assert.EqualError(t, err, "a.scap (rule `uncountable`): no rule found")
+}
+
+func TestBenchEvaluationsRulesFile(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, benchEvaluationsRulesFile([]string{"open", "openat"}))
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+	r := rs.Rule(benchEvaluationsRule)
+	require.NotNil(t, r)
+	assert.Equal(t, []string{"open", "openat"}, rulesetEntryEventTypes(rs, r))
+	assert.Equal(t, "%evt.num", r.Output)
+}
+
+func TestBenchRegressions(t *testing.T) {
+	t.Parallel()
+
+	left := []*benchMeasure{
+		{Capture: "a.scap", CPUSeconds: 1},
+		{Capture: "a.scap", Rule: "r1", CPUSeconds: 0.1},
+	}
+	right := []*benchMeasure{
+		{Capture: "a.scap", CPUSeconds: 1.05},
+		{Capture: "a.scap", Rule: "r1", CPUSeconds: 0.2},
+		{Capture: "a.scap", Rule: "r2", CPUSeconds: 0.3},
+	}
+	assert.Equal(t, []string{"CPU time of rule `r1` on a.scap increased by 100.0%"}, benchRegressions(left, right, 10))
+	assert.Len(t, benchRegressions(left, right, 1), 2)
+}
+
+func TestWriteBenchReport(t *testing.T) {
+	t.Parallel()
+
+	left := []*benchMeasure{
+		{Capture: "a.scap", Events: 100, CPUSeconds: 1, EventsPerSec: 100, Alerts: map[string]int{"r1": 2, "r2": 1}},
+	}
+	right := []*benchMeasure{
+		{Capture: "a.scap", Events: 100, CPUSeconds: 0.5, EventsPerSec: 200, Alerts: map[string]int{"r1": 3}},
+		{Capture: "a.scap", Rule: "r1", Events: 100, CPUSeconds: 0.1, EventsPerSec: 1000, Alerts: map[string]int{"r1": 3}, Evaluations: 40},
+	}
+
+	var b bytes.Buffer
+	writeBenchReport(&b, left, right)
+	assert.Contains(t, b.String(), "## a.scap\n")
+	assert.Contains(t, b.String(), "| all | 100 | - | 200 | 0.500s | 3 | - | 100 | 1.000s | 3 | -50.0% |\n")
+	assert.Contains(t, b.String(), "| `r1` | 100 | 40 | 1000 | 0.100s | 3 | - | - | - | - | - |\n")
+	assert.Contains(t, b.String(), "* `r1`: 3 (before: 2)\n* `r2`: 0 (before: 1)\n")
+
+	b.Reset()
+	writeBenchReport(&b, nil, right)
+	assert.Contains(t, b.String(), "| all | 100 | - | 200 | 0.500s | 3 |\n")
+	assert.Contains(t, b.String(), "* `r1`: 3\n")
+}
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// prNumberRegex matches the pull request number of squashed or merge commits.
+var prNumberRegex = regexp.MustCompile(`(?:\(#([0-9]+)\)$|^Merge pull request #([0-9]+))`)
+
+// changelogCommit is a commit changing a rules file.
+type changelogCommit struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	PR      int    `json:"pr,omitempty"`
+	// Objects contains the ids of the lists, macros, and rules changed by the
+	// commit, as returned by depGraphNodeID
+	Objects []string `json:"-"`
+}
+
+// Ref returns a short reference to the commit, either its pull request
+// number or its abbreviated hash.
+func (c *changelogCommit) Ref() string {
+	if c.PR > 0 {
+		return fmt.Sprintf("#%d", c.PR)
+	}
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+	return c.Hash
+}
+
+// changelogEntry is the change of a list, macro, or rule between two
+// revisions of a rules file.
+type changelogEntry struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Status is either added, removed, or changed
+	Status         string             `json:"status"`
+	Classification string             `json:"classification,omitempty"`
+	Details        []string           `json:"details,omitempty"`
+	Commits        []*changelogCommit `json:"commits,omitempty"`
+}
+
+// changelog is a section of the changelog of a rules file, with its changes
+// grouped by category.
+type changelog struct {
+	Rulesfile string            `json:"rulesfile"`
+	From      string            `json:"from"`
+	To        string            `json:"to"`
+	Breaking  []*changelogEntry `json:"breaking"`
+	NewRules  []*changelogEntry `json:"new_rules"`
+	Tuning    []*changelogEntry `json:"tuning"`
+	Removed   []*changelogEntry `json:"removed"`
+	// Requirements contains the changes of the engine and plugin version
+	// requirements reported by compare
+	Requirements []string `json:"requirements"`
+}
+
+// rulesetChanges returns the lists, macros, and rules that have been added,
+// removed, or changed between two rulesets.
+func rulesetChanges(before, after *ruleset) []*changelogEntry {
+	var res []*changelogEntry
+	for _, kind := range []string{itemKindRule, itemKindMacro, itemKindList} {
+		afterEntries, _ := after.entries(kind)
+		for _, r := range *afterEntries {
+			l := rulesetEntryOf(before, kind, r.Name())
+			if l == nil {
+				res = append(res, &changelogEntry{Kind: kind, Name: r.Name(), Status: "added"})
+			} else if d := rulesetEntryDiff(l, r); len(d) > 0 {
+				res = append(res, &changelogEntry{Kind: kind, Name: r.Name(), Status: "changed", Details: d})
+			}
+		}
+		beforeEntries, _ := before.entries(kind)
+		for _, l := range *beforeEntries {
+			if rulesetEntryOf(after, kind, l.Name()) == nil {
+				res = append(res, &changelogEntry{Kind: kind, Name: l.Name(), Status: "removed"})
+			}
+		}
+	}
+	return res
+}
+
+// buildChangelog categorizes the changes between two revisions of a rules
+// file, attributing them to the commits that changed the same objects.
+func buildChangelog(rulesfile, fromRef, toRef string, from, to *ruleset, commits []*changelogCommit) *changelog {
+	res := &changelog{Rulesfile: rulesfile, From: fromRef, To: toRef}
+	fromDesc, toDesc := rulesetDescription(from), rulesetDescription(to)
+	for _, e := range rulesetChanges(from, to) {
+		e.Classification = compareClassification(fromDesc, toDesc, e.Kind, e.Name)
+		id := depGraphNodeID(e.Kind, e.Name)
+		for _, c := range commits {
+			if strSliceContains(c.Objects, id) {
+				e.Commits = append(e.Commits, c)
+			}
+		}
+		switch {
+		case e.Status == "removed":
+			res.Removed = append(res.Removed, e)
+		case e.Status == "added" && e.Kind == itemKindRule:
+			res.NewRules = append(res.NewRules, e)
+		case e.Classification == "major":
+			res.Breaking = append(res.Breaking, e)
+		default:
+			res.Tuning = append(res.Tuning, e)
+		}
+	}
+
+	for _, diff := range [][]string{compareRulesMajor(fromDesc, toDesc), compareRulesMinor(fromDesc, toDesc), compareRulesPatch(fromDesc, toDesc)} {
+		for _, d := range diff {
+			if strings.HasPrefix(d, "Required engine version") || strings.HasPrefix(d, "Version dependency") {
+				res.Requirements = append(res.Requirements, d)
+			}
+		}
+	}
+	return res
+}
+
+func writeChangelog(w io.Writer, c *changelog) {
+	fmt.Fprintf(w, "## %s\n\n", c.To)
+	fmt.Fprintf(w, "Changes of %s since %s.\n\n", c.Rulesfile, c.From)
+	empty := true
+	for _, section := range []struct {
+		title   string
+		entries []*changelogEntry
+	}{
+		{"Breaking changes", c.Breaking},
+		{"New rules", c.NewRules},
+		{"Tuning", c.Tuning},
+		{"Removed", c.Removed},
+	} {
+		if len(section.entries) == 0 {
+			continue
+		}
+		empty = false
+		fmt.Fprintf(w, "### %s\n\n", section.title)
+		for _, e := range section.entries {
+			line := fmt.Sprintf("* %s `%s` has been %s", capitalize(e.Kind), e.Name, e.Status)
+			var refs []string
+			for _, commit := range e.Commits {
+				if !strSliceContains(refs, commit.Ref()) {
+					refs = append(refs, commit.Ref())
+				}
+			}
+			if len(refs) > 0 {
+				line += " (" + strings.Join(refs, ", ") + ")"
+			}
+			fmt.Fprintln(w, line)
+			for _, d := range e.Details {
+				fmt.Fprintln(w, "  * "+d)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	if len(c.Requirements) > 0 {
+		empty = false
+		fmt.Fprintf(w, "### Requirements\n\n")
+		for _, r := range c.Requirements {
+			fmt.Fprintln(w, "* "+r)
+		}
+		fmt.Fprintln(w)
+	}
+	if empty {
+		fmt.Fprintln(w, "No changes detected")
+	}
+}
+
+// loadChangelogCommits returns the commits changing a rules file between two
+// git revisions, along with the objects each of them changed.
+func loadChangelogCommits(from, to, path string) ([]*changelogCommit, error) {
+	out, err := runGit("log", "--reverse", "--format=%H%x1f%P%x1f%an%x1f%cs%x1f%s", from+".."+to, "--", path)
+	if err != nil {
+		return nil, err
+	}
+	var res []*changelogCommit
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		fields := strings.Split(line, "\x1f")
+		if len(fields) != 5 {
+			continue
+		}
+		c := &changelogCommit{Hash: fields[0], Author: fields[2], Date: fields[3], Subject: fields[4]}
+		if m := prNumberRegex.FindStringSubmatch(c.Subject); m != nil {
+			c.PR, _ = strconv.Atoi(m[1] + m[2])
+		}
+
+		before := newRuleset()
+		if parents := strings.Fields(fields[1]); len(parents) > 0 {
+			if before, err = loadRulesetAt(parents[0], path); err != nil {
+				return nil, err
+			}
+		}
+		after, err := loadRulesetAt(c.Hash, path)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range rulesetChanges(before, after) {
+			c.Objects = append(c.Objects, depGraphNodeID(e.Kind, e.Name))
+		}
+		res = append(res, c)
+	}
+	return res, nil
+}
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Generate a categorized changelog section of a rules file between two git revisions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return err
+		}
+
+		if len(from) == 0 {
+			return fmt.Errorf("you must specify the revision to generate the changelog from")
+		}
+
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+
+		name, err := cmd.Flags().GetString("rulesfile")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		if len(name) == 0 {
+			n, _, ok := parseReleaseTag(from)
+			if !ok {
+				return fmt.Errorf("you must specify the rules file if '%s' is not a release tag", from)
+			}
+			name = n
+		}
+
+		var rulesets []*ruleset
+		var path string
+		for _, rev := range []string{from, to} {
+			p, ok, err := rulesfilePathAt(registryPath, name, rev)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return fmt.Errorf("no rules file named '%s' in registry at '%s'", name, rev)
+			}
+			rs, err := loadRulesetAt(rev, p)
+			if err != nil {
+				return err
+			}
+			rulesets = append(rulesets, rs)
+			path = p
+		}
+
+		commits, err := loadChangelogCommits(from, to, path)
+		if err != nil {
+			return err
+		}
+
+		c := buildChangelog(name, from, to, rulesets[0], rulesets[1], commits)
+		switch format {
+		case "text":
+			writeChangelog(cmd.OutOrStdout(), c)
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(c)
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+		return nil
+	},
+}
+
+func init() {
+	changelogCmd.Flags().String("from", "", "Git revision of the previous release, such as falco-rules-1.0.0")
+	changelogCmd.Flags().String("to", "HEAD", "Git revision of the new release")
+	changelogCmd.Flags().String("rulesfile", "", "Name of the rules file of the registry (defaults to the one of the release tag)")
+	changelogCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	changelogCmd.Flags().StringP("output", "o", "text", "Output format, one of: text (Markdown), json")
+	rootCmd.AddCommand(changelogCmd)
+}
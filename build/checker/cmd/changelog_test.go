@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildChangelog(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, `
+- required_engine_version: 13
+- list: l1
+  items: [a]
+- macro: m1
+  condition: evt.type = open
+- rule: r1
+  desc: rule 1
+  condition: m1 and proc.name in (l1)
+  output: out
+  priority: WARNING
+- rule: r2
+  desc: rule 2
+  condition: m1
+  output: out
+  priority: WARNING
+`, `
+- required_engine_version: 14
+- list: l1
+  items: [a, b]
+- macro: m1
+  condition: evt.type = open
+- rule: r1
+  desc: rule 1
+  condition: m1 and proc.name in (l1)
+  output: out
+  priority: NOTICE
+- rule: r3
+  desc: rule 3
+  condition: m1
+  output: out
+  priority: WARNING
+`)
+	from := newRuleset()
+	require.NoError(t, from.Add(files[0]))
+	to := newRuleset()
+	require.NoError(t, to.Add(files[1]))
+
+	commits := []*changelogCommit{
+		{Hash: "0123456789", Subject: "update rules", Objects: []string{"rule:r1", "rule:r3"}},
+		{Hash: "abcdef0123", Subject: "new: add r3 (#12)", PR: 12, Objects: []string{"rule:r3", "list:l1"}},
+	}
+	c := buildChangelog("falco-rules", "falco-rules-1.0.0", "falco-rules-2.0.0", from, to, commits)
+	require.Len(t, c.Breaking, 1)
+	assert.Equal(t, "r1", c.Breaking[0].Name)
+	assert.Equal(t, []string{"Priority changed from `WARNING` to `NOTICE`"}, c.Breaking[0].Details)
+	require.Len(t, c.NewRules, 1)
+	assert.Equal(t, "r3", c.NewRules[0].Name)
+	require.Len(t, c.Tuning, 1)
+	assert.Equal(t, "l1", c.Tuning[0].Name)
+	assert.Equal(t, "patch", c.Tuning[0].Classification)
+	require.Len(t, c.Removed, 1)
+	assert.Equal(t, "r2", c.Removed[0].Name)
+	assert.Equal(t, []string{"Required engine version was incremented from 13 to 14"}, c.Requirements)
+
+	var buf bytes.Buffer
+	writeChangelog(&buf, c)
+	assert.Equal(t, `## falco-rules-2.0.0
+
+Changes of falco-rules since falco-rules-1.0.0.
+
+### Breaking changes
+
+* Rule `+"`r1`"+` has been changed (0123456)
+  * Priority changed from `+"`WARNING` to `NOTICE`"+`
+
+### New rules
+
+* Rule `+"`r3`"+` has been added (0123456, #12)
+
+### Tuning
+
+* List `+"`l1`"+` has been changed (#12)
+  * Items added: `+"`b`"+`
+
+### Removed
+
+* Rule `+"`r2`"+` has been removed
+
+### Requirements
+
+* Required engine version was incremented from 13 to 14
+
+`, buf.String())
+}
+
+func TestBuildChangelogEventTypes(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, `
+- macro: open_events
+  condition: evt.type in (open, openat)
+- rule: r1
+  desc: rule 1
+  condition: evt.type in (open, openat) and proc.name = cat
+  output: "%proc.name"
+  priority: WARNING
+- rule: r2
+  desc: rule 2
+  condition: evt.type = execve
+  output: "%proc.name"
+  priority: WARNING
+`, `
+- macro: open_events
+  condition: evt.type = openat
+- rule: r1
+  desc: rule 1
+  condition: evt.type = openat and proc.name = cat
+  output: "%proc.name"
+  priority: WARNING
+- rule: r2
+  desc: rule 2
+  condition: evt.type in (execve, execveat)
+  output: "%proc.name %proc.cmdline"
+  priority: WARNING
+`)
+	from := newRuleset()
+	require.NoError(t, from.Add(files[0]))
+	to := newRuleset()
+	require.NoError(t, to.Add(files[1]))
+
+	// narrowing the event types of a macro or rule is breaking, whereas
+	// matching more events and adding output fields is not
+	c := buildChangelog("falco-rules", "falco-rules-1.0.0", "falco-rules-2.0.0", from, to, nil)
+	var breaking []string
+	for _, e := range c.Breaking {
+		breaking = append(breaking, e.Kind+":"+e.Name)
+	}
+	assert.ElementsMatch(t, []string{"macro:open_events", "rule:r1"}, breaking)
+	require.Len(t, c.Tuning, 1)
+	assert.Equal(t, "r2", c.Tuning[0].Name)
+	assert.Equal(t, "patch", c.Tuning[0].Classification)
+}
+
+func TestPRNumberRegex(t *testing.T) {
+	t.Parallel()
+
+	m := prNumberRegex.FindStringSubmatch("update(rules): tune r1 (#123)")
+	require.NotNil(t, m)
+	assert.Equal(t, "123", m[1]+m[2])
+	m = prNumberRegex.FindStringSubmatch("Merge pull request #45 from user/branch")
+	require.NotNil(t, m)
+	assert.Equal(t, "45", m[1]+m[2])
+	assert.Nil(t, prNumberRegex.FindStringSubmatch("fix #12 in rules"))
+}
@@ -20,6 +20,8 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"sort"
 	"strconv"
 
 	"github.com/blang/semver"
@@ -194,7 +196,7 @@ func compareRulesPatch(left, right *falco.RulesetDescription) (res []string) {
 				}
 
 				// Matching more events in a rule condition
-				if len(diffStrSet(r.Details.Events, l.Details.Events)) > 0 {
+				if !eventTypesSubset(r.Details.Events, l.Details.Events) {
 					res = append(res, fmt.Sprintf("Rule `%s` matches more events than before", l.Info.Name))
 				}
 
@@ -352,7 +354,7 @@ func compareRulesMajor(left, right *falco.RulesetDescription) (res []string) {
 				}
 
 				// Matching less events in a rule condition
-				if len(diffStrSet(l.Details.Events, r.Details.Events)) > 0 {
+				if !eventTypesSubset(l.Details.Events, r.Details.Events) {
 					res = append(res, fmt.Sprintf("Rule `%s` matches less events than before", l.Info.Name))
 				}
 
@@ -373,8 +375,8 @@ func compareRulesMajor(left, right *falco.RulesetDescription) (res []string) {
 		for _, r := range right.Macros {
 			if l.Info.Name == r.Info.Name {
 				// Matching different events in a macro condition
-				if len(diffStrSet(l.Details.Events, r.Details.Events)) > 0 ||
-					len(diffStrSet(r.Details.Events, l.Details.Events)) > 0 {
+				if !eventTypesSubset(l.Details.Events, r.Details.Events) ||
+					!eventTypesSubset(r.Details.Events, l.Details.Events) {
 					res = append(res, fmt.Sprintf("Macro `%s` matches different events than before", l.Info.Name))
 				}
 			}
@@ -383,6 +385,122 @@ func compareRulesMajor(left, right *falco.RulesetDescription) (res []string) {
 	return
 }
 
+// itemsDescription describes the lists, macros, and rules defined by the
+// given items similarly to the Falco `-L` option, without the details that
+// require compiling them.
+func itemsDescription(items []*rulesFileItem) *falco.RulesetDescription {
+	res := &falco.RulesetDescription{}
+	for _, item := range items {
+		switch item.Kind {
+		case itemKindEngine:
+			res.RequiredEngineVersion = item.RequiredEngineVersion
+		case itemKindList:
+			res.Lists = append(res.Lists, falco.ListDescription{
+				Info: falco.ListInfoDescription{Name: item.List, Items: item.Items},
+			})
+		case itemKindMacro:
+			res.Macros = append(res.Macros, falco.MacroDescription{
+				Info: falco.MacroInfoDescription{Name: item.Macro, Condition: item.Condition},
+			})
+		case itemKindRule:
+			var exceptions []string
+			for _, e := range item.Exceptions {
+				exceptions = append(exceptions, e.Name)
+			}
+			res.Rules = append(res.Rules, falco.RuleDescription{
+				Info: falco.RuleInfoDescription{
+					Name:        item.Rule,
+					Condition:   item.Condition,
+					Description: item.Desc,
+					Enabled:     item.IsEnabled(),
+					Output:      item.Output,
+					Priority:    item.Priority,
+					Source:      item.RuleSource(),
+					Tags:        item.Tags,
+				},
+				Details: falco.RuleDetailsDescription{ExceptionNames: exceptions},
+			})
+		}
+	}
+	return res
+}
+
+// rulesFileDescription describes the lists, macros, and rules defined in a
+// rules file, ignoring the items appending to or overriding other ones.
+func rulesFileDescription(f *rulesFile) *falco.RulesetDescription {
+	var items []*rulesFileItem
+	for _, item := range f.Items {
+		if !item.IsOverride() {
+			items = append(items, item)
+		}
+	}
+	return itemsDescription(items)
+}
+
+// rulesetDescription describes the lists, macros, and rules of a ruleset,
+// with all the appends and overrides applied. Unlike itemsDescription, it
+// also describes the event types of the macros and rules, and the output
+// fields of the rules. The event types are nil if the condition can match
+// all of them, or if it can't be expanded.
+func rulesetDescription(rs *ruleset) *falco.RulesetDescription {
+	items := []*rulesFileItem{{Kind: itemKindEngine, RequiredEngineVersion: rs.RequiredEngineVersion}}
+	for _, entries := range [][]*rulesetEntry{rs.Lists, rs.Macros, rs.Rules} {
+		for _, e := range entries {
+			items = append(items, e.rulesFileItem)
+		}
+	}
+	res := itemsDescription(items)
+	for i, m := range rs.Macros {
+		res.Macros[i].Details.Events = rulesetEntryEventTypes(rs, m)
+	}
+	for i, r := range rs.Rules {
+		if r.RuleSource() == defaultRuleSource {
+			res.Rules[i].Details.Events = rulesetEntryEventTypes(rs, r)
+		}
+		for f := range outputFieldRefs(r.Output) {
+			res.Rules[i].Details.OutputFields = append(res.Rules[i].Details.OutputFields, f)
+		}
+		sort.Strings(res.Rules[i].Details.OutputFields)
+	}
+	return res
+}
+
+// rulesetEntryEventTypes returns the sorted event types a macro or rule of a
+// ruleset applies to, or nil if it applies to all of them.
+func rulesetEntryEventTypes(rs *ruleset, e *rulesetEntry) []string {
+	c, err := rs.ExpandCondition(e)
+	if err != nil || !restrictsEventTypes(c, false) {
+		return nil
+	}
+	res := conditionEventTypes(c)
+	sort.Strings(res)
+	return res
+}
+
+// writeCompareResult writes the major, minor, and patch changes between two
+// rulesets, and returns true if any change was found.
+func writeCompareResult(w io.Writer, left, right *falco.RulesetDescription) bool {
+	found := false
+	for _, c := range []struct {
+		title string
+		diff  []string
+	}{
+		{"**Major** changes:", compareRulesMajor(left, right)},
+		{"**Minor** changes:", compareRulesMinor(left, right)},
+		{"**Patch** changes:", compareRulesPatch(left, right)},
+	} {
+		if len(c.diff) > 0 {
+			found = true
+			fmt.Fprintln(w, c.title)
+			for _, s := range c.diff {
+				fmt.Fprintln(w, "* "+s)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	return found
+}
+
 var compareCmd = &cobra.Command{
 	Use: "compare",
 	// todo: load more than one rules files both on left and right
@@ -427,34 +545,100 @@ var compareCmd = &cobra.Command{
 			return err
 		}
 
-		diff := compareRulesMajor(leftOutput, rightOutput)
-		if len(diff) > 0 {
-			fmt.Fprintln(cmd.OutOrStdout(), "**Major** changes:")
-			for _, s := range diff {
-				fmt.Fprintln(cmd.OutOrStdout(), "* "+s)
-			}
-			fmt.Fprintln(cmd.OutOrStdout())
+		testsPaths, err := cmd.Flags().GetStringArray("tests")
+		if err != nil {
+			return err
+		}
+
+		baseTestsPaths, err := cmd.Flags().GetStringArray("base-tests")
+		if err != nil {
+			return err
+		}
+
+		untestedAllowlist, err := cmd.Flags().GetStringArray("allow-untested")
+		if err != nil {
+			return err
+		}
+
+		checkDeprecations, err := cmd.Flags().GetBool("check-deprecations")
+		if err != nil {
+			return err
+		}
+
+		deprecatedPaths, err := cmd.Flags().GetStringArray("deprecated")
+		if err != nil {
+			return err
+		}
+
+		deprecatedName, err := cmd.Flags().GetString("deprecated-rulesfile")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
 		}
 
-		diff = compareRulesMinor(leftOutput, rightOutput)
-		if len(diff) > 0 {
-			fmt.Fprintln(cmd.OutOrStdout(), "**Minor** changes:")
-			for _, s := range diff {
-				fmt.Fprintln(cmd.OutOrStdout(), "* "+s)
+		writeCompareResult(cmd.OutOrStdout(), leftOutput, rightOutput)
+
+		// the result of the policy checks, reported after running all of them
+		var res error
+
+		// Requiring removed rules to be deprecated for at least one release
+		if checkDeprecations {
+			var deprecated, released []*rulesFile
+			for _, path := range deprecatedPaths {
+				f, err := loadRulesFile(path)
+				if err != nil {
+					return err
+				}
+				deprecated = append(deprecated, f)
+			}
+			releases, err := loadReleaseHistory(registryPath, deprecatedName)
+			if err != nil {
+				return err
+			}
+			for _, rel := range releases {
+				released = append(released, rel.File)
+			}
+			diff := compareRulesDeprecations(leftOutput, rightOutput, deprecated, released)
+			if len(diff) > 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "**Deprecation** policy violations:")
+				for _, s := range diff {
+					fmt.Fprintln(cmd.OutOrStdout(), "* "+s)
+				}
+				fmt.Fprintln(cmd.OutOrStdout())
+				res = errAppend(res, fmt.Errorf("some rules have been removed without being deprecated for at least one release"))
 			}
-			fmt.Fprintln(cmd.OutOrStdout())
 		}
 
-		diff = compareRulesPatch(leftOutput, rightOutput)
-		if len(diff) > 0 {
-			fmt.Fprintln(cmd.OutOrStdout(), "**Patch** changes:")
-			for _, s := range diff {
-				fmt.Fprintln(cmd.OutOrStdout(), "* "+s)
+		// Requiring tests for added or changed rules
+		if len(testsPaths) > 0 {
+			specs, err := loadRuleTestSpecs(testsPaths...)
+			if err != nil {
+				return err
+			}
+			baseSpecs, err := loadExistingRuleTestSpecs(baseTestsPaths...)
+			if err != nil {
+				return err
+			}
+			untested, err := untestedRuleNames(rightRules, untestedAllowlist)
+			if err != nil {
+				return err
+			}
+			diff := compareRulesTests(leftOutput, rightOutput, specs, baseSpecs, untested)
+			if len(diff) > 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "**Missing** tests:")
+				for _, s := range diff {
+					fmt.Fprintln(cmd.OutOrStdout(), "* "+s)
+				}
+				fmt.Fprintln(cmd.OutOrStdout())
+				res = errAppend(res, fmt.Errorf("some added or changed rules have no new or updated positive and negative test cases"))
 			}
-			fmt.Fprintln(cmd.OutOrStdout())
 		}
 
-		return nil
+		return res
 	},
 }
 
@@ -464,5 +648,12 @@ func init() {
 	compareCmd.Flags().StringArrayP("file", "f", []string{}, "Extra files required by Falco for running")
 	compareCmd.Flags().StringArrayP("left", "l", []string{}, "Rules files to be loaded for the left-hand side of the comparison")
 	compareCmd.Flags().StringArrayP("right", "r", []string{}, "Rules files to be loaded for the right-hand side of the comparison")
+	compareCmd.Flags().StringArray("tests", []string{}, "Test specs files or directories of the right-hand side, requiring tests for added or changed rules")
+	compareCmd.Flags().StringArray("base-tests", []string{}, "Test specs files or directories of the left-hand side, whose test cases are not considered new")
+	compareCmd.Flags().StringArray("allow-untested", []string{}, "Base names of the rules files whose rules are not required to have tests")
+	compareCmd.Flags().Bool("check-deprecations", false, "Require removed stable rules to be deprecated for at least one release, using the release tags of the deprecated rules file")
+	compareCmd.Flags().StringArray("deprecated", []string{}, "Deprecated rules files of the right-hand side, where removed rules can be moved to")
+	compareCmd.Flags().String("deprecated-rulesfile", maturityRulesfiles[maturityDeprecated], "Name of the deprecated rules file in the registry, whose release tags are checked")
+	compareCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
 	rootCmd.AddCommand(compareCmd)
 }
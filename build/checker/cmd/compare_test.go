@@ -420,3 +420,149 @@ func TestCompareRulesMajor(t *testing.T) {
 		})
 	})
 }
+
+func TestCompareRulesTests(t *testing.T) {
+	t.Parallel()
+
+	one := 1
+	positive := ruleTestCase{
+		Name:   "positive",
+		Events: []condEvent{{"evt.type": "execve"}},
+		Alerts: []ruleTestAlert{{Count: &one}},
+	}
+	negative := ruleTestCase{
+		Name:   "negative",
+		Events: []condEvent{{"evt.type": "openat"}},
+	}
+	spec := func(cases ...ruleTestCase) []*ruleTestSpec {
+		return []*ruleTestSpec{{Rule: "rule1", Cases: cases, Path: "tests/rule1.yaml"}}
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		t.Parallel()
+		o1 := testGetSampleFalcoCompareOutput(t)
+		o2 := testGetSampleFalcoCompareOutput(t)
+		o2.Rules[0].Info.Condition = "\n" + o2.Rules[0].Info.Condition
+		res := compareRulesTests(o1, o2, nil, nil, nil)
+		assert.Empty(t, res)
+	})
+	t.Run("added-without-tests", func(t *testing.T) {
+		t.Parallel()
+		o1 := testGetSampleFalcoCompareOutput(t)
+		o1.Rules = nil
+		res := compareRulesTests(o1, testGetSampleFalcoCompareOutput(t), spec(positive), nil, nil)
+		assert.Equal(t, []string{"Rule `rule1` has been added without new or updated negative test cases"}, res)
+	})
+	t.Run("added-with-tests", func(t *testing.T) {
+		t.Parallel()
+		o1 := testGetSampleFalcoCompareOutput(t)
+		o1.Rules = nil
+		res := compareRulesTests(o1, testGetSampleFalcoCompareOutput(t), spec(positive, negative), nil, nil)
+		assert.Empty(t, res)
+	})
+	t.Run("changed-condition-with-old-tests", func(t *testing.T) {
+		t.Parallel()
+		o2 := testGetSampleFalcoCompareOutput(t)
+		o2.Rules[0].Info.Condition = "evt.type = execve"
+		res := compareRulesTests(testGetSampleFalcoCompareOutput(t), o2, spec(positive, negative), spec(positive, negative), nil)
+		assert.Equal(t, []string{"Rule `rule1` changed its condition without new or updated positive and negative test cases"}, res)
+	})
+	t.Run("changed-condition-with-updated-tests", func(t *testing.T) {
+		t.Parallel()
+		o2 := testGetSampleFalcoCompareOutput(t)
+		o2.Rules[0].Info.Condition = "evt.type = execve"
+		updated := negative
+		updated.Events = []condEvent{{"evt.type": "connect"}}
+		res := compareRulesTests(testGetSampleFalcoCompareOutput(t), o2, spec(positive, updated), spec(negative), nil)
+		assert.Empty(t, res)
+	})
+	t.Run("allowlisted", func(t *testing.T) {
+		t.Parallel()
+		o1 := testGetSampleFalcoCompareOutput(t)
+		o1.Rules = nil
+		res := compareRulesTests(o1, testGetSampleFalcoCompareOutput(t), nil, nil, map[string]bool{"rule1": true})
+		assert.Empty(t, res)
+	})
+	withMacro := func(t *testing.T) *falco.RulesetDescription {
+		o := testGetSampleFalcoCompareOutput(t)
+		o.Macros[0].Info.Condition = "evt.type = execve"
+		o.Rules[0].Info.Condition = "macro1 and proc.name in (list1)"
+		return o
+	}
+	t.Run("changed-macro-with-old-tests", func(t *testing.T) {
+		t.Parallel()
+		o2 := withMacro(t)
+		o2.Macros[0].Info.Condition = "evt.type in (execve, execveat)"
+		res := compareRulesTests(withMacro(t), o2, spec(positive, negative), spec(positive, negative), nil)
+		assert.Equal(t, []string{"Rule `rule1` changed its condition without new or updated positive and negative test cases"}, res)
+	})
+	t.Run("changed-list-with-old-tests", func(t *testing.T) {
+		t.Parallel()
+		o2 := withMacro(t)
+		o2.Lists[0].Info.Items = append(o2.Lists[0].Info.Items, "zsh")
+		res := compareRulesTests(withMacro(t), o2, spec(positive, negative), spec(positive, negative), nil)
+		assert.Len(t, res, 1)
+	})
+	t.Run("reformatted-macro", func(t *testing.T) {
+		t.Parallel()
+		o2 := withMacro(t)
+		o2.Macros[0].Info.Condition = "(evt.type=execve)"
+		res := compareRulesTests(withMacro(t), o2, nil, nil, nil)
+		assert.Empty(t, res)
+	})
+}
+
+func TestCompareRulesDeprecations(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, `
+- rule: rule1
+  desc: deprecated
+  condition: evt.type = execve
+  output: out
+  priority: NOTICE
+  tags: [maturity_deprecated]
+`, `
+- rule: rule1
+  desc: moved without tag
+  condition: evt.type = execve
+  output: out
+  priority: NOTICE
+  tags: [maturity_stable]
+`)
+	stable := func() *falco.RulesetDescription {
+		o := testGetSampleFalcoCompareOutput(t)
+		o.Rules[0].Info.Tags = append(o.Rules[0].Info.Tags, "maturity_stable")
+		return o
+	}
+	removed := testGetSampleFalcoCompareOutput(t)
+	removed.Rules = nil
+
+	t.Run("unchanged", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, compareRulesDeprecations(stable(), stable(), nil, nil))
+	})
+	t.Run("removed-without-deprecation", func(t *testing.T) {
+		t.Parallel()
+		res := compareRulesDeprecations(stable(), removed, nil, nil)
+		assert.Equal(t, []string{"Rule `rule1` has been removed without being deprecated for at least one release"}, res)
+	})
+	t.Run("moved-to-deprecated", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, compareRulesDeprecations(stable(), removed, files[:1], nil))
+		res := compareRulesDeprecations(stable(), removed, files[1:], nil)
+		assert.Equal(t, []string{"Rule `rule1` has been moved to the deprecated rules without the maturity_deprecated tag"}, res)
+	})
+	t.Run("removed-after-release", func(t *testing.T) {
+		t.Parallel()
+		deprecated := testGetSampleFalcoCompareOutput(t)
+		deprecated.Rules[0].Info.Tags = []string{"maturity_deprecated"}
+		assert.Empty(t, compareRulesDeprecations(deprecated, removed, nil, files[:1]))
+		res := compareRulesDeprecations(deprecated, removed, nil, files[1:])
+		assert.Equal(t, []string{"Rule `rule1` has been removed before being released as deprecated"}, res)
+	})
+	t.Run("non-stable", func(t *testing.T) {
+		t.Parallel()
+		assert.Empty(t, compareRulesDeprecations(testGetSampleFalcoCompareOutput(t), removed, nil, nil))
+	})
+}
@@ -0,0 +1,731 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// condition operators that take a parenthesized list of values
+var condListOperators = map[string]bool{
+	"in":         true,
+	"intersects": true,
+	"pmatch":     true,
+}
+
+// condition operators that take no value
+var condUnaryOperators = map[string]bool{
+	"exists": true,
+}
+
+// condition operators that take one value
+var condBinaryOperators = map[string]bool{
+	"=":           true,
+	"==":          true,
+	"!=":          true,
+	"<":           true,
+	"<=":          true,
+	">":           true,
+	">=":          true,
+	"contains":    true,
+	"icontains":   true,
+	"bcontains":   true,
+	"startswith":  true,
+	"bstartswith": true,
+	"endswith":    true,
+	"glob":        true,
+	"iglob":       true,
+	"regex":       true,
+}
+
+// field transformers that can wrap a field, such as in `tolower(proc.name)`
+var condFieldTransformers = map[string]bool{
+	"tolower":  true,
+	"toupper":  true,
+	"b64":      true,
+	"basename": true,
+	"len":      true,
+	"val":      true,
+}
+
+// condExpr is a node of the AST of a Falco condition.
+type condExpr interface {
+	// Pos returns the offset of the node in the parsed condition text
+	Pos() int
+	// String returns the node in the Falco condition syntax
+	String() string
+}
+
+// condAndExpr is a conjunction of two or more expressions.
+type condAndExpr struct {
+	Offset int
+	Exprs  []condExpr
+}
+
+// condOrExpr is a disjunction of two or more expressions.
+type condOrExpr struct {
+	Offset int
+	Exprs  []condExpr
+}
+
+// condNotExpr is the negation of an expression.
+type condNotExpr struct {
+	Offset int
+	Expr   condExpr
+}
+
+// condIdentExpr is a reference to a macro.
+type condIdentExpr struct {
+	Offset int
+	Name   string
+}
+
+// condField is a field reference, such as `proc.aname[2]` or `tolower(proc.name)`.
+type condField struct {
+	Name        string
+	Arg         string
+	Transformer string
+}
+
+// condValue is the right-hand side of a field comparison. Values can either
+// be literals or, when used with `val()`, other fields.
+type condValue struct {
+	Offset int
+	Text   string
+	Quote  byte
+	Field  *condField
+}
+
+// condCheckExpr is a comparison between a field and zero or more values.
+type condCheckExpr struct {
+	Offset int
+	Field  condField
+	Op     string
+	Values []condValue
+}
+
+func (e *condAndExpr) Pos() int   { return e.Offset }
+func (e *condOrExpr) Pos() int    { return e.Offset }
+func (e *condNotExpr) Pos() int   { return e.Offset }
+func (e *condIdentExpr) Pos() int { return e.Offset }
+func (e *condCheckExpr) Pos() int { return e.Offset }
+
+func (e *condAndExpr) String() string { return joinCondExprs(e.Exprs, " and ", false) }
+func (e *condOrExpr) String() string  { return joinCondExprs(e.Exprs, " or ", true) }
+
+func (e *condNotExpr) String() string {
+	switch e.Expr.(type) {
+	case *condAndExpr, *condOrExpr:
+		return "not (" + e.Expr.String() + ")"
+	}
+	return "not " + e.Expr.String()
+}
+
+func (e *condIdentExpr) String() string { return e.Name }
+
+func (f condField) String() string {
+	s := f.Name
+	if len(f.Arg) > 0 {
+		s += "[" + f.Arg + "]"
+	}
+	if len(f.Transformer) > 0 {
+		s = f.Transformer + "(" + s + ")"
+	}
+	return s
+}
+
+// FullName returns the name of the field including its argument, if any.
+func (f condField) FullName() string {
+	if len(f.Arg) > 0 {
+		return f.Name + "[" + f.Arg + "]"
+	}
+	return f.Name
+}
+
+func (v condValue) String() string {
+	if v.Field != nil {
+		return v.Field.String()
+	}
+	if v.Quote != 0 {
+		q := string(v.Quote)
+		return q + strings.ReplaceAll(v.Text, q, "\\"+q) + q
+	}
+	return v.Text
+}
+
+func (e *condCheckExpr) String() string {
+	if condUnaryOperators[e.Op] {
+		return e.Field.String() + " " + e.Op
+	}
+	if condListOperators[e.Op] {
+		var values []string
+		for _, v := range e.Values {
+			values = append(values, v.String())
+		}
+		return e.Field.String() + " " + e.Op + " (" + strings.Join(values, ", ") + ")"
+	}
+	sep := " "
+	if !isCondWord(e.Op) {
+		sep = ""
+	}
+	return e.Field.String() + sep + e.Op + sep + e.Values[0].String()
+}
+
+func joinCondExprs(exprs []condExpr, sep string, isOr bool) string {
+	var parts []string
+	for _, e := range exprs {
+		s := e.String()
+		switch e.(type) {
+		case *condOrExpr:
+			if !isOr {
+				s = "(" + s + ")"
+			}
+		case *condAndExpr:
+			if isOr {
+				s = "(" + s + ")"
+			}
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, sep)
+}
+
+func isCondWord(s string) bool {
+	return len(s) > 0 && (s[0] >= 'a' && s[0] <= 'z')
+}
+
+// condition tokens
+const (
+	condTokEOF = iota
+	condTokLParen
+	condTokRParen
+	condTokComma
+	condTokOp
+	condTokWord
+	condTokString
+)
+
+type condToken struct {
+	Kind   int
+	Text   string
+	Quote  byte
+	Offset int
+}
+
+// tokenizeCondition splits a condition into its tokens.
+func tokenizeCondition(s string) ([]condToken, error) {
+	var toks []condToken
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v':
+			i++
+		case c == '(':
+			toks = append(toks, condToken{Kind: condTokLParen, Text: "(", Offset: i})
+			i++
+		case c == ')':
+			toks = append(toks, condToken{Kind: condTokRParen, Text: ")", Offset: i})
+			i++
+		case c == ',':
+			toks = append(toks, condToken{Kind: condTokComma, Text: ",", Offset: i})
+			i++
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			start := i
+			i++
+			if i < len(s) && s[i] == '=' {
+				i++
+			}
+			op := s[start:i]
+			if op == "!" {
+				return nil, fmt.Errorf("unexpected character '!' at offset %d", start)
+			}
+			toks = append(toks, condToken{Kind: condTokOp, Text: op, Offset: start})
+		case c == '"' || c == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(s) {
+				if s[i] == '\\' && i+1 < len(s) {
+					sb.WriteByte(s[i+1])
+					i += 2
+					continue
+				}
+				if s[i] == c {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteByte(s[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string at offset %d", start)
+			}
+			toks = append(toks, condToken{Kind: condTokString, Text: sb.String(), Quote: c, Offset: start})
+		default:
+			start := i
+			for i < len(s) && !strings.ContainsRune(" \t\n\r\f\v(),=<>!\"'", rune(s[i])) {
+				i++
+			}
+			// bracketed field arguments can contain any character but spaces and brackets
+			if i < len(s) && strings.HasSuffix(s[start:i], "[") {
+				for i < len(s) && s[i] != ']' && s[i] != ' ' {
+					i++
+				}
+				if i < len(s) && s[i] == ']' {
+					i++
+				}
+			}
+			toks = append(toks, condToken{Kind: condTokWord, Text: s[start:i], Offset: start})
+		}
+	}
+	toks = append(toks, condToken{Kind: condTokEOF, Offset: len(s)})
+	return toks, nil
+}
+
+type condParser struct {
+	toks []condToken
+	cur  int
+}
+
+// parseCondition parses a Falco condition and returns its AST.
+func parseCondition(s string) (condExpr, error) {
+	toks, err := tokenizeCondition(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &condParser{toks: toks}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().Kind != condTokEOF {
+		return nil, p.errorf("unexpected token '%s'", p.peek().Text)
+	}
+	return e, nil
+}
+
+// parseConditionFragment parses a condition that may be meant to be appended
+// to another one, and thus start with a dangling `and` or `or`.
+func parseConditionFragment(s string) (condExpr, error) {
+	trimmed := strings.TrimSpace(s)
+	for _, kw := range []string{"and", "or"} {
+		if strings.HasPrefix(trimmed, kw) && len(trimmed) > len(kw) &&
+			strings.ContainsRune(" \t\n\r(", rune(trimmed[len(kw)])) {
+			return parseCondition(trimmed[len(kw):])
+		}
+	}
+	return parseCondition(s)
+}
+
+func (p *condParser) peek() condToken {
+	return p.toks[p.cur]
+}
+
+func (p *condParser) peekAt(n int) condToken {
+	if p.cur+n >= len(p.toks) {
+		return p.toks[len(p.toks)-1]
+	}
+	return p.toks[p.cur+n]
+}
+
+func (p *condParser) next() condToken {
+	t := p.toks[p.cur]
+	if t.Kind != condTokEOF {
+		p.cur++
+	}
+	return t
+}
+
+func (p *condParser) isKeyword(kw string) bool {
+	t := p.peek()
+	return t.Kind == condTokWord && t.Text == kw
+}
+
+func (p *condParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s at offset %d", fmt.Sprintf(format, args...), p.peek().Offset)
+}
+
+func (p *condParser) parseOr() (condExpr, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []condExpr{first}
+	for p.isKeyword("or") {
+		p.next()
+		e, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	if len(exprs) == 1 {
+		return first, nil
+	}
+	return &condOrExpr{Offset: first.Pos(), Exprs: exprs}, nil
+}
+
+func (p *condParser) parseAnd() (condExpr, error) {
+	first, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []condExpr{first}
+	for p.isKeyword("and") {
+		p.next()
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, e)
+	}
+	if len(exprs) == 1 {
+		return first, nil
+	}
+	return &condAndExpr{Offset: first.Pos(), Exprs: exprs}, nil
+}
+
+func (p *condParser) parseNot() (condExpr, error) {
+	if p.isKeyword("not") {
+		t := p.next()
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &condNotExpr{Offset: t.Offset, Expr: e}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *condParser) parsePrimary() (condExpr, error) {
+	t := p.peek()
+	switch t.Kind {
+	case condTokLParen:
+		p.next()
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().Kind != condTokRParen {
+			return nil, p.errorf("expected ')'")
+		}
+		p.next()
+		return e, nil
+	case condTokWord:
+		if condFieldTransformers[t.Text] && p.peekAt(1).Kind == condTokLParen {
+			return p.parseCheck()
+		}
+		next := p.peekAt(1)
+		if !strings.ContainsAny(t.Text, ".[") && !p.isOperator(next) {
+			p.next()
+			return &condIdentExpr{Offset: t.Offset, Name: t.Text}, nil
+		}
+		return p.parseCheck()
+	case condTokEOF:
+		return nil, p.errorf("unexpected end of condition")
+	}
+	return nil, p.errorf("unexpected token '%s'", t.Text)
+}
+
+func (p *condParser) isOperator(t condToken) bool {
+	if t.Kind == condTokOp {
+		return true
+	}
+	return t.Kind == condTokWord &&
+		(condBinaryOperators[t.Text] || condListOperators[t.Text] || condUnaryOperators[t.Text])
+}
+
+func (p *condParser) parseField() (condField, error) {
+	t := p.next()
+	if t.Kind != condTokWord {
+		return condField{}, fmt.Errorf("expected field at offset %d", t.Offset)
+	}
+	if condFieldTransformers[t.Text] && p.peek().Kind == condTokLParen {
+		p.next()
+		f, err := p.parseField()
+		if err != nil {
+			return f, err
+		}
+		if p.peek().Kind != condTokRParen {
+			return f, p.errorf("expected ')'")
+		}
+		p.next()
+		f.Transformer = t.Text
+		return f, nil
+	}
+	f := condField{Name: t.Text}
+	if i := strings.Index(t.Text, "["); i >= 0 && strings.HasSuffix(t.Text, "]") {
+		f.Name = t.Text[:i]
+		f.Arg = t.Text[i+1 : len(t.Text)-1]
+	}
+	return f, nil
+}
+
+func (p *condParser) parseValue() (condValue, error) {
+	t := p.peek()
+	switch t.Kind {
+	case condTokString:
+		p.next()
+		return condValue{Offset: t.Offset, Text: t.Text, Quote: t.Quote}, nil
+	case condTokWord:
+		if condFieldTransformers[t.Text] && p.peekAt(1).Kind == condTokLParen {
+			f, err := p.parseField()
+			if err != nil {
+				return condValue{}, err
+			}
+			return condValue{Offset: t.Offset, Field: &f}, nil
+		}
+		p.next()
+		return condValue{Offset: t.Offset, Text: t.Text}, nil
+	case condTokOp:
+		// legacy syntax such as `evt.dir=<`
+		if t.Text == "<" || t.Text == ">" {
+			p.next()
+			return condValue{Offset: t.Offset, Text: t.Text}, nil
+		}
+	}
+	return condValue{}, p.errorf("expected value")
+}
+
+func (p *condParser) parseCheck() (condExpr, error) {
+	start := p.peek().Offset
+	f, err := p.parseField()
+	if err != nil {
+		return nil, err
+	}
+	opTok := p.next()
+	if !p.isOperator(opTok) {
+		return nil, fmt.Errorf("expected operator after field '%s' at offset %d", f.String(), opTok.Offset)
+	}
+	res := &condCheckExpr{Offset: start, Field: f, Op: opTok.Text}
+	switch {
+	case condUnaryOperators[res.Op]:
+		return res, nil
+	case condListOperators[res.Op]:
+		if p.peek().Kind != condTokLParen {
+			// a single value or list name without parentheses
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			res.Values = append(res.Values, v)
+			return res, nil
+		}
+		p.next()
+		for p.peek().Kind != condTokRParen {
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			res.Values = append(res.Values, v)
+			if p.peek().Kind == condTokComma {
+				p.next()
+			} else if p.peek().Kind != condTokRParen {
+				return nil, p.errorf("expected ',' or ')'")
+			}
+		}
+		p.next()
+		return res, nil
+	default:
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		res.Values = append(res.Values, v)
+		return res, nil
+	}
+}
+
+// walkCondition visits all the nodes of a condition AST in depth-first
+// order, and stops descending into a node when fn returns false.
+func walkCondition(e condExpr, fn func(condExpr) bool) {
+	if e == nil || !fn(e) {
+		return
+	}
+	switch v := e.(type) {
+	case *condAndExpr:
+		for _, c := range v.Exprs {
+			walkCondition(c, fn)
+		}
+	case *condOrExpr:
+		for _, c := range v.Exprs {
+			walkCondition(c, fn)
+		}
+	case *condNotExpr:
+		walkCondition(v.Expr, fn)
+	}
+}
+
+// conditionMacroRefs returns the names of the macros referenced by a
+// condition, in order of first appearance.
+func conditionMacroRefs(e condExpr) []string {
+	var res []string
+	seen := make(map[string]bool)
+	walkCondition(e, func(n condExpr) bool {
+		if id, ok := n.(*condIdentExpr); ok && !seen[id.Name] {
+			seen[id.Name] = true
+			res = append(res, id.Name)
+		}
+		return true
+	})
+	return res
+}
+
+// conditionListRefs returns the names of the lists referenced by a condition,
+// in order of first appearance. Lists can be referenced only as values of list
+// operators, and only the names present in isList are considered.
+func conditionListRefs(e condExpr, isList func(string) bool) []string {
+	var res []string
+	seen := make(map[string]bool)
+	walkCondition(e, func(n condExpr) bool {
+		if c, ok := n.(*condCheckExpr); ok && condListOperators[c.Op] {
+			for _, v := range c.Values {
+				if v.Quote == 0 && v.Field == nil && !seen[v.Text] && isList(v.Text) {
+					seen[v.Text] = true
+					res = append(res, v.Text)
+				}
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// conditionChecks returns all the field comparisons of a condition.
+func conditionChecks(e condExpr) []*condCheckExpr {
+	var res []*condCheckExpr
+	walkCondition(e, func(n condExpr) bool {
+		if c, ok := n.(*condCheckExpr); ok {
+			res = append(res, c)
+		}
+		return true
+	})
+	return res
+}
+
+// expandCondition returns a copy of a condition AST in which all macro
+// references are recursively replaced by the macro conditions, and all list
+// references are replaced by the list items. The macro lookup function
+// returns an error if the macro is not defined, whereas the list lookup one
+// returns false if no list exists with a given name.
+func expandCondition(e condExpr, macro func(string) (condExpr, error), list func(string) ([]string, bool)) (condExpr, error) {
+	return expandConditionRec(e, macro, list, nil)
+}
+
+func expandConditionRec(e condExpr, macro func(string) (condExpr, error), list func(string) ([]string, bool), visiting []string) (condExpr, error) {
+	switch v := e.(type) {
+	case *condAndExpr:
+		res := &condAndExpr{Offset: v.Offset}
+		for _, c := range v.Exprs {
+			x, err := expandConditionRec(c, macro, list, visiting)
+			if err != nil {
+				return nil, err
+			}
+			res.Exprs = append(res.Exprs, x)
+		}
+		return res, nil
+	case *condOrExpr:
+		res := &condOrExpr{Offset: v.Offset}
+		for _, c := range v.Exprs {
+			x, err := expandConditionRec(c, macro, list, visiting)
+			if err != nil {
+				return nil, err
+			}
+			res.Exprs = append(res.Exprs, x)
+		}
+		return res, nil
+	case *condNotExpr:
+		x, err := expandConditionRec(v.Expr, macro, list, visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &condNotExpr{Offset: v.Offset, Expr: x}, nil
+	case *condIdentExpr:
+		for _, name := range visiting {
+			if name == v.Name {
+				return nil, fmt.Errorf("macro `%s` has a circular reference", v.Name)
+			}
+		}
+		m, err := macro(v.Name)
+		if err != nil {
+			return nil, err
+		}
+		return expandConditionRec(m, macro, list, append(visiting, v.Name))
+	case *condCheckExpr:
+		res := *v
+		if condListOperators[v.Op] {
+			res.Values = expandListValues(v.Values, list, nil)
+		}
+		return &res, nil
+	}
+	return e, nil
+}
+
+func expandListValues(values []condValue, list func(string) ([]string, bool), visiting []string) []condValue {
+	var res []condValue
+	for _, v := range values {
+		if v.Quote != 0 || v.Field != nil {
+			res = append(res, v)
+			continue
+		}
+		items, ok := list(v.Text)
+		if !ok || strSliceContains(visiting, v.Text) {
+			res = append(res, v)
+			continue
+		}
+		var itemValues []condValue
+		for _, it := range items {
+			itemValues = append(itemValues, condListItemValue(v.Offset, it))
+		}
+		res = append(res, expandListValues(itemValues, list, append(visiting, v.Text))...)
+	}
+	return res
+}
+
+// condListItemValue converts a list item into a condition value, keeping
+// the quoting that YAML list items use to include special characters.
+func condListItemValue(offset int, item string) condValue {
+	if len(item) >= 2 && (item[0] == '"' || item[0] == '\'') && item[len(item)-1] == item[0] {
+		var sb strings.Builder
+		for i := 1; i < len(item)-1; i++ {
+			if item[i] == '\\' && i+1 < len(item)-1 {
+				i++
+			}
+			sb.WriteByte(item[i])
+		}
+		return condValue{Offset: offset, Text: sb.String(), Quote: item[0]}
+	}
+	if strings.ContainsAny(item, " \t(),=<>!") {
+		return condValue{Offset: offset, Text: item, Quote: '"'}
+	}
+	return condValue{Offset: offset, Text: item}
+}
+
+// strSliceContains returns true if the slice contains the given string.
+func strSliceContains(s []string, v string) bool {
+	for _, item := range s {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCondition(t *testing.T) {
+	t.Parallel()
+
+	t.Run("precedence", func(t *testing.T) {
+		t.Parallel()
+		c, err := parseCondition("a or b and not c")
+		require.NoError(t, err)
+		or, ok := c.(*condOrExpr)
+		require.True(t, ok)
+		assert.Len(t, or.Exprs, 2)
+		assert.IsType(t, &condAndExpr{}, or.Exprs[1])
+		assert.Equal(t, "a or (b and not c)", c.String())
+	})
+
+	t.Run("checks", func(t *testing.T) {
+		t.Parallel()
+		c, err := parseCondition(`evt.type in (open,openat) and fd.num>=0 and proc.aname[2]="sh" and proc.pname exists and tolower(proc.name) = bash`)
+		require.NoError(t, err)
+		checks := conditionChecks(c)
+		require.Len(t, checks, 5)
+		assert.Equal(t, "in", checks[0].Op)
+		assert.Len(t, checks[0].Values, 2)
+		assert.Equal(t, ">=", checks[1].Op)
+		assert.Equal(t, "proc.aname", checks[2].Field.Name)
+		assert.Equal(t, "2", checks[2].Field.Arg)
+		assert.Equal(t, byte('"'), checks[2].Values[0].Quote)
+		assert.Equal(t, "exists", checks[3].Op)
+		assert.Equal(t, "tolower", checks[4].Field.Transformer)
+	})
+
+	t.Run("legacy-direction", func(t *testing.T) {
+		t.Parallel()
+		c, err := parseCondition(`evt.dir=< and evt.type=open`)
+		require.NoError(t, err)
+		assert.Equal(t, "evt.dir=< and evt.type=open", c.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		for _, s := range []string{"", "(a", "a and", "proc.name =", `proc.name = "abc`, "proc.name in (a b)"} {
+			_, err := parseCondition(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
+
+func TestExpandCondition(t *testing.T) {
+	t.Parallel()
+	macros := map[string]string{
+		"m1": "evt.type in (open, openat)",
+		"m2": "m1 and proc.name in (shells)",
+		"m3": "m4",
+		"m4": "m3",
+	}
+	lists := map[string][]string{
+		"shells":      {"bash", "more_shells"},
+		"more_shells": {"zsh", `"ba sh"`},
+	}
+	macro := func(name string) (condExpr, error) {
+		if m, ok := macros[name]; ok {
+			return parseCondition(m)
+		}
+		return nil, assert.AnError
+	}
+	list := func(name string) ([]string, bool) {
+		l, ok := lists[name]
+		return l, ok
+	}
+
+	c, err := parseCondition("m2 and not proc.pname in (shells, sh)")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"m2"}, conditionMacroRefs(c))
+	assert.Equal(t, []string{"shells"}, conditionListRefs(c, func(s string) bool { _, ok := lists[s]; return ok }))
+
+	e, err := expandCondition(c, macro, list)
+	require.NoError(t, err)
+	assert.Equal(t, `evt.type in (open, openat) and proc.name in (bash, zsh, "ba sh") and not proc.pname in (bash, zsh, "ba sh", sh)`, e.String())
+
+	c, err = parseCondition("m3")
+	require.NoError(t, err)
+	_, err = expandCondition(c, macro, list)
+	assert.Error(t, err)
+
+	c, err = parseCondition("undefined_macro")
+	require.NoError(t, err)
+	_, err = expandCondition(c, macro, list)
+	assert.Error(t, err)
+}
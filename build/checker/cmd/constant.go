@@ -0,0 +1,427 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// condConstantChecks are the field comparisons known to have a constant
+// result, such as the one of the `never_true` macro.
+var condConstantChecks = map[string]bool{
+	"evt.num=0":  false,
+	"evt.num>=0": true,
+	"evt.num>0":  true,
+}
+
+// condNonSetFields are the fields whose equality comparisons can't be
+// reasoned about as sets of values, because they can have more than one value
+// for the same event or because they match network ranges.
+var condNonSetFields = map[string]bool{
+	"fd.types": true,
+	"fd.net":   true,
+	"fd.cnet":  true,
+	"fd.snet":  true,
+	"fd.lnet":  true,
+	"fd.rnet":  true,
+}
+
+// defaultSolverBudget is the maximum number of steps after which the solver
+// gives up and assumes that a condition is satisfiable.
+const defaultSolverBudget = 100000
+
+// condFieldDomain is the set of values that a field can assume. A nil set of
+// allowed values means that the field can assume any value not excluded.
+type condFieldDomain struct {
+	allowed  map[string]bool
+	excluded map[string]bool
+}
+
+func (d *condFieldDomain) empty() bool {
+	if d.allowed == nil {
+		return false
+	}
+	for v := range d.allowed {
+		if !d.excluded[v] {
+			return false
+		}
+	}
+	return true
+}
+
+// condSolverEnv is the set of constraints collected along a branch of the
+// symbolic evaluation of a condition.
+type condSolverEnv struct {
+	fields map[string]*condFieldDomain
+	atoms  map[string]bool
+}
+
+func newCondSolverEnv() *condSolverEnv {
+	return &condSolverEnv{
+		fields: make(map[string]*condFieldDomain),
+		atoms:  make(map[string]bool),
+	}
+}
+
+func (e *condSolverEnv) clone() *condSolverEnv {
+	res := newCondSolverEnv()
+	for k, v := range e.atoms {
+		res.atoms[k] = v
+	}
+	for k, d := range e.fields {
+		c := &condFieldDomain{excluded: make(map[string]bool)}
+		if d.allowed != nil {
+			c.allowed = make(map[string]bool)
+			for v := range d.allowed {
+				c.allowed[v] = true
+			}
+		}
+		for v := range d.excluded {
+			c.excluded[v] = true
+		}
+		res.fields[k] = c
+	}
+	return res
+}
+
+// condTerm is a sub-expression of a condition, possibly negated.
+type condTerm struct {
+	expr    condExpr
+	negated bool
+}
+
+// condSolver evaluates conditions symbolically, by searching for an
+// assignment of the field comparisons that satisfies them. Equality checks
+// on single-value fields are reasoned about as sets of values, whereas all
+// the other field comparisons are considered independent of each other.
+type condSolver struct {
+	// setFields enables reasoning on the values of single-value fields
+	setFields bool
+	budget    int
+	// Exhausted is true if the solver gave up in any of the evaluations
+	Exhausted bool
+}
+
+func newCondSolver(setFields bool) *condSolver {
+	return &condSolver{setFields: setFields, budget: defaultSolverBudget}
+}
+
+// Satisfiable returns true if there is at least one event for which all the
+// given conditions are true.
+func (s *condSolver) Satisfiable(exprs ...condExpr) bool {
+	var terms []condTerm
+	for _, e := range exprs {
+		terms = append(terms, condTerm{expr: e})
+	}
+	return s.sat(terms, newCondSolverEnv())
+}
+
+func (s *condSolver) sat(terms []condTerm, env *condSolverEnv) bool {
+	var branches []condTerm
+	for len(terms) > 0 {
+		s.budget--
+		if s.budget < 0 {
+			s.Exhausted = true
+			return true
+		}
+		t := terms[0]
+		terms = terms[1:]
+		switch v := t.expr.(type) {
+		case *condNotExpr:
+			terms = append(terms, condTerm{expr: v.Expr, negated: !t.negated})
+		case *condAndExpr, *condOrExpr:
+			exprs, isAnd := flattenCondition(v)
+			// a negated or behaves like an and, and vice versa
+			if isAnd == t.negated {
+				branches = append(branches, t)
+				continue
+			}
+			for _, c := range exprs {
+				terms = append(terms, condTerm{expr: c, negated: t.negated})
+			}
+		case *condCheckExpr:
+			if !s.apply(v, t.negated, env) {
+				return false
+			}
+		default:
+			// unexpanded macros are considered opaque
+			if !s.applyAtom(t.expr.String(), t.negated, env) {
+				return false
+			}
+		}
+	}
+	if len(branches) == 0 {
+		return true
+	}
+
+	// branch on the first disjunction and keep the others for later
+	exprs, _ := flattenCondition(branches[0].expr)
+	for _, c := range exprs {
+		next := []condTerm{{expr: c, negated: branches[0].negated}}
+		next = append(next, branches[1:]...)
+		if s.sat(next, env.clone()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *condSolver) applyAtom(key string, negated bool, env *condSolverEnv) bool {
+	if v, ok := env.atoms[key]; ok {
+		return v != negated
+	}
+	env.atoms[key] = !negated
+	return true
+}
+
+// apply adds the constraint of a field comparison to an environment, and
+// returns false if that makes the constraints contradictory.
+func (s *condSolver) apply(c *condCheckExpr, negated bool, env *condSolverEnv) bool {
+	if v, ok := condConstantChecks[c.String()]; ok {
+		return v != negated
+	}
+	if !s.isSetCheck(c) {
+		return s.applyAtom(c.String(), negated, env)
+	}
+	d, ok := env.fields[c.Field.FullName()]
+	if !ok {
+		d = &condFieldDomain{excluded: make(map[string]bool)}
+		env.fields[c.Field.FullName()] = d
+	}
+	values := make(map[string]bool)
+	for _, v := range c.Values {
+		values[v.Text] = true
+	}
+	if (c.Op == "!=") == negated {
+		if d.allowed == nil {
+			d.allowed = values
+		} else {
+			for v := range d.allowed {
+				if !values[v] {
+					delete(d.allowed, v)
+				}
+			}
+		}
+	} else {
+		for v := range values {
+			d.excluded[v] = true
+		}
+	}
+	return !d.empty()
+}
+
+// isSetCheck returns true if a field comparison can be reasoned about as the
+// field value belonging to a set of values or not.
+func (s *condSolver) isSetCheck(c *condCheckExpr) bool {
+	if !s.setFields || len(c.Field.Transformer) > 0 || condNonSetFields[c.Field.Name] {
+		return false
+	}
+	if condAncestryFields[c.Field.Name] && len(c.Field.Arg) == 0 {
+		return false
+	}
+	switch c.Op {
+	case "=", "==", "!=", "in":
+	default:
+		return false
+	}
+	for _, v := range c.Values {
+		if v.Field != nil {
+			return false
+		}
+	}
+	return len(c.Values) > 0
+}
+
+// constantConditionReport reports a rule whose condition can never match,
+// or always matches for the event types it applies to.
+type constantConditionReport struct {
+	Rule     string
+	Location string
+	// EventTypes are the event types the rule applies to, or nil if the rule
+	// applies to all of them
+	EventTypes []string
+	// NeverMatches is true if the condition is false for all events
+	NeverMatches bool
+	// AlwaysMatches is true if the condition is true for all the events of
+	// the event types the rule applies to
+	AlwaysMatches bool
+	// DeadEventTypes are the event types for which the condition is false
+	// even if they are part of the ones the rule applies to
+	DeadEventTypes []string
+}
+
+// conditionEventTypes returns the event types compared for equality in a
+// condition, in order of first appearance.
+func conditionEventTypes(e condExpr) []string {
+	var res []string
+	for _, c := range conditionChecks(e) {
+		if !isEvtTypeCheck(c) || c.Op == "!=" {
+			continue
+		}
+		for _, v := range c.Values {
+			if v.Field == nil && !strSliceContains(res, v.Text) {
+				res = append(res, v.Text)
+			}
+		}
+	}
+	return res
+}
+
+func evtTypeInCheck(types ...string) *condCheckExpr {
+	c := &condCheckExpr{Field: condField{Name: "evt.type"}, Op: "in"}
+	for _, t := range types {
+		c.Values = append(c.Values, condValue{Text: t})
+	}
+	return c
+}
+
+// analyzeConstantCondition evaluates the expanded condition of a rule
+// symbolically, and returns a report if the rule never matches, always
+// matches, or never matches for some of its event types. Returns nil if none
+// applies or if the evaluation is inconclusive.
+func analyzeConstantCondition(c condExpr, syscallSource bool) *constantConditionReport {
+	s := newCondSolver(syscallSource)
+	rep := &constantConditionReport{}
+	neg := &condNotExpr{Expr: c}
+
+	rep.NeverMatches = !s.Satisfiable(c)
+	if !rep.NeverMatches {
+		if syscallSource && restrictsEventTypes(c, false) {
+			var types []string
+			for _, t := range conditionEventTypes(c) {
+				if s.Satisfiable(c, evtTypeInCheck(t)) {
+					types = append(types, t)
+				} else {
+					rep.DeadEventTypes = append(rep.DeadEventTypes, t)
+				}
+			}
+			rep.EventTypes = types
+			rep.AlwaysMatches = !s.Satisfiable(neg, evtTypeInCheck(types...))
+		} else {
+			rep.AlwaysMatches = !s.Satisfiable(neg)
+		}
+	}
+	if s.Exhausted || (!rep.NeverMatches && !rep.AlwaysMatches && len(rep.DeadEventTypes) == 0) {
+		return nil
+	}
+	return rep
+}
+
+// analyzeConstantConditions returns the reports of the rules of a ruleset
+// whose conditions are contradictory or always true.
+func analyzeConstantConditions(rs *ruleset) ([]*constantConditionReport, error) {
+	var res []*constantConditionReport
+	for _, r := range rs.Rules {
+		c, err := rs.ExpandCondition(r)
+		if err != nil {
+			return nil, err
+		}
+		if rep := analyzeConstantCondition(c, r.RuleSource() == defaultRuleSource); rep != nil {
+			rep.Rule = r.Name()
+			rep.Location = r.Loc.String()
+			res = append(res, rep)
+		}
+	}
+	return res, nil
+}
+
+func writeConstantConditionReports(w io.Writer, reports []*constantConditionReport) {
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "No issues detected")
+		return
+	}
+	for _, rep := range reports {
+		fmt.Fprintf(w, "## %s (%s)\n\n", rep.Rule, rep.Location)
+		if rep.NeverMatches {
+			fmt.Fprintln(w, "* Condition can never match")
+		}
+		if rep.AlwaysMatches {
+			if rep.EventTypes == nil {
+				fmt.Fprintln(w, "* Condition always matches, for all event types")
+			} else {
+				types := append([]string{}, rep.EventTypes...)
+				sort.Strings(types)
+				fmt.Fprintf(w, "* Condition always matches for event types: %s\n", strings.Join(types, ", "))
+			}
+		}
+		if len(rep.DeadEventTypes) > 0 {
+			fmt.Fprintf(w, "* Warning: condition can never match for event types: %s\n", strings.Join(rep.DeadEventTypes, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// constantConditionErrors returns an error for each rule whose condition
+// never matches or always matches. Rules that never match only for some of
+// their event types are reported as warnings, and are not errors.
+func constantConditionErrors(reports []*constantConditionReport) error {
+	var err error
+	for _, rep := range reports {
+		if rep.NeverMatches {
+			err = errAppend(err, fmt.Errorf("rule `%s` has a condition that can never match", rep.Rule))
+		}
+		if rep.AlwaysMatches {
+			err = errAppend(err, fmt.Errorf("rule `%s` has a condition that always matches for its event types", rep.Rule))
+		}
+	}
+	return err
+}
+
+var constantCmd = &cobra.Command{
+	Use:   "constant",
+	Short: "Report rules whose condition can never match or always matches for its event types",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		rs, err := loadRuleset(rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+
+		reports, err := analyzeConstantConditions(rs)
+		if err != nil {
+			return err
+		}
+		writeConstantConditionReports(cmd.OutOrStdout(), reports)
+		return constantConditionErrors(reports)
+	},
+}
+
+func init() {
+	constantCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	constantCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	rootCmd.AddCommand(constantCmd)
+}
@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondSolverSatisfiable(t *testing.T) {
+	t.Parallel()
+	tests := map[string]bool{
+		"evt.type = open":                                               true,
+		"evt.type = open and evt.type = execve":                         false,
+		"evt.type in (open, execve) and evt.type = execve":              true,
+		"evt.type in (open, execve) and evt.type != execve":             true,
+		"evt.type in (open, execve) and not evt.type in (open, execve)": false,
+		"proc.name = x and not proc.name = x":                           false,
+		"proc.cmdline contains x and not proc.cmdline contains x":       false,
+		"proc.cmdline contains x and not proc.cmdline contains y":       true,
+		"evt.num=0": false,
+		"proc.name = a and (proc.name = b or proc.name = c)":   false,
+		"proc.name = a and (proc.name = b or proc.name = a)":   true,
+		"fd.net = \"10.0.0.0/8\" and fd.net = \"10.1.0.0/16\"": true,
+		"proc.aname = a and proc.aname = b":                    true,
+		"proc.aname[1] = a and proc.aname[1] = b":              false,
+		"tolower(proc.name) = a and tolower(proc.name) = b":    true,
+	}
+	for cond, expected := range tests {
+		c, err := parseCondition(cond)
+		require.NoError(t, err)
+		s := newCondSolver(true)
+		assert.Equal(t, expected, s.Satisfiable(c), cond)
+		assert.False(t, s.Exhausted)
+	}
+
+	// without reasoning on field values, comparisons are independent
+	c, err := parseCondition("ka.verb = create and ka.verb = delete")
+	require.NoError(t, err)
+	assert.True(t, newCondSolver(false).Satisfiable(c))
+}
+
+func TestAnalyzeConstantCondition(t *testing.T) {
+	t.Parallel()
+	analyze := func(cond string) *constantConditionReport {
+		c, err := parseCondition(cond)
+		require.NoError(t, err)
+		return analyzeConstantCondition(c, true)
+	}
+
+	assert.Nil(t, analyze("evt.type = execve and proc.name = x"))
+	assert.Nil(t, analyze("evt.type = execve and proc.name = x and not evt.num=0"))
+
+	rep := analyze("evt.type = open and evt.type = execve")
+	require.NotNil(t, rep)
+	assert.True(t, rep.NeverMatches)
+	assert.False(t, rep.AlwaysMatches)
+
+	rep = analyze("evt.type in (open, execve) and (proc.name = x or not proc.name = x)")
+	require.NotNil(t, rep)
+	assert.False(t, rep.NeverMatches)
+	assert.True(t, rep.AlwaysMatches)
+	assert.Equal(t, []string{"open", "execve"}, rep.EventTypes)
+
+	rep = analyze("proc.name = x or not proc.name = x")
+	require.NotNil(t, rep)
+	assert.True(t, rep.AlwaysMatches)
+	assert.Nil(t, rep.EventTypes)
+
+	rep = analyze("(evt.type = connect or (evt.type = sendto and fd.l4proto != tcp)) and fd.l4proto = tcp")
+	require.NotNil(t, rep)
+	assert.False(t, rep.NeverMatches)
+	assert.False(t, rep.AlwaysMatches)
+	assert.Equal(t, []string{"connect"}, rep.EventTypes)
+	assert.Equal(t, []string{"sendto"}, rep.DeadEventTypes)
+}
+
+func TestAnalyzeConstantConditions(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- macro: never_true
+  condition: (evt.num=0)
+
+- macro: user_known_activities
+  condition: (never_true)
+
+- rule: good
+  desc: test
+  condition: evt.type = execve and proc.name = x and not user_known_activities
+  output: test
+  priority: INFO
+
+- rule: disabled_by_override
+  desc: test
+  condition: evt.type = execve and user_known_activities
+  output: test
+  priority: INFO
+`)
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+	reports, err := analyzeConstantConditions(rs)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "disabled_by_override", reports[0].Rule)
+	assert.True(t, reports[0].NeverMatches)
+
+	var buf bytes.Buffer
+	writeConstantConditionReports(&buf, reports)
+	assert.Equal(t, "## disabled_by_override (a.yaml:14)\n\n* Condition can never match\n\n", buf.String())
+}
+
+func TestConstantConditionErrorsDeadEventTypes(t *testing.T) {
+	t.Parallel()
+	// the outbound macro and the rule are the ones of the upstream rules
+	files := testParseRulesFiles(t, `
+- list: rfc_1918_addresses
+  items: ['"10.0.0.0/8"', '"172.16.0.0/12"', '"192.168.0.0/16"']
+
+- macro: outbound
+  condition: >
+    ((evt.type = connect or
+      (evt.type in (sendto,sendmsg) and
+       fd.l4proto != tcp and fd.connected=false and fd.name_changed=true)) and
+     (fd.typechar = 4 or fd.typechar = 6) and
+     (fd.ip != "0.0.0.0" and fd.net != "127.0.0.0/8" and not fd.snet in (rfc_1918_addresses)) and
+     (evt.rawres >= 0 or evt.res = EINPROGRESS))
+
+- list: ssh_non_standard_ports
+  items: [80, 8080, 88, 443, 8443, 53, 4444]
+
+- macro: ssh_non_standard_ports_network
+  condition: (fd.sport in (ssh_non_standard_ports))
+
+- rule: Disallowed SSH Connection Non Standard Port
+  desc: test
+  condition: >
+    outbound
+    and proc.exe endswith ssh
+    and fd.l4proto=tcp
+    and ssh_non_standard_ports_network
+  output: test
+  priority: NOTICE
+`)
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+	reports, err := analyzeConstantConditions(rs)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.False(t, reports[0].NeverMatches)
+	assert.False(t, reports[0].AlwaysMatches)
+	assert.Equal(t, []string{"sendto", "sendmsg"}, reports[0].DeadEventTypes)
+	assert.NoError(t, constantConditionErrors(reports))
+
+	var buf bytes.Buffer
+	writeConstantConditionReports(&buf, reports)
+	assert.Equal(t, "## Disallowed SSH Connection Non Standard Port (a.yaml:20)\n\n* Warning: condition can never match for event types: sendto, sendmsg\n\n", buf.String())
+
+	err = constantConditionErrors([]*constantConditionReport{
+		{Rule: "never", NeverMatches: true},
+		{Rule: "always", AlwaysMatches: true},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rule `never` has a condition that can never match")
+	assert.Contains(t, err.Error(), "rule `always` has a condition that always matches for its event types")
+}
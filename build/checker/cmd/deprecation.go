@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/falcosecurity/testing/pkg/falco"
+)
+
+const (
+	maturityStableTag     = "maturity_stable"
+	maturityDeprecatedTag = "maturity_deprecated"
+)
+
+// deprecatedRuleDefined returns true if one of the given rules files defines
+// a rule with the maturity_deprecated tag, and also whether the rule is
+// defined at all.
+func deprecatedRuleDefined(files []*rulesFile, rule string) (bool, bool) {
+	found := false
+	for _, f := range files {
+		for _, item := range f.Items {
+			if item.Kind == itemKindRule && item.Rule == rule && !item.IsOverride() {
+				found = true
+				if strSliceContains(item.Tags, maturityDeprecatedTag) {
+					return true, true
+				}
+			}
+		}
+	}
+	return false, found
+}
+
+// compareRulesDeprecations returns the rules removed between two versions of
+// a rules file that skipped the deprecation step. Stable rules must first be
+// moved to the deprecated rules files with the maturity_deprecated tag, and
+// deprecated rules can be deleted only after being part of at least one
+// release with that tag. The released deprecated rules files are given in
+// release order.
+func compareRulesDeprecations(left, right *falco.RulesetDescription, deprecated, released []*rulesFile) (res []string) {
+	for v := range diffStrSet(ruleNames(left), ruleNames(right)) {
+		var tags []string
+		for _, r := range left.Rules {
+			if r.Info.Name == v {
+				tags = r.Info.Tags
+			}
+		}
+		if !strSliceContains(tags, maturityStableTag) && !strSliceContains(tags, maturityDeprecatedTag) {
+			continue
+		}
+		if ok, found := deprecatedRuleDefined(deprecated, v); ok {
+			continue
+		} else if found {
+			res = append(res, fmt.Sprintf("Rule `%s` has been moved to the deprecated rules without the %s tag", v, maturityDeprecatedTag))
+			continue
+		}
+		if ok, _ := deprecatedRuleDefined(released, v); ok {
+			continue
+		}
+		if strSliceContains(tags, maturityDeprecatedTag) {
+			res = append(res, fmt.Sprintf("Rule `%s` has been removed before being released as deprecated", v))
+		} else {
+			res = append(res, fmt.Sprintf("Rule `%s` has been removed without being deprecated for at least one release", v))
+		}
+	}
+	return
+}
@@ -0,0 +1,272 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// rulesfileDependency is a rules file providing definitions required by
+// another rules file.
+type rulesfileDependency struct {
+	Name    string   `json:"name"`
+	Path    string   `json:"path"`
+	Objects []string `json:"objects"`
+}
+
+// rulesfileDepsReport reports whether a rules file of the registry can be
+// loaded standalone and in combination with the rules files it depends on.
+type rulesfileDepsReport struct {
+	Name             string                 `json:"name"`
+	Path             string                 `json:"path"`
+	Dependencies     []*rulesfileDependency `json:"dependencies"`
+	Combination      []string               `json:"combination"`
+	StandaloneErrors []string               `json:"standalone_errors"`
+	CombinedErrors   []string               `json:"combined_errors"`
+}
+
+// checkRulesetLoad loads rules files in order and returns all the issues
+// that would prevent Falco from loading them, such as undefined macros or
+// overrides of objects that are not defined.
+func checkRulesetLoad(files []*rulesFile) []string {
+	res := []string{}
+	rs := newRuleset()
+	for _, f := range files {
+		if err := rs.Add(f); err != nil {
+			return append(res, err.Error())
+		}
+	}
+	for _, e := range append(append([]*rulesetEntry{}, rs.Macros...), rs.Rules...) {
+		if _, err := rs.ExpandCondition(e); err != nil {
+			res = append(res, fmt.Sprintf("%s: %s", e.Loc.String(), err.Error()))
+		}
+	}
+	return res
+}
+
+// analyzeRulesfilesDeps computes the dependencies between the rules files of
+// the registry, which are given in load order, and checks that each of them
+// can be loaded both standalone and along with its dependencies.
+func analyzeRulesfilesDeps(rulesfiles []registryRulesfile, files []*rulesFile) ([]*rulesfileDepsReport, error) {
+	refs, err := analyzeFileRefs(files)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	for i, f := range files {
+		index[f.Path] = i
+	}
+
+	var res []*rulesfileDepsReport
+	for i, f := range files {
+		rep := &rulesfileDepsReport{
+			Name:         rulesfiles[i].Name,
+			Path:         f.Path,
+			Dependencies: []*rulesfileDependency{},
+		}
+		deps := make(map[string]*rulesfileDependency)
+		for _, ref := range refs[i].External {
+			d, ok := deps[ref.ResolvedIn]
+			if !ok {
+				d = &rulesfileDependency{Name: rulesfiles[index[ref.ResolvedIn]].Name, Path: ref.ResolvedIn}
+				deps[ref.ResolvedIn] = d
+			}
+			obj := depGraphNodeID(ref.Kind, ref.Name)
+			if !strSliceContains(d.Objects, obj) {
+				d.Objects = append(d.Objects, obj)
+			}
+		}
+		for _, other := range files {
+			if d, ok := deps[other.Path]; ok {
+				rep.Dependencies = append(rep.Dependencies, d)
+			}
+		}
+
+		// the combination includes all the transitive dependencies, in load order
+		needed := map[int]bool{i: true}
+		stack := []int{i}
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, ref := range refs[cur].External {
+				if j := index[ref.ResolvedIn]; !needed[j] {
+					needed[j] = true
+					stack = append(stack, j)
+				}
+			}
+		}
+		var combination []*rulesFile
+		for j, other := range files {
+			if needed[j] {
+				combination = append(combination, other)
+				rep.Combination = append(rep.Combination, other.Path)
+			}
+		}
+
+		rep.StandaloneErrors = checkRulesetLoad([]*rulesFile{f})
+		if len(combination) > 1 {
+			rep.CombinedErrors = checkRulesetLoad(combination)
+		} else {
+			rep.CombinedErrors = rep.StandaloneErrors
+		}
+		res = append(res, rep)
+	}
+	return res, nil
+}
+
+func writeRulesfilesDepsReports(w io.Writer, reports []*rulesfileDepsReport) {
+	status := func(errs []string) string {
+		if len(errs) > 0 {
+			return "FAILED"
+		}
+		return "OK"
+	}
+	for _, rep := range reports {
+		fmt.Fprintf(w, "## %s (%s)\n\n", rep.Name, rep.Path)
+		fmt.Fprintf(w, "Standalone: %s\n", status(rep.StandaloneErrors))
+		for _, e := range rep.StandaloneErrors {
+			fmt.Fprintln(w, "* "+e)
+		}
+		if len(rep.Dependencies) == 0 {
+			fmt.Fprintln(w)
+			continue
+		}
+		fmt.Fprintf(w, "Combined with dependencies (%s): %s\n", strings.Join(rep.Combination, ", "), status(rep.CombinedErrors))
+		for _, e := range rep.CombinedErrors {
+			fmt.Fprintln(w, "* "+e)
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "Required definitions:")
+		for _, d := range rep.Dependencies {
+			fmt.Fprintf(w, "* %s: %s\n", d.Name, strings.Join(d.Objects, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Check that the rules files of the registry can be loaded standalone and along with their dependencies",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		names, err := cmd.Flags().GetStringArray("rulesfile")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+
+		falcoImage, err := cmd.Flags().GetString("falco-image")
+		if err != nil {
+			return err
+		}
+
+		reg, err := loadRegistry(registryPath)
+		if err != nil {
+			return err
+		}
+
+		rulesfiles := reg.ActiveRulesfiles()
+		var files []*rulesFile
+		for _, rf := range rulesfiles {
+			f, err := loadRulesFile(rf.Path)
+			if err != nil {
+				return err
+			}
+			files = append(files, f)
+		}
+
+		reports, err := analyzeRulesfilesDeps(rulesfiles, files)
+		if err != nil {
+			return err
+		}
+		if len(names) > 0 {
+			var filtered []*rulesfileDepsReport
+			for _, rep := range reports {
+				if strSliceContains(names, rep.Name) {
+					filtered = append(filtered, rep)
+				}
+			}
+			reports = filtered
+		}
+
+		// optionally double-check the results with Falco
+		if len(falcoImage) > 0 {
+			for _, rep := range reports {
+				if err := validateRulesFiles(falcoImage, "", []string{rep.Path}, nil, cmd.ErrOrStderr(), cmd.ErrOrStderr()); err != nil && len(rep.StandaloneErrors) == 0 {
+					rep.StandaloneErrors = append(rep.StandaloneErrors, "falco: "+err.Error())
+				}
+				if len(rep.Combination) > 1 {
+					if err := validateRulesFiles(falcoImage, "", rep.Combination, nil, cmd.ErrOrStderr(), cmd.ErrOrStderr()); err != nil {
+						rep.CombinedErrors = append(rep.CombinedErrors, "falco: "+err.Error())
+					}
+				}
+			}
+		}
+
+		switch format {
+		case "text":
+			writeRulesfilesDepsReports(cmd.OutOrStdout(), reports)
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(reports); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+
+		err = nil
+		for _, rep := range reports {
+			if len(rep.CombinedErrors) > 0 {
+				err = errAppend(err, fmt.Errorf("%s cannot be loaded along with its dependencies", rep.Name))
+			} else if strict && len(rep.StandaloneErrors) > 0 {
+				err = errAppend(err, fmt.Errorf("%s cannot be loaded standalone", rep.Name))
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	depsCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	depsCmd.Flags().StringArray("rulesfile", []string{}, "Only report the rules files of the registry with the given names")
+	depsCmd.Flags().StringP("output", "o", "text", "Output format, one of: text, json")
+	depsCmd.Flags().Bool("strict", false, "Fail if any rules file cannot be loaded standalone")
+	depsCmd.Flags().StringP("falco-image", "i", "", "Docker image of Falco to be used for double-checking the results (skipped if empty)")
+	rootCmd.AddCommand(depsCmd)
+}
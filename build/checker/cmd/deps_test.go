@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeRulesfilesDeps(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- list: shells
+  items: [bash]
+
+- macro: spawned_process
+  condition: evt.type = execve
+
+- rule: rule_a
+  desc: rule a
+  condition: spawned_process
+  output: a
+  priority: INFO
+`, `
+- macro: shell_procs
+  condition: proc.name in (shells)
+
+- rule: rule_b
+  desc: rule b
+  condition: spawned_process and shell_procs
+  output: b
+  priority: INFO
+`, `
+- rule: rule_b
+  condition: and not proc.pname in (shells)
+  override:
+    condition: append
+`)
+	rulesfiles := []registryRulesfile{{Name: "a-rules"}, {Name: "b-rules"}, {Name: "c-rules"}}
+	reports, err := analyzeRulesfilesDeps(rulesfiles, files)
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+
+	assert.Empty(t, reports[0].Dependencies)
+	assert.Empty(t, reports[0].StandaloneErrors)
+	assert.Equal(t, []string{"a.yaml"}, reports[0].Combination)
+
+	b := reports[1]
+	require.Len(t, b.Dependencies, 1)
+	assert.Equal(t, "a-rules", b.Dependencies[0].Name)
+	assert.Equal(t, []string{"list:shells", "macro:spawned_process"}, b.Dependencies[0].Objects)
+	assert.Equal(t, []string{"a.yaml", "b.yaml"}, b.Combination)
+	assert.Len(t, b.StandaloneErrors, 1)
+	assert.Empty(t, b.CombinedErrors)
+
+	c := reports[2]
+	require.Len(t, c.Dependencies, 2)
+	assert.Equal(t, "a-rules", c.Dependencies[0].Name)
+	assert.Equal(t, []string{"list:shells"}, c.Dependencies[0].Objects)
+	assert.Equal(t, "b-rules", c.Dependencies[1].Name)
+	assert.Equal(t, []string{"rule:rule_b"}, c.Dependencies[1].Objects)
+	assert.Equal(t, []string{"a.yaml", "b.yaml", "c.yaml"}, c.Combination)
+	assert.Len(t, c.StandaloneErrors, 1)
+	assert.Empty(t, c.CombinedErrors)
+
+	var buf bytes.Buffer
+	writeRulesfilesDepsReports(&buf, reports)
+	assert.Contains(t, buf.String(), "Combined with dependencies (a.yaml, b.yaml): OK\n")
+	assert.Contains(t, buf.String(), "* a-rules: list:shells, macro:spawned_process\n")
+}
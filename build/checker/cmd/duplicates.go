@@ -0,0 +1,460 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	duplicateKindExact    = "duplicate"
+	duplicateKindSubsumed = "subsumed"
+	duplicateKindSimilar  = "similar"
+)
+
+// weights of the condition, event types, and output fields in the
+// similarity score of two rules
+const (
+	similarityConditionWeight = 0.6
+	similarityEventsWeight    = 0.2
+	similarityOutputWeight    = 0.2
+)
+
+// subsumptionSolverBudget is the maximum number of solver steps spent on
+// each pair of rules, lower than the default one since all the pairs of rules
+// with compatible event types are evaluated.
+const subsumptionSolverBudget = 10000
+
+// ruleFingerprint is the normalized representation of a rule used to find
+// duplicate and overlapping rules.
+type ruleFingerprint struct {
+	Entry *rulesetEntry
+	// Cond is the expanded condition of the rule
+	Cond condExpr
+	// Canonical is the expanded condition in a form that does not depend on
+	// the order of operands and list values
+	Canonical string
+	// EventTypes are the sorted event types the rule applies to, or nil if the
+	// rule applies to all of them
+	EventTypes []string
+	// Checks are the field comparisons of the condition, split by value
+	Checks map[string]bool
+	// OutputFields are the fields referenced by the rule output
+	OutputFields map[string]bool
+}
+
+// duplicateRulesReport reports a rule that is a duplicate of another, that
+// only matches events also matched by another, or that is very similar to
+// another.
+type duplicateRulesReport struct {
+	Kind          string  `json:"kind"`
+	Rule          string  `json:"rule"`
+	Location      string  `json:"location"`
+	Other         string  `json:"other"`
+	OtherLocation string  `json:"other_location"`
+	Score         float64 `json:"score"`
+	// SameOutputFields is true if both rules output the same fields
+	SameOutputFields bool `json:"same_output_fields"`
+}
+
+// canonicalCondition returns a condition in a form in which the operands of
+// and and or expressions, and the values of list operators, are sorted and
+// deduplicated, so that equivalent rewrites of a condition are equal.
+func canonicalCondition(e condExpr) string {
+	switch v := e.(type) {
+	case *condAndExpr, *condOrExpr:
+		exprs, isAnd := flattenCondition(v)
+		var parts []string
+		for _, c := range exprs {
+			s := canonicalCondition(c)
+			if !strSliceContains(parts, s) {
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) == 1 {
+			return parts[0]
+		}
+		sort.Strings(parts)
+		if isAnd {
+			return "(" + strings.Join(parts, " and ") + ")"
+		}
+		return "(" + strings.Join(parts, " or ") + ")"
+	case *condNotExpr:
+		if n, ok := v.Expr.(*condNotExpr); ok {
+			return canonicalCondition(n.Expr)
+		}
+		return "not " + canonicalCondition(v.Expr)
+	case *condCheckExpr:
+		op, values := canonicalCheck(v)
+		if condUnaryOperators[op] {
+			return v.Field.String() + " " + op
+		}
+		return v.Field.String() + " " + op + " (" + strings.Join(values, ", ") + ")"
+	}
+	return e.String()
+}
+
+// canonicalCheck returns the operator and the sorted values of a field
+// comparison, considering equality checks as list checks with one value.
+func canonicalCheck(c *condCheckExpr) (string, []string) {
+	op := c.Op
+	switch op {
+	case "=", "==":
+		op = "in"
+	}
+	var values []string
+	for _, v := range c.Values {
+		s := v.Text
+		if v.Field != nil {
+			s = "val(" + v.Field.String() + ")"
+		}
+		if !strSliceContains(values, s) {
+			values = append(values, s)
+		}
+	}
+	if condListOperators[op] {
+		sort.Strings(values)
+	}
+	return op, values
+}
+
+// conditionCheckFeatures returns the field comparisons of a condition, with
+// list comparisons split into one comparison per value, so that conditions
+// differing only by some values share most of their features. Comparisons
+// evaluated within an odd number of `not` operators are prefixed with `not`.
+func conditionCheckFeatures(e condExpr) map[string]bool {
+	res := make(map[string]bool)
+	var visit func(e condExpr, negated bool)
+	visit = func(e condExpr, negated bool) {
+		prefix := ""
+		if negated {
+			prefix = "not "
+		}
+		switch v := e.(type) {
+		case *condAndExpr, *condOrExpr:
+			exprs, _ := flattenCondition(v)
+			for _, c := range exprs {
+				visit(c, negated)
+			}
+		case *condNotExpr:
+			visit(v.Expr, !negated)
+		case *condCheckExpr:
+			op, values := canonicalCheck(v)
+			if condUnaryOperators[op] {
+				res[prefix+v.Field.String()+" "+op] = true
+			}
+			for _, val := range values {
+				res[prefix+v.Field.String()+" "+op+" "+val] = true
+			}
+		default:
+			res[prefix+e.String()] = true
+		}
+	}
+	visit(e, false)
+	return res
+}
+
+// outputFields returns the fields referenced by a rule output, in order of
+// appearance and including the duplicated ones.
+func outputFields(output string) []condField {
+	var res []condField
+	for _, m := range outputFieldRefRegex.FindAllStringSubmatch(output, -1) {
+		f := condField{Name: m[2], Transformer: m[1]}
+		if len(m[3]) > 0 {
+			f.Arg = m[3][1 : len(m[3])-1]
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+// outputFieldRefs returns the fields referenced by a rule output.
+func outputFieldRefs(output string) map[string]bool {
+	res := make(map[string]bool)
+	for _, f := range outputFields(output) {
+		res[f.String()] = true
+	}
+	return res
+}
+
+// newRuleFingerprint returns the fingerprint of a rule of a ruleset.
+func newRuleFingerprint(rs *ruleset, r *rulesetEntry) (*ruleFingerprint, error) {
+	c, err := rs.ExpandCondition(r)
+	if err != nil {
+		return nil, err
+	}
+	res := &ruleFingerprint{
+		Entry:        r,
+		Cond:         c,
+		Canonical:    canonicalCondition(c),
+		Checks:       conditionCheckFeatures(c),
+		OutputFields: outputFieldRefs(r.Output),
+	}
+	if r.RuleSource() == defaultRuleSource && restrictsEventTypes(c, false) {
+		res.EventTypes = conditionEventTypes(c)
+		sort.Strings(res.EventTypes)
+	}
+	return res, nil
+}
+
+// jaccardIndex returns the size of the intersection of two sets divided by
+// the size of their union, considering two empty sets as equal.
+func jaccardIndex(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	common := 0
+	for k := range a {
+		if b[k] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// eventTypesSubset returns true if a rule applying to the event types a
+// applies to a subset of the event types b, where nil means all event types.
+func eventTypesSubset(a, b []string) bool {
+	if b == nil {
+		return true
+	}
+	if a == nil {
+		return false
+	}
+	for _, t := range a {
+		if !strSliceContains(b, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleSimilarity returns a score between 0 and 1 measuring how similar two
+// rules are, combining the similarity of their field comparisons, of their
+// event types, and of their output fields.
+func ruleSimilarity(a, b *ruleFingerprint) float64 {
+	events := 0.0
+	if a.EventTypes == nil && b.EventTypes == nil {
+		events = 1
+	} else if a.EventTypes != nil && b.EventTypes != nil {
+		events = jaccardIndex(strSliceToMap(a.EventTypes), strSliceToMap(b.EventTypes))
+	}
+	return similarityConditionWeight*jaccardIndex(a.Checks, b.Checks) +
+		similarityEventsWeight*events +
+		similarityOutputWeight*jaccardIndex(a.OutputFields, b.OutputFields)
+}
+
+// ruleSubsumes returns true if the condition of a rule matches all the
+// events matched by the condition of another one. Exceptions are not
+// considered. Returns false if the evaluation is inconclusive.
+func ruleSubsumes(broad, narrow *ruleFingerprint) bool {
+	if !eventTypesSubset(narrow.EventTypes, broad.EventTypes) {
+		return false
+	}
+	s := newCondSolver(broad.Entry.RuleSource() == defaultRuleSource)
+	s.budget = subsumptionSolverBudget
+	res := !s.Satisfiable(narrow.Cond, &condNotExpr{Expr: broad.Cond})
+	return res && !s.Exhausted
+}
+
+// analyzeDuplicateRules compares all the pairs of rules of a ruleset with
+// the same source, and reports the exact duplicates, the rules whose
+// condition is subsumed by the one of another rule, and the pairs whose
+// similarity score is at least the given threshold. In each report, the
+// rule is the one loaded last of the pair, or the narrower one for
+// subsumptions.
+func analyzeDuplicateRules(rs *ruleset, threshold float64, skipDisabled bool) ([]*duplicateRulesReport, error) {
+	var fps []*ruleFingerprint
+	for _, r := range rs.Rules {
+		if skipDisabled && !r.IsEnabled() {
+			continue
+		}
+		fp, err := newRuleFingerprint(rs, r)
+		if err != nil {
+			return nil, err
+		}
+		fps = append(fps, fp)
+	}
+
+	var res []*duplicateRulesReport
+	for j, b := range fps {
+		for _, a := range fps[:j] {
+			if a.Entry.RuleSource() != b.Entry.RuleSource() {
+				continue
+			}
+			rep := &duplicateRulesReport{
+				Rule:             b.Entry.Name(),
+				Location:         b.Entry.Loc.String(),
+				Other:            a.Entry.Name(),
+				OtherLocation:    a.Entry.Loc.String(),
+				Score:            ruleSimilarity(a, b),
+				SameOutputFields: jaccardIndex(a.OutputFields, b.OutputFields) == 1,
+			}
+			switch {
+			case a.Canonical == b.Canonical:
+				rep.Kind = duplicateKindExact
+			case ruleSubsumes(a, b):
+				rep.Kind = duplicateKindSubsumed
+			case ruleSubsumes(b, a):
+				rep.Kind = duplicateKindSubsumed
+				rep.Rule, rep.Other = rep.Other, rep.Rule
+				rep.Location, rep.OtherLocation = rep.OtherLocation, rep.Location
+			case rep.Score >= threshold:
+				rep.Kind = duplicateKindSimilar
+			default:
+				continue
+			}
+			res = append(res, rep)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Score > res[j].Score })
+	return res, nil
+}
+
+func writeDuplicateRulesReports(w io.Writer, reports []*duplicateRulesReport) {
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "No issues detected")
+		return
+	}
+	sections := []struct {
+		kind, title string
+	}{
+		{duplicateKindExact, "**Duplicate** rules:"},
+		{duplicateKindSubsumed, "**Subsumed** rules:"},
+		{duplicateKindSimilar, "**Similar** rules:"},
+	}
+	for _, s := range sections {
+		var lines []string
+		for _, rep := range reports {
+			if rep.Kind != s.kind {
+				continue
+			}
+			var line string
+			switch rep.Kind {
+			case duplicateKindExact:
+				line = fmt.Sprintf("* Rule `%s` (%s) has the same condition of rule `%s` (%s)", rep.Rule, rep.Location, rep.Other, rep.OtherLocation)
+				if rep.SameOutputFields {
+					line += " and outputs the same fields"
+				}
+			case duplicateKindSubsumed:
+				line = fmt.Sprintf("* Rule `%s` (%s) only matches events also matched by rule `%s` (%s)", rep.Rule, rep.Location, rep.Other, rep.OtherLocation)
+			default:
+				line = fmt.Sprintf("* Rules `%s` (%s) and `%s` (%s)", rep.Rule, rep.Location, rep.Other, rep.OtherLocation)
+			}
+			lines = append(lines, fmt.Sprintf("%s, similarity %.0f%%", line, rep.Score*100))
+		}
+		if len(lines) > 0 {
+			fmt.Fprintln(w, s.title)
+			fmt.Fprintln(w, strings.Join(lines, "\n"))
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+var duplicatesCmd = &cobra.Command{
+	Use:   "duplicates",
+	Short: "Report duplicate rules, rules subsumed by other ones, and pairs of very similar rules",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		threshold, err := cmd.Flags().GetFloat64("threshold")
+		if err != nil {
+			return err
+		}
+
+		skipDisabled, err := cmd.Flags().GetBool("skip-disabled")
+		if err != nil {
+			return err
+		}
+
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+
+		if threshold < 0 || threshold > 1 {
+			return fmt.Errorf("similarity threshold must be between 0 and 1")
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		rs, err := loadRuleset(rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+
+		reports, err := analyzeDuplicateRules(rs, threshold, skipDisabled)
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case "text":
+			writeDuplicateRulesReports(cmd.OutOrStdout(), reports)
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(reports); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+
+		if strict {
+			for _, rep := range reports {
+				if rep.Kind != duplicateKindSimilar {
+					err = errAppend(err, fmt.Errorf("rule `%s` is %s by rule `%s`", rep.Rule, rep.Kind, rep.Other))
+				}
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	duplicatesCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	duplicatesCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	duplicatesCmd.Flags().StringP("output", "o", "text", "Output format, one of: text, json")
+	duplicatesCmd.Flags().Float64("threshold", 0.8, "Minimum similarity score, between 0 and 1, of the reported pairs of similar rules")
+	duplicatesCmd.Flags().Bool("skip-disabled", false, "Skip the rules that are disabled at default")
+	duplicatesCmd.Flags().Bool("strict", false, "Fail if any rule is a duplicate of or is subsumed by another rule")
+	rootCmd.AddCommand(duplicatesCmd)
+}
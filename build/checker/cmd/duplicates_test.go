@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalCondition(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"proc.name = sh":                        "proc.name in (sh)",
+		"proc.name in (sh, bash, sh)":           "proc.name in (bash, sh)",
+		"b = 1 and (a = 1 and c = 2)":           "(a in (1) and b in (1) and c in (2))",
+		"(c = 2 or a = 1) and not not b exists": "((a in (1) or c in (2)) and b exists)",
+		"m and m":                               "m",
+		"fd.name contains \"/tmp\"":             "fd.name contains (/tmp)",
+	}
+	for cond, expected := range tests {
+		c, err := parseCondition(cond)
+		require.NoError(t, err, cond)
+		assert.Equal(t, expected, canonicalCondition(c), cond)
+	}
+
+	a, err := parseCondition("evt.type = execve and proc.name in (sh, bash) and not user.name = root")
+	require.NoError(t, err)
+	b, err := parseCondition("not user.name = root and proc.name in (bash, sh) and evt.type in (execve)")
+	require.NoError(t, err)
+	assert.Equal(t, canonicalCondition(a), canonicalCondition(b))
+}
+
+func TestAnalyzeDuplicateRules(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- list: shell_binaries
+  items: [bash, sh]
+
+- macro: spawned_process
+  condition: evt.type = execve
+
+- rule: shell_spawned
+  desc: test
+  condition: spawned_process and proc.name in (shell_binaries)
+  output: shell (proc=%proc.name user=%user.name)
+  priority: NOTICE
+
+- rule: sh_spawned
+  desc: test
+  condition: spawned_process and proc.name = sh and user.name = root
+  output: sh (proc=%proc.name)
+  priority: NOTICE
+
+- rule: write_etc
+  desc: test
+  condition: evt.type = open and fd.name startswith /etc and proc.name = vi
+  output: write (file=%fd.name proc=%proc.name)
+  priority: ERROR
+
+- rule: write_etc_emacs
+  desc: test
+  condition: evt.type = open and fd.name startswith /etc and proc.name = emacs
+  output: write (file=%fd.name proc=%proc.name)
+  priority: ERROR
+
+- rule: audit
+  desc: test
+  condition: ka.verb = create
+  output: audit %ka.verb
+  priority: INFO
+  source: k8s_audit
+`, `
+- rule: shell_spawned_copy
+  desc: test
+  condition: proc.name in (sh, bash) and evt.type in (execve)
+  output: copy (proc=%proc.name user=%user.name)
+  priority: NOTICE
+
+- rule: audit_copy
+  desc: test
+  condition: evt.type = execve and proc.name in (shell_binaries)
+  output: audit %ka.verb
+  priority: INFO
+  source: k8s_audit
+`)
+	rs := newRuleset()
+	for _, f := range files {
+		require.NoError(t, rs.Add(f))
+	}
+
+	reports, err := analyzeDuplicateRules(rs, 0.7, false)
+	require.NoError(t, err)
+	kinds := make(map[string]string)
+	for _, rep := range reports {
+		kinds[rep.Rule+" "+rep.Other] = rep.Kind
+	}
+	assert.Equal(t, map[string]string{
+		"sh_spawned shell_spawned":         duplicateKindSubsumed,
+		"shell_spawned_copy shell_spawned": duplicateKindExact,
+		"sh_spawned shell_spawned_copy":    duplicateKindSubsumed,
+		"write_etc_emacs write_etc":        duplicateKindSimilar,
+	}, kinds)
+	for _, rep := range reports {
+		if rep.Kind == duplicateKindExact {
+			assert.True(t, rep.SameOutputFields)
+			assert.Equal(t, "b.yaml:2", rep.Location)
+		}
+		if rep.Kind == duplicateKindSimilar {
+			assert.InDelta(t, 0.7, rep.Score, 0.01)
+		}
+	}
+
+	reports, err = analyzeDuplicateRules(rs, 0.9, false)
+	require.NoError(t, err)
+	assert.Len(t, reports, 3)
+
+	var buf bytes.Buffer
+	writeDuplicateRulesReports(&buf, reports)
+	assert.Contains(t, buf.String(), "**Duplicate** rules:\n* Rule `shell_spawned_copy` (b.yaml:2) has the same condition of rule `shell_spawned` (a.yaml:8) and outputs the same fields, similarity 100%\n")
+	assert.Contains(t, buf.String(), "* Rule `sh_spawned` (a.yaml:14) only matches events also matched by rule `shell_spawned` (a.yaml:8)")
+	assert.NotContains(t, buf.String(), "**Similar**")
+
+	buf.Reset()
+	writeDuplicateRulesReports(&buf, nil)
+	assert.Equal(t, "No issues detected\n", buf.String())
+}
@@ -0,0 +1,516 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/falcosecurity/testing/pkg/falco"
+	"github.com/falcosecurity/testing/pkg/run"
+	"github.com/spf13/cobra"
+)
+
+const (
+	engineFeatureField       = "field"
+	engineFeatureOperator    = "operator"
+	engineFeatureTransformer = "transformer"
+	engineFeatureEvent       = "event"
+)
+
+// baseEngineVersion is the first engine version following semver, shipped
+// with Falco 0.37.0. Features that are not listed in the engine version
+// table are assumed to be supported since then.
+const baseEngineVersion = "0.31.0"
+
+// engineVersionTable maps the kind and name of the features used by rules
+// (fields, operators, transformers, event types) to the engine version that
+// introduced them.
+type engineVersionTable map[string]map[string]string
+
+// defaultEngineVersionTable only lists the features introduced after the
+// base engine version. It is maintained by hand from the Falco changelogs,
+// and it's only complete for operators and transformers, which Falco can't
+// list. The fields and event types introduced by each Falco version are
+// probed from its Docker image with the --falco-versions flag, which the CI
+// uses with the versions of .github/FALCO_VERSIONS.
+var defaultEngineVersionTable = engineVersionTable{
+	engineFeatureField: {
+		"fs.path.name":      "0.40.0",
+		"fs.path.nameraw":   "0.40.0",
+		"fs.path.source":    "0.40.0",
+		"fs.path.sourceraw": "0.40.0",
+		"fs.path.target":    "0.40.0",
+		"fs.path.targetraw": "0.40.0",
+	},
+	engineFeatureOperator: {
+		"regex": "0.40.0",
+	},
+	engineFeatureTransformer: {
+		"tolower":  "0.40.0",
+		"toupper":  "0.40.0",
+		"b64":      "0.40.0",
+		"val":      "0.43.0",
+		"basename": "0.43.0",
+		"len":      "0.47.0",
+	},
+	engineFeatureEvent: {},
+}
+
+// Lookup returns the engine version that introduced a feature, or the base
+// engine version if the feature is not in the table.
+func (t engineVersionTable) Lookup(kind, name string) (semver.Version, error) {
+	if v, ok := t[kind][name]; ok {
+		return parseEngineVersion(v)
+	}
+	return semver.MustParse(baseEngineVersion), nil
+}
+
+// parseEngineVersion parses a required engine version, which is either in
+// semver format or a number for the legacy versions preceding Falco 0.37.0.
+func parseEngineVersion(s string) (semver.Version, error) {
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return semver.Version{Major: 0, Minor: n, Patch: 0}, nil
+	}
+	v, err := semver.Parse(s)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("invalid engine version '%s': %s", s, err.Error())
+	}
+	return v, nil
+}
+
+// engineFeatureUse is the usage of a feature by a rules file item.
+type engineFeatureUse struct {
+	Kind string
+	Name string
+	Item *rulesFileItem
+}
+
+// itemEngineFeatures returns the features used by the condition, output,
+// and exceptions of a rules file item. Lists referenced by the condition are
+// resolved with the given lookup function, so that the event types they
+// contain are accounted for.
+func itemEngineFeatures(item *rulesFileItem, list func(string) ([]string, bool)) ([]*engineFeatureUse, error) {
+	var res []*engineFeatureUse
+	use := func(kind, name string) {
+		res = append(res, &engineFeatureUse{Kind: kind, Name: name, Item: item})
+	}
+	useField := func(f condField) {
+		use(engineFeatureField, f.Name)
+		if len(f.Transformer) > 0 {
+			use(engineFeatureTransformer, f.Transformer)
+		}
+	}
+
+	if item.Keys["condition"] && (item.Kind == itemKindMacro || item.Kind == itemKindRule) {
+		c, err := parseConditionFragment(item.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s `%s` has an invalid condition: %s", item.Loc.String(), item.Kind, item.Name(), err.Error())
+		}
+		for _, check := range conditionChecks(c) {
+			useField(check.Field)
+			use(engineFeatureOperator, check.Op)
+			values := check.Values
+			if condListOperators[check.Op] {
+				values = expandListValues(values, list, nil)
+			}
+			for _, v := range values {
+				if v.Field != nil {
+					useField(*v.Field)
+				} else if check.Field.Name == "evt.type" && len(check.Field.Transformer) == 0 {
+					use(engineFeatureEvent, v.Text)
+				}
+			}
+		}
+	}
+
+	for _, f := range outputFields(item.Output) {
+		useField(f)
+	}
+
+	for _, e := range item.Exceptions {
+		for _, f := range exceptionStrings(e.Fields) {
+			use(engineFeatureField, f)
+		}
+		for _, c := range exceptionStrings(e.Comps) {
+			use(engineFeatureOperator, c)
+		}
+	}
+	return res, nil
+}
+
+// exceptionStrings returns the strings of an exception key that can be
+// either a single string or a list of strings.
+func exceptionStrings(v interface{}) []string {
+	switch s := v.(type) {
+	case string:
+		return []string{s}
+	case []interface{}:
+		var res []string
+		for _, i := range s {
+			if str, ok := i.(string); ok {
+				res = append(res, str)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// engineRequirement is a feature used by a rules file that requires an
+// engine version more recent than the base one.
+type engineRequirement struct {
+	Kind    string
+	Name    string
+	Version semver.Version
+	Items   []*rulesFileItem
+}
+
+func (r *engineRequirement) String() string {
+	var users []string
+	for _, i := range r.Items {
+		users = append(users, fmt.Sprintf("%s `%s` (%s)", i.Kind, i.Name(), i.Loc.String()))
+	}
+	return fmt.Sprintf("%s `%s` requires %s, used by %s", capitalize(r.Kind), r.Name, r.Version.String(), strings.Join(users, ", "))
+}
+
+// engineVersionReport compares the engine version declared by a rules file
+// with the one required by the features it uses.
+type engineVersionReport struct {
+	Path     string
+	Declared string
+	// Required is the highest engine version required by the features used
+	// by the rules file, or zero if all of them are supported by the base one
+	Required     semver.Version
+	Requirements []*engineRequirement
+}
+
+// Satisfied returns true if the declared engine version is not lower than
+// the required one.
+func (r *engineVersionReport) Satisfied() bool {
+	if len(r.Declared) == 0 {
+		return len(r.Requirements) == 0
+	}
+	v, err := parseEngineVersion(r.Declared)
+	return err == nil && v.GTE(r.Required)
+}
+
+// analyzeEngineVersions loads the given rules files in order, and computes
+// for each of them the minimum engine version required by the features it
+// uses.
+func analyzeEngineVersions(files []*rulesFile, table engineVersionTable) ([]*engineVersionReport, error) {
+	var res []*engineVersionReport
+	rs := newRuleset()
+	for _, f := range files {
+		if err := rs.Add(f); err != nil {
+			return nil, err
+		}
+		rep := &engineVersionReport{Path: f.Path}
+		reqs := make(map[string]*engineRequirement)
+		for _, item := range f.Items {
+			if item.Kind == itemKindEngine {
+				rep.Declared = item.RequiredEngineVersion
+				continue
+			}
+			uses, err := itemEngineFeatures(item, rs.ListLookup())
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range uses {
+				v, err := table.Lookup(u.Kind, u.Name)
+				if err != nil {
+					return nil, err
+				}
+				if v.LTE(semver.MustParse(baseEngineVersion)) {
+					continue
+				}
+				id := u.Kind + ":" + u.Name
+				r, ok := reqs[id]
+				if !ok {
+					r = &engineRequirement{Kind: u.Kind, Name: u.Name, Version: v}
+					reqs[id] = r
+					rep.Requirements = append(rep.Requirements, r)
+				}
+				if len(r.Items) == 0 || r.Items[len(r.Items)-1] != item {
+					r.Items = append(r.Items, item)
+				}
+				if v.GT(rep.Required) {
+					rep.Required = v
+				}
+			}
+		}
+		if len(rep.Declared) > 0 {
+			if _, err := parseEngineVersion(rep.Declared); err != nil {
+				return nil, fmt.Errorf("%s: %s", f.Path, err.Error())
+			}
+		}
+		sort.SliceStable(rep.Requirements, func(i, j int) bool {
+			return rep.Requirements[i].Version.GT(rep.Requirements[j].Version)
+		})
+		res = append(res, rep)
+	}
+	return res, nil
+}
+
+func writeEngineVersionReports(w io.Writer, reports []*engineVersionReport) {
+	for _, rep := range reports {
+		fmt.Fprintln(w, "## "+rep.Path)
+		fmt.Fprintln(w)
+		declared := rep.Declared
+		if len(declared) == 0 {
+			declared = "(none)"
+		}
+		fmt.Fprintf(w, "Declared required_engine_version: %s\n", declared)
+		if len(rep.Requirements) == 0 {
+			fmt.Fprintf(w, "Minimum required_engine_version: %s or lower\n", baseEngineVersion)
+		} else {
+			fmt.Fprintf(w, "Minimum required_engine_version: %s\n", rep.Required.String())
+		}
+		fmt.Fprintln(w)
+		if len(rep.Requirements) > 0 {
+			fmt.Fprintln(w, "Features requiring an engine version above "+baseEngineVersion+":")
+			for _, r := range rep.Requirements {
+				fmt.Fprintln(w, "* "+r.String())
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+var (
+	falcoVersionEngineRegex = regexp.MustCompile(`(?m)^Engine:\s+(\S+)`)
+	falcoListFieldRegex     = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9_]*\.[a-zA-Z0-9_.]+)`)
+	falcoListEventRegex     = regexp.MustCompile(`(?:^|[\s<>])([a-z_][a-z0-9_]*)\(`)
+)
+
+// runFalcoProbe runs Falco with the given arguments and returns its output.
+func runFalcoProbe(runner run.Runner, args ...string) (string, error) {
+	res := falco.Test(runner, falco.WithArgs(args...))
+	if res.Err() != nil {
+		return "", res.Err()
+	}
+	if res.ExitCode() != 0 {
+		return "", fmt.Errorf("unexpected exit code (%d) running falco %s", res.ExitCode(), strings.Join(args, " "))
+	}
+	return res.Stdout(), nil
+}
+
+// engineProbe lists the fields and event types supported by a Falco image.
+type engineProbe struct {
+	Version semver.Version
+	Fields  []string
+	Events  []string
+}
+
+// mergeEngineProbes builds an engine version table mapping the fields and
+// event types of the given probes to the lowest engine version supporting
+// them, whereas operators and transformers, which Falco can't list, are taken
+// from the given table. Features supported by the oldest probed version may
+// have been introduced before it, so their version is taken from the given
+// table, or assumed to be the base engine version if not listed there.
+func mergeEngineProbes(probes []*engineProbe, table engineVersionTable) engineVersionTable {
+	res := engineVersionTable{
+		engineFeatureField:       make(map[string]string),
+		engineFeatureOperator:    table[engineFeatureOperator],
+		engineFeatureTransformer: table[engineFeatureTransformer],
+		engineFeatureEvent:       make(map[string]string),
+	}
+	if len(probes) == 0 {
+		return res
+	}
+	oldest := probes[0].Version
+	for _, p := range probes {
+		if p.Version.LT(oldest) {
+			oldest = p.Version
+		}
+	}
+
+	base := semver.MustParse(baseEngineVersion)
+	found := make(map[string]semver.Version)
+	set := func(kind, name string, v semver.Version) {
+		id := kind + ":" + name
+		if cur, ok := found[id]; ok && cur.LTE(v) {
+			return
+		}
+		found[id] = v
+		if v.EQ(oldest) {
+			if prev, ok := table[kind][name]; ok {
+				res[kind][name] = prev
+				return
+			}
+			v = base
+		}
+		if v.GT(base) {
+			res[kind][name] = v.String()
+		} else {
+			delete(res[kind], name)
+		}
+	}
+	for _, p := range probes {
+		for _, f := range p.Fields {
+			set(engineFeatureField, f, p.Version)
+		}
+		for _, e := range p.Events {
+			set(engineFeatureEvent, e, p.Version)
+		}
+	}
+	return res
+}
+
+// probeEngineVersionTable builds an engine version table by listing the
+// fields and event types supported by each of the given Falco images.
+func probeEngineVersionTable(images []string, table engineVersionTable) (engineVersionTable, error) {
+	var probes []*engineProbe
+	for _, image := range images {
+		runner, err := run.NewDockerRunner(image, defaultFalcoDockerEntrypoint, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := runFalcoProbe(runner, "--version")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", image, err.Error())
+		}
+		m := falcoVersionEngineRegex.FindStringSubmatch(out)
+		if m == nil {
+			return nil, fmt.Errorf("%s: can't find the engine version in the falco --version output", image)
+		}
+		v, err := parseEngineVersion(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", image, err.Error())
+		}
+		probe := &engineProbe{Version: v}
+
+		out, err = runFalcoProbe(runner, "--list", "-N")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", image, err.Error())
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if m := falcoListFieldRegex.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				probe.Fields = append(probe.Fields, strings.TrimSuffix(m[1], "."))
+			}
+		}
+
+		out, err = runFalcoProbe(runner, "--list-events")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", image, err.Error())
+		}
+		for _, m := range falcoListEventRegex.FindAllStringSubmatch(out, -1) {
+			probe.Events = append(probe.Events, m[1])
+		}
+		probes = append(probes, probe)
+	}
+	return mergeEngineProbes(probes, table), nil
+}
+
+// readFalcoVersions reads a file listing one Falco version per line, such as
+// .github/FALCO_VERSIONS.
+func readFalcoVersions(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var res []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if v := strings.TrimSpace(scanner.Text()); len(v) > 0 && !strings.HasPrefix(v, "#") {
+			res = append(res, v)
+		}
+	}
+	return res, scanner.Err()
+}
+
+var engineVersionCmd = &cobra.Command{
+	Use:   "engine-version",
+	Short: "Compute the minimum required_engine_version of rules files from the fields, operators, and event types they use",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		falcoVersionsPath, err := cmd.Flags().GetString("falco-versions")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		table := defaultEngineVersionTable
+		if len(falcoVersionsPath) > 0 {
+			versions, err := readFalcoVersions(falcoVersionsPath)
+			if err != nil {
+				return err
+			}
+			var images []string
+			for _, v := range versions {
+				images = append(images, "falcosecurity/falco:"+v)
+			}
+			table, err = probeEngineVersionTable(images, table)
+			if err != nil {
+				return err
+			}
+		}
+
+		var files []*rulesFile
+		for _, p := range rulesFilesPaths {
+			f, err := loadRulesFile(p)
+			if err != nil {
+				return err
+			}
+			files = append(files, f)
+		}
+
+		reports, err := analyzeEngineVersions(files, table)
+		if err != nil {
+			return err
+		}
+		writeEngineVersionReports(cmd.OutOrStdout(), reports)
+
+		for _, rep := range reports {
+			if !rep.Satisfied() {
+				err = errAppend(err, fmt.Errorf("%s requires engine version %s or above", rep.Path, rep.Required.String()))
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	engineVersionCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	engineVersionCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	engineVersionCmd.Flags().String("falco-versions", "", "File listing the Falco versions whose Docker images are probed for the supported fields and event types, such as .github/FALCO_VERSIONS (uses the bundled table if empty)")
+	rootCmd.AddCommand(engineVersionCmd)
+}
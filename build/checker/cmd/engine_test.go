@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEngineVersion(t *testing.T) {
+	t.Parallel()
+	v, err := parseEngineVersion("0.57.0")
+	require.NoError(t, err)
+	assert.Equal(t, "0.57.0", v.String())
+
+	v, err = parseEngineVersion("15")
+	require.NoError(t, err)
+	assert.Equal(t, "0.15.0", v.String())
+
+	_, err = parseEngineVersion("test")
+	assert.Error(t, err)
+}
+
+func TestItemEngineFeatures(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- list: open_events
+  items: [open, openat]
+
+- rule: test_rule
+  desc: test rule
+  condition: evt.type in (open_events) and basename(fd.name) = val(proc.exe) and proc.cmdline regex "a.*"
+  output: user=%user.name exe=%toupper(proc.exe) parent=%proc.aname[2].
+  priority: INFO
+  exceptions:
+    - name: files
+      fields: [fd.name, proc.name]
+      comps: [startswith, =]
+    - name: single
+      fields: fs.path.name
+`)
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+
+	uses, err := itemEngineFeatures(files[0].Items[1], rs.ListLookup())
+	require.NoError(t, err)
+	features := make(map[string]bool)
+	for _, u := range uses {
+		assert.Equal(t, files[0].Items[1], u.Item)
+		features[u.Kind+":"+u.Name] = true
+	}
+	for _, f := range []string{
+		"event:open",
+		"event:openat",
+		"field:evt.type",
+		"field:fd.name",
+		"field:proc.exe",
+		"field:proc.cmdline",
+		"field:user.name",
+		"field:proc.aname",
+		"field:proc.name",
+		"field:fs.path.name",
+		"operator:in",
+		"operator:regex",
+		"operator:startswith",
+		"transformer:basename",
+		"transformer:val",
+		"transformer:toupper",
+	} {
+		assert.True(t, features[f], f)
+	}
+	assert.False(t, features["event:open_events"])
+}
+
+func TestAnalyzeEngineVersions(t *testing.T) {
+	t.Parallel()
+	table := engineVersionTable{
+		engineFeatureField:    {"new.field": "0.45.0"},
+		engineFeatureOperator: {"regex": "0.40.0"},
+		engineFeatureEvent:    {"newevent": "0.50.0"},
+	}
+	files := testParseRulesFiles(t, `
+- required_engine_version: 0.45.0
+
+- list: events
+  items: [execve, newevent]
+
+- macro: uses_regex
+  condition: proc.name regex "x.*"
+`, `
+- required_engine_version: 0.45.0
+
+- rule: uses_list
+  desc: test
+  condition: evt.type in (events) and uses_regex
+  output: "%new.field"
+  priority: INFO
+`, `
+- rule: legacy
+  desc: test
+  condition: evt.type = execve
+  output: "%proc.name"
+  priority: INFO
+`)
+	reports, err := analyzeEngineVersions(files, table)
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+
+	assert.Equal(t, "0.45.0", reports[0].Declared)
+	assert.Equal(t, "0.40.0", reports[0].Required.String())
+	assert.True(t, reports[0].Satisfied())
+
+	// events of lists defined in other files are accounted for
+	assert.Equal(t, "0.50.0", reports[1].Required.String())
+	assert.False(t, reports[1].Satisfied())
+	require.Len(t, reports[1].Requirements, 2)
+	assert.Equal(t, "event", reports[1].Requirements[0].Kind)
+	assert.Equal(t, "newevent", reports[1].Requirements[0].Name)
+	assert.Equal(t, "field", reports[1].Requirements[1].Kind)
+
+	assert.Empty(t, reports[2].Declared)
+	assert.Empty(t, reports[2].Requirements)
+	assert.True(t, reports[2].Satisfied())
+
+	var buf bytes.Buffer
+	writeEngineVersionReports(&buf, reports)
+	assert.Contains(t, buf.String(), "Minimum required_engine_version: 0.50.0")
+	assert.Contains(t, buf.String(), "* Event `newevent` requires 0.50.0, used by rule `uses_list` (b.yaml:4)")
+	assert.Contains(t, buf.String(), "Declared required_engine_version: (none)")
+}
+
+func TestMergeEngineProbes(t *testing.T) {
+	t.Parallel()
+	table := engineVersionTable{
+		engineFeatureField:       {"fs.path.name": "0.40.0"},
+		engineFeatureOperator:    {"regex": "0.40.0"},
+		engineFeatureTransformer: {"len": "0.47.0"},
+		engineFeatureEvent:       {},
+	}
+	res := mergeEngineProbes([]*engineProbe{
+		{
+			Version: semver.MustParse("0.52.0"),
+			Fields:  []string{"proc.name", "fs.path.name", "new.field", "newer.field"},
+			Events:  []string{"execve", "newevent"},
+		},
+		{
+			Version: semver.MustParse("0.50.0"),
+			Fields:  []string{"proc.name", "fs.path.name", "new.field"},
+			Events:  []string{"execve"},
+		},
+	}, table)
+
+	// features of the oldest probed version keep their known version
+	assert.Equal(t, map[string]string{
+		"fs.path.name": "0.40.0",
+		"newer.field":  "0.52.0",
+	}, res[engineFeatureField])
+	assert.Equal(t, map[string]string{"newevent": "0.52.0"}, res[engineFeatureEvent])
+	assert.Equal(t, table[engineFeatureOperator], res[engineFeatureOperator])
+	assert.Equal(t, table[engineFeatureTransformer], res[engineFeatureTransformer])
+}
@@ -0,0 +1,472 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/falcosecurity/testing/pkg/falco"
+)
+
+// condEvent is an event fixture mapping field names, including their
+// argument if any (such as `proc.aname[2]`), to their values. Fields with
+// more than one value, such as `proc.aname` or `fd.types`, are lists.
+type condEvent map[string]interface{}
+
+// Lookup returns the values of a field in an event, and false if the event
+// has no value for the field.
+func (e condEvent) Lookup(f condField) ([]string, bool) {
+	v, ok := e[f.FullName()]
+	if !ok || v == nil {
+		return nil, false
+	}
+	var res []string
+	if l, isList := v.([]interface{}); isList {
+		for _, i := range l {
+			res = append(res, condEventValueString(i))
+		}
+	} else {
+		res = []string{condEventValueString(v)}
+	}
+
+	switch f.Transformer {
+	case "tolower":
+		for i := range res {
+			res[i] = strings.ToLower(res[i])
+		}
+	case "toupper":
+		for i := range res {
+			res[i] = strings.ToUpper(res[i])
+		}
+	case "b64":
+		for i := range res {
+			b, err := base64.StdEncoding.DecodeString(res[i])
+			if err != nil {
+				return nil, false
+			}
+			res[i] = string(b)
+		}
+	case "basename":
+		for i := range res {
+			res[i] = path.Base(res[i])
+			if strings.HasSuffix(res[i], "/") || res[i] == "." {
+				res[i] = ""
+			}
+		}
+	case "len":
+		n := len(res)
+		if _, isList := v.([]interface{}); !isList {
+			n = len(res[0])
+		}
+		res = []string{strconv.Itoa(n)}
+	}
+	return res, true
+}
+
+func condEventValueString(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// condEvaluator evaluates conditions with all their macros and lists inlined
+// against event fixtures, approximating the semantics of the Falco engine.
+type condEvaluator struct {
+	regexps map[string]*regexp.Regexp
+}
+
+func newCondEvaluator() *condEvaluator {
+	return &condEvaluator{regexps: make(map[string]*regexp.Regexp)}
+}
+
+// Eval returns true if the condition matches the event.
+func (x *condEvaluator) Eval(e condExpr, evt condEvent) (bool, error) {
+	switch v := e.(type) {
+	case *condAndExpr:
+		for _, c := range v.Exprs {
+			ok, err := x.Eval(c, evt)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case *condOrExpr:
+		for _, c := range v.Exprs {
+			ok, err := x.Eval(c, evt)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case *condNotExpr:
+		ok, err := x.Eval(v.Expr, evt)
+		return !ok, err
+	case *condCheckExpr:
+		return x.evalCheck(v, evt)
+	}
+	return false, fmt.Errorf("can't evaluate `%s`, macros must be expanded", e.String())
+}
+
+func (x *condEvaluator) evalCheck(c *condCheckExpr, evt condEvent) (bool, error) {
+	values, ok := evt.Lookup(c.Field)
+	if c.Op == "exists" {
+		return ok, nil
+	}
+	if !ok {
+		return false, nil
+	}
+
+	var operands []string
+	for _, v := range c.Values {
+		if v.Field == nil {
+			operands = append(operands, v.Text)
+			continue
+		}
+		other, ok := evt.Lookup(*v.Field)
+		if !ok {
+			return false, nil
+		}
+		operands = append(operands, other...)
+	}
+
+	// ancestry fields without an index match if any of the ancestors does
+	anyAncestor := condAncestryFields[c.Field.Name] && len(c.Field.Arg) == 0
+	switch c.Op {
+	case "in":
+		if anyAncestor {
+			for _, v := range values {
+				if strSliceContains(operands, v) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		// all the values of multi-value fields must be in the set
+		for _, v := range values {
+			if !strSliceContains(operands, v) {
+				return false, nil
+			}
+		}
+		return len(values) > 0, nil
+	case "intersects":
+		for _, v := range values {
+			if strSliceContains(operands, v) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case "pmatch":
+		for _, v := range values {
+			for _, o := range operands {
+				o = strings.TrimSuffix(o, "/")
+				if v == o || strings.HasPrefix(v, o+"/") {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+
+	if len(operands) != 1 {
+		return false, fmt.Errorf("operator `%s` requires a single value in `%s`", c.Op, c.String())
+	}
+	operand := operands[0]
+	// multi-value fields match if any of their values does
+	for _, v := range values {
+		ok, err := x.compare(c.Op, v, operand)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+func (x *condEvaluator) compare(op, value, operand string) (bool, error) {
+	switch op {
+	case "=", "==", "!=":
+		eq := value == operand
+		if l, err := strconv.ParseFloat(value, 64); err == nil {
+			if r, err := strconv.ParseFloat(operand, 64); err == nil {
+				eq = l == r
+			}
+		}
+		return eq == (op != "!="), nil
+	case "<", "<=", ">", ">=":
+		l, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false, nil
+		}
+		r, err := strconv.ParseFloat(operand, 64)
+		if err != nil {
+			return false, fmt.Errorf("operator `%s` requires a numeric value, got '%s'", op, operand)
+		}
+		switch op {
+		case "<":
+			return l < r, nil
+		case "<=":
+			return l <= r, nil
+		case ">":
+			return l > r, nil
+		}
+		return l >= r, nil
+	case "contains":
+		return strings.Contains(value, operand), nil
+	case "icontains":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(operand)), nil
+	case "startswith":
+		return strings.HasPrefix(value, operand), nil
+	case "endswith":
+		return strings.HasSuffix(value, operand), nil
+	case "bcontains", "bstartswith":
+		b, err := hex.DecodeString(operand)
+		if err != nil {
+			return false, fmt.Errorf("operator `%s` requires an hex string, got '%s'", op, operand)
+		}
+		if op == "bcontains" {
+			return strings.Contains(value, string(b)), nil
+		}
+		return strings.HasPrefix(value, string(b)), nil
+	case "glob", "iglob", "regex":
+		re, err := x.regexp(op, operand)
+		if err != nil {
+			return false, err
+		}
+		return re.MatchString(value), nil
+	}
+	return false, fmt.Errorf("unsupported operator `%s`", op)
+}
+
+// regexp returns the compiled regular expression matching the whole value
+// for a glob, iglob or regex operand.
+func (x *condEvaluator) regexp(op, operand string) (*regexp.Regexp, error) {
+	key := op + ":" + operand
+	if re, ok := x.regexps[key]; ok {
+		return re, nil
+	}
+	expr := operand
+	if op != "regex" {
+		var sb strings.Builder
+		for _, r := range operand {
+			switch r {
+			case '*':
+				sb.WriteString(".*")
+			case '?':
+				sb.WriteString(".")
+			case '[', ']':
+				sb.WriteRune(r)
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		expr = sb.String()
+		if op == "iglob" {
+			expr = "(?i)" + expr
+		}
+	}
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value '%s': %s", op, operand, err.Error())
+	}
+	x.regexps[key] = re
+	return re, nil
+}
+
+// outputFieldRefRegex matches the fields referenced by a rule output, with
+// their optional transformer and argument.
+var outputFieldRefRegex = regexp.MustCompile(`%(?:([a-z0-9]+)\()?([a-zA-Z][a-zA-Z0-9_]*(?:\.[a-zA-Z0-9_]+)+)(\[[^\]]*\])?`)
+
+// FormatOutput resolves the fields of a rule output with the values of an
+// event, using `<NA>` for the fields the event has no value for.
+func (x *condEvaluator) FormatOutput(output string, evt condEvent) string {
+	var sb strings.Builder
+	last := 0
+	for _, m := range outputFieldRefRegex.FindAllStringSubmatchIndex(output, -1) {
+		end := m[1]
+		f := condField{Name: output[m[4]:m[5]]}
+		if m[6] >= 0 {
+			f.Arg = output[m[6]+1 : m[7]-1]
+		}
+		if m[2] >= 0 {
+			// transformers are applied only if the parenthesis is closed
+			if end >= len(output) || output[end] != ')' {
+				continue
+			}
+			f.Transformer = output[m[2]:m[3]]
+			end++
+		}
+		sb.WriteString(output[last:m[0]])
+		if values, ok := evt.Lookup(f); ok {
+			if len(values) == 1 {
+				sb.WriteString(values[0])
+			} else {
+				sb.WriteString("(" + strings.Join(values, ",") + ")")
+			}
+		} else {
+			sb.WriteString("<NA>")
+		}
+		last = end
+	}
+	sb.WriteString(output[last:])
+	return sb.String()
+}
+
+// compiledRule is a rule with its condition expanded, ready to be evaluated.
+type compiledRule struct {
+	*rulesetEntry
+	Condition condExpr
+}
+
+// exceptionValue converts a value of a rule exception to a condition value.
+// Values compared with list operators are list items, or list names if
+// not within a list, whereas the other values are literals.
+func exceptionValue(v interface{}, isList bool) condValue {
+	s := fmt.Sprint(v)
+	if isList {
+		return condValue{Text: s}
+	}
+	return condValue{Text: s, Quote: '"'}
+}
+
+// exceptionCondition returns the condition matching the events suppressed
+// by a rule exception, which is the disjunction of the field comparisons of
+// each of its values, or nil if it has no values.
+func exceptionCondition(e ruleException) (condExpr, error) {
+	shape, issues := exceptionShapeOf(e)
+	if len(issues) > 0 {
+		return nil, fmt.Errorf("exception `%s` %s", e.Name, strings.Join(issues, ", "))
+	}
+	var fields []condField
+	for _, f := range shape.Fields {
+		c, err := parseConditionFragment(f + " exists")
+		if err != nil {
+			return nil, fmt.Errorf("exception `%s` has an invalid field `%s`", e.Name, f)
+		}
+		check, ok := c.(*condCheckExpr)
+		if !ok {
+			return nil, fmt.Errorf("exception `%s` has an invalid field `%s`", e.Name, f)
+		}
+		fields = append(fields, check.Field)
+	}
+	values, _ := e.Values.([]interface{})
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	res := &condOrExpr{}
+	if shape.Single {
+		check := &condCheckExpr{Field: fields[0], Op: shape.Comps[0]}
+		for _, v := range values {
+			check.Values = append(check.Values, condListItemValue(0, fmt.Sprint(v)))
+		}
+		return check, nil
+	}
+	for i, v := range values {
+		tuple, ok := v.([]interface{})
+		if !ok || len(tuple) != len(fields) {
+			return nil, fmt.Errorf("exception `%s` has an invalid value #%d", e.Name, i+1)
+		}
+		and := &condAndExpr{}
+		for j, elem := range tuple {
+			check := &condCheckExpr{Field: fields[j], Op: shape.Comps[j]}
+			isList := condListOperators[shape.Comps[j]]
+			if items, ok := elem.([]interface{}); ok && isList {
+				for _, it := range items {
+					check.Values = append(check.Values, condListItemValue(0, fmt.Sprint(it)))
+				}
+			} else {
+				check.Values = append(check.Values, exceptionValue(elem, isList))
+			}
+			and.Exprs = append(and.Exprs, check)
+		}
+		res.Exprs = append(res.Exprs, and)
+	}
+	return res, nil
+}
+
+// compileRules expands the conditions of the rules of a ruleset, and
+// appends to them the negation of their exceptions, similarly to what the
+// Falco engine does.
+func compileRules(rs *ruleset) ([]*compiledRule, error) {
+	var res []*compiledRule
+	for _, r := range rs.Rules {
+		c, err := rs.ExpandCondition(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range r.Exceptions {
+			exc, err := exceptionCondition(e)
+			if err != nil {
+				return nil, fmt.Errorf("rule `%s`: %s", r.Name(), err.Error())
+			}
+			if exc == nil {
+				continue
+			}
+			exc, err = expandCondition(exc, rs.MacroLookup(), rs.ListLookup())
+			if err != nil {
+				return nil, fmt.Errorf("rule `%s`: %s", r.Name(), err.Error())
+			}
+			c = &condAndExpr{Exprs: []condExpr{c, &condNotExpr{Expr: exc}}}
+		}
+		res = append(res, &compiledRule{rulesetEntry: r, Condition: c})
+	}
+	return res, nil
+}
+
+// EvalRules evaluates the given rules against a sequence of events in order,
+// and returns the alerts they produce. Each event is matched by all the
+// matching rules of its source, which is defined by the `evt.source` field,
+// defaulting to syscall. Rules disabled at default are evaluated only if
+// explicitly enabled.
+func (x *condEvaluator) EvalRules(rules []*compiledRule, events []condEvent, enabled ...string) (falco.Detections, error) {
+	var res falco.Detections
+	for i, evt := range events {
+		source := defaultRuleSource
+		if s, ok := evt["evt.source"]; ok {
+			source = condEventValueString(s)
+		}
+		for _, r := range rules {
+			if r.RuleSource() != source || (!r.IsEnabled() && !strSliceContains(enabled, r.Name())) {
+				continue
+			}
+			ok, err := x.Eval(r.Condition, evt)
+			if err != nil {
+				return nil, fmt.Errorf("event #%d: rule `%s`: %s", i+1, r.Name(), err.Error())
+			}
+			if ok {
+				res = append(res, &falco.Alert{
+					Rule:     r.Name(),
+					Output:   x.FormatOutput(r.Output, evt),
+					Priority: r.Priority,
+					Source:   source,
+					Tags:     r.Tags,
+				})
+			}
+		}
+	}
+	return res, nil
+}
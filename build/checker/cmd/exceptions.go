@@ -0,0 +1,351 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// syscallFieldClasses are the prefixes of the fields that the syscall event
+// source supports without plugins.
+var syscallFieldClasses = []string{
+	"evt.",
+	"evtin.",
+	"fd.",
+	"fdlist.",
+	"fs.",
+	"group.",
+	"proc.",
+	"span.",
+	"syscall.",
+	"thread.",
+	"user.",
+}
+
+// fieldValidForSource returns true if a field can be used by the rules of
+// an event source. Fields of sources that no known plugin provides are
+// always considered valid.
+func fieldValidForSource(plugins []pluginInfo, source, field string) bool {
+	if strings.HasPrefix(field, "evt.") {
+		return true
+	}
+	if p := fieldPlugin(plugins, field); len(p) > 0 {
+		for _, info := range plugins {
+			if info.Name == p && (strSliceContains(info.Sources, source) || strSliceContains(info.ExtractSources, source)) {
+				return true
+			}
+		}
+		return false
+	}
+	if source == defaultRuleSource {
+		for _, c := range syscallFieldClasses {
+			if strings.HasPrefix(field, c) {
+				return true
+			}
+		}
+		return false
+	}
+	return len(sourcePlugin(plugins, source)) == 0
+}
+
+// exceptionShape is the normalized definition of a rule exception.
+type exceptionShape struct {
+	Fields []string
+	Comps  []string
+	// Single is true if the exception has a single field not within a list,
+	// in which case its values are compared with a list operator
+	Single bool
+}
+
+// exceptionShapeOf returns the normalized definition of a rule exception,
+// applying the default comparison operators, and the issues found in its
+// fields and comps definitions.
+func exceptionShapeOf(e ruleException) (*exceptionShape, []string) {
+	var issues []string
+	res := &exceptionShape{}
+	switch f := e.Fields.(type) {
+	case string:
+		res.Single = true
+		res.Fields = []string{f}
+	case []interface{}:
+		res.Fields = exceptionStrings(f)
+		if len(res.Fields) != len(f) {
+			issues = append(issues, "fields must be strings")
+		}
+	case nil:
+		return nil, []string{"has no fields"}
+	default:
+		return nil, []string{"fields must be a field name or a list of field names"}
+	}
+
+	switch c := e.Comps.(type) {
+	case nil:
+		def := "="
+		if res.Single {
+			def = "in"
+		}
+		for range res.Fields {
+			res.Comps = append(res.Comps, def)
+		}
+	case string:
+		if !res.Single {
+			issues = append(issues, "comps must be a list when fields is a list")
+		}
+		res.Comps = []string{c}
+	case []interface{}:
+		if res.Single {
+			issues = append(issues, "comps must be a single operator when fields is a single field")
+		}
+		res.Comps = exceptionStrings(c)
+		if len(res.Comps) != len(c) {
+			issues = append(issues, "comps must be strings")
+		}
+	default:
+		issues = append(issues, "comps must be an operator or a list of operators")
+	}
+
+	if len(res.Comps) != len(res.Fields) {
+		issues = append(issues, fmt.Sprintf("has %d comps for %d fields", len(res.Comps), len(res.Fields)))
+	}
+	for _, c := range res.Comps {
+		if !condBinaryOperators[c] && !condListOperators[c] {
+			issues = append(issues, fmt.Sprintf("comp `%s` is not a valid operator", c))
+		} else if res.Single && !condListOperators[c] {
+			issues = append(issues, fmt.Sprintf("comp `%s` of a single field must be a list operator such as `in`", c))
+		}
+	}
+	return res, issues
+}
+
+func isExceptionScalar(v interface{}) bool {
+	switch v.(type) {
+	case []interface{}, map[string]interface{}, nil:
+		return false
+	}
+	return true
+}
+
+// exceptionValuesIssues returns the issues found in the values of an
+// exception, checking that they match the shape of the exception.
+func exceptionValuesIssues(shape *exceptionShape, values interface{}) []string {
+	if values == nil {
+		return nil
+	}
+	list, ok := values.([]interface{})
+	if !ok {
+		return []string{"values must be a list"}
+	}
+	var res []string
+	for i, v := range list {
+		if shape.Single {
+			if !isExceptionScalar(v) {
+				res = append(res, fmt.Sprintf("value #%d must be a single value", i+1))
+			}
+			continue
+		}
+		tuple, ok := v.([]interface{})
+		if !ok {
+			res = append(res, fmt.Sprintf("value #%d must be a list of %d elements, one for each field", i+1, len(shape.Fields)))
+			continue
+		}
+		if len(tuple) != len(shape.Fields) {
+			res = append(res, fmt.Sprintf("value #%d has %d elements for %d fields", i+1, len(tuple), len(shape.Fields)))
+			continue
+		}
+		for j, elem := range tuple {
+			if j >= len(shape.Comps) {
+				break
+			}
+			_, isList := elem.([]interface{})
+			if condListOperators[shape.Comps[j]] {
+				// list operators accept both a list of values and a list name
+				if !isList && !isExceptionScalar(elem) {
+					res = append(res, fmt.Sprintf("value #%d has element #%d that must be a list for comp `%s`", i+1, j+1, shape.Comps[j]))
+				}
+			} else if !isExceptionScalar(elem) {
+				res = append(res, fmt.Sprintf("value #%d has element #%d that must be a single value for comp `%s`", i+1, j+1, shape.Comps[j]))
+			}
+		}
+	}
+	return res
+}
+
+// exceptionsReport reports the issues of the rule exceptions of a rules file.
+type exceptionsReport struct {
+	Path   string
+	Issues []string
+}
+
+// ruleExceptionsIssues validates the exceptions of a rule item. The base
+// entry is the rule the item appends to or overrides, or nil for items
+// defining a new rule.
+func ruleExceptionsIssues(item *rulesFileItem, base *rulesetEntry, plugins []pluginInfo) []string {
+	var res []string
+	source := item.RuleSource()
+	appending := base != nil && (item.Append || item.Override["exceptions"] == "append")
+	if base != nil && !item.Keys["source"] {
+		source = base.RuleSource()
+	}
+	for _, e := range item.Exceptions {
+		prefix := fmt.Sprintf("%s: rule `%s` exception `%s`", item.Loc.String(), item.Name(), e.Name)
+		if len(e.Name) == 0 {
+			res = append(res, fmt.Sprintf("%s: rule `%s` has an exception without name", item.Loc.String(), item.Name()))
+			continue
+		}
+
+		var existing *ruleException
+		if appending {
+			for i := range base.Exceptions {
+				if base.Exceptions[i].Name == e.Name {
+					existing = &base.Exceptions[i]
+				}
+			}
+		}
+		def := e
+		if existing != nil && e.Fields == nil {
+			def = *existing
+		} else if appending && e.Fields == nil {
+			res = append(res, fmt.Sprintf("%s: appended values don't match any exception of the rule", prefix))
+			continue
+		}
+
+		shape, issues := exceptionShapeOf(def)
+		if shape != nil {
+			for _, f := range shape.Fields {
+				if !fieldValidForSource(plugins, source, f) {
+					issues = append(issues, fmt.Sprintf("field `%s` is not valid for source `%s`", f, source))
+				}
+			}
+			if len(issues) == 0 {
+				issues = append(issues, exceptionValuesIssues(shape, e.Values)...)
+			}
+		}
+		for _, i := range issues {
+			res = append(res, fmt.Sprintf("%s: %s", prefix, i))
+		}
+	}
+	return res
+}
+
+// analyzeExceptions loads the given rules files in order, and validates the
+// exceptions of their rules, including the ones appended by overrides.
+func analyzeExceptions(files []*rulesFile, plugins []pluginInfo) ([]*exceptionsReport, error) {
+	var res []*exceptionsReport
+	rs := newRuleset()
+	for _, f := range files {
+		rep := &exceptionsReport{Path: f.Path}
+		for _, item := range f.Items {
+			if item.Kind == itemKindRule && item.Keys["exceptions"] {
+				var base *rulesetEntry
+				if item.IsOverride() {
+					base = rs.Rule(item.Name())
+				}
+				rep.Issues = append(rep.Issues, ruleExceptionsIssues(item, base, plugins)...)
+			}
+			if item.Kind == itemKindEngine || item.Kind == itemKindPlugin {
+				continue
+			}
+			if err := rs.addItem(item); err != nil {
+				return nil, fmt.Errorf("%s: %s", item.Loc.String(), err.Error())
+			}
+		}
+		res = append(res, rep)
+	}
+	return res, nil
+}
+
+func writeExceptionsReports(w io.Writer, reports []*exceptionsReport) {
+	for _, rep := range reports {
+		fmt.Fprintln(w, "## "+rep.Path)
+		fmt.Fprintln(w)
+		if len(rep.Issues) == 0 {
+			fmt.Fprintln(w, "No issues detected")
+		}
+		for _, i := range rep.Issues {
+			fmt.Fprintln(w, "* "+i)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+var exceptionsCmd = &cobra.Command{
+	Use:   "exceptions",
+	Short: "Validate the fields, comps and values of the rule exceptions, including the ones appended by overrides",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		tablePath, err := cmd.Flags().GetString("plugins-table")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		plugins := defaultPluginsTable
+		if len(tablePath) > 0 {
+			plugins, err = loadPluginsTable(tablePath)
+			if err != nil {
+				return err
+			}
+		}
+
+		var files []*rulesFile
+		for _, p := range rulesFilesPaths {
+			f, err := loadRulesFile(p)
+			if err != nil {
+				return err
+			}
+			files = append(files, f)
+		}
+
+		reports, err := analyzeExceptions(files, plugins)
+		if err != nil {
+			return err
+		}
+		writeExceptionsReports(cmd.OutOrStdout(), reports)
+
+		for _, rep := range reports {
+			if len(rep.Issues) > 0 {
+				err = errAppend(err, fmt.Errorf("%s has invalid exceptions", rep.Path))
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	exceptionsCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order, including the ones containing overrides (defaults to the rules files of the registry)")
+	exceptionsCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	exceptionsCmd.Flags().String("plugins-table", "", "YAML file listing the name, fields, and event sources of each plugin (uses the bundled table if empty)")
+	rootCmd.AddCommand(exceptionsCmd)
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldValidForSource(t *testing.T) {
+	t.Parallel()
+	assert.True(t, fieldValidForSource(defaultPluginsTable, "syscall", "proc.name"))
+	assert.True(t, fieldValidForSource(defaultPluginsTable, "syscall", "container.id"))
+	assert.False(t, fieldValidForSource(defaultPluginsTable, "syscall", "ka.verb"))
+	assert.False(t, fieldValidForSource(defaultPluginsTable, "syscall", "unknown.field"))
+	assert.True(t, fieldValidForSource(defaultPluginsTable, "k8s_audit", "ka.verb"))
+	assert.True(t, fieldValidForSource(defaultPluginsTable, "k8s_audit", "jevt.value"))
+	assert.True(t, fieldValidForSource(defaultPluginsTable, "k8s_audit", "evt.time"))
+	assert.False(t, fieldValidForSource(defaultPluginsTable, "k8s_audit", "proc.name"))
+	assert.False(t, fieldValidForSource(defaultPluginsTable, "k8s_audit", "container.id"))
+	assert.True(t, fieldValidForSource(defaultPluginsTable, "unknown_source", "any.field"))
+}
+
+func TestAnalyzeExceptions(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- rule: test_rule
+  desc: test
+  condition: evt.type = execve
+  output: test
+  priority: INFO
+  exceptions:
+    - name: valid
+      fields: [proc.name, fd.name, proc.pname]
+      comps: [=, startswith, in]
+      values:
+        - [bash, /tmp, [sshd, sudo]]
+        - [sh, /etc, known_parents]
+    - name: single
+      fields: proc.name
+      values: [bash, sh]
+    - name: defaults
+      fields: [proc.name, container.image.repository]
+    - name: wrong_comps
+      fields: [proc.name, fd.name]
+      comps: [=]
+    - name: bad_operator
+      fields: proc.name
+      comps: startswith
+    - name: wrong_field
+      fields: [ka.verb]
+    - name: wrong_values
+      fields: [proc.name, fd.name]
+      comps: [=, in]
+      values:
+        - [bash]
+        - bash
+        - [[bash], [a, b]]
+`, `
+- rule: test_rule
+  exceptions:
+    - name: defaults
+      values:
+        - [bash, alpine]
+        - [bash]
+    - name: missing
+      values:
+        - [bash]
+    - name: new_one
+      fields: [proc.name]
+      values:
+        - [bash]
+  override:
+    exceptions: append
+
+- rule: audit_rule
+  desc: test
+  condition: ka.verb = create
+  output: test
+  priority: INFO
+  source: k8s_audit
+  exceptions:
+    - name: users
+      fields: [ka.user.name, proc.name]
+`)
+	reports, err := analyzeExceptions(files, defaultPluginsTable)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	assert.Equal(t, []string{
+		"a.yaml:2: rule `test_rule` exception `wrong_comps`: has 1 comps for 2 fields",
+		"a.yaml:2: rule `test_rule` exception `bad_operator`: comp `startswith` of a single field must be a list operator such as `in`",
+		"a.yaml:2: rule `test_rule` exception `wrong_field`: field `ka.verb` is not valid for source `syscall`",
+		"a.yaml:2: rule `test_rule` exception `wrong_values`: value #1 has 1 elements for 2 fields",
+		"a.yaml:2: rule `test_rule` exception `wrong_values`: value #2 must be a list of 2 elements, one for each field",
+		"a.yaml:2: rule `test_rule` exception `wrong_values`: value #3 has element #1 that must be a single value for comp `=`",
+	}, reports[0].Issues)
+	assert.Equal(t, []string{
+		"b.yaml:2: rule `test_rule` exception `defaults`: value #2 has 1 elements for 2 fields",
+		"b.yaml:2: rule `test_rule` exception `missing`: appended values don't match any exception of the rule",
+		"b.yaml:18: rule `audit_rule` exception `users`: field `proc.name` is not valid for source `k8s_audit`",
+	}, reports[1].Issues)
+
+	var buf bytes.Buffer
+	writeExceptionsReports(&buf, []*exceptionsReport{{Path: "c.yaml"}})
+	assert.Equal(t, "## c.yaml\n\nNo issues detected\n\n", buf.String())
+}
@@ -0,0 +1,178 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// explainer pretty-prints the boolean tree of a condition, inlining macros
+// and lists and annotating each node with the location it comes from.
+type explainer struct {
+	rs       *ruleset
+	w        io.Writer
+	visiting []string
+}
+
+func (x *explainer) line(prefix, text string, loc srcLoc) {
+	fmt.Fprintf(x.w, "%s%s  [%s]\n", prefix, text, loc.String())
+}
+
+// explainListValues returns the values of a list operator with all the list
+// references inlined, and the description of the lists that got inlined.
+func (x *explainer) explainListValues(c *condCheckExpr) (string, []string) {
+	var inlined []string
+	lookup := func(name string) ([]string, bool) {
+		l := x.rs.List(name)
+		if l == nil {
+			return nil, false
+		}
+		var locs []string
+		for _, o := range l.Origins {
+			locs = append(locs, o.Loc.String())
+		}
+		inlined = append(inlined, fmt.Sprintf("list %s: %s", name, strings.Join(locs, ", ")))
+		return l.Items, true
+	}
+	res := *c
+	res.Values = expandListValues(c.Values, lookup, nil)
+	return res.String(), inlined
+}
+
+func (x *explainer) explain(e condExpr, src *sourceText, prefix, childPrefix string) error {
+	loc := src.LocAt(e.Pos())
+	switch v := e.(type) {
+	case *condAndExpr:
+		x.line(prefix, "and", loc)
+		return x.explainChildren(v.Exprs, src, childPrefix)
+	case *condOrExpr:
+		x.line(prefix, "or", loc)
+		return x.explainChildren(v.Exprs, src, childPrefix)
+	case *condNotExpr:
+		x.line(prefix, "not", loc)
+		return x.explainChildren([]condExpr{v.Expr}, src, childPrefix)
+	case *condIdentExpr:
+		if strSliceContains(x.visiting, v.Name) {
+			return fmt.Errorf("macro `%s` has a circular reference", v.Name)
+		}
+		m := x.rs.Macro(v.Name)
+		if m == nil {
+			return fmt.Errorf("undefined macro `%s`", v.Name)
+		}
+		c, err := m.ParseCondition()
+		if err != nil {
+			return err
+		}
+		x.line(prefix, "macro "+v.Name, loc)
+		x.visiting = append(x.visiting, v.Name)
+		defer func() { x.visiting = x.visiting[:len(x.visiting)-1] }()
+		return x.explainChildren([]condExpr{c}, &m.Cond, childPrefix)
+	case *condCheckExpr:
+		if !condListOperators[v.Op] {
+			x.line(prefix, v.String(), loc)
+			return nil
+		}
+		s, inlined := x.explainListValues(v)
+		x.line(prefix, s, loc)
+		for _, l := range inlined {
+			fmt.Fprintf(x.w, "%s  (%s)\n", childPrefix, l)
+		}
+		return nil
+	}
+	return fmt.Errorf("unexpected condition node: %s", e.String())
+}
+
+func (x *explainer) explainChildren(exprs []condExpr, src *sourceText, prefix string) error {
+	for i, c := range exprs {
+		p, cp := prefix+"├── ", prefix+"│   "
+		if i == len(exprs)-1 {
+			p, cp = prefix+"└── ", prefix+"    "
+		}
+		if err := x.explain(c, src, p, cp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// explainEntry writes the explanation of a rule or macro.
+func explainEntry(w io.Writer, rs *ruleset, e *rulesetEntry) error {
+	c, err := e.ParseCondition()
+	if err != nil {
+		return err
+	}
+	expanded, err := rs.ExpandCondition(e)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "%s: %s\n", capitalize(e.Kind), e.Name())
+	for i, o := range e.Origins {
+		if i == 0 {
+			fmt.Fprintf(w, "Defined at: %s\n", o.Loc.String())
+		} else {
+			fmt.Fprintf(w, "Overridden at: %s\n", o.Loc.String())
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Condition (macros and lists inlined):")
+	fmt.Fprintln(w, "  "+expanded.String())
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Condition tree:")
+	x := &explainer{rs: rs, w: w}
+	return x.explain(c, &e.Cond, "", "")
+}
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <rule>",
+	Short: "Explain the condition of a rule or macro by inlining all its macros and lists",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		if len(rulesFilesPaths) == 0 {
+			return fmt.Errorf("you must specify at least one rules file")
+		}
+
+		rs, err := loadRuleset(rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+
+		e := rs.Rule(args[0])
+		if e == nil {
+			e = rs.Macro(args[0])
+		}
+		if e == nil {
+			return fmt.Errorf("no rule or macro found with name `%s`", args[0])
+		}
+		return explainEntry(cmd.OutOrStdout(), rs, e)
+	},
+}
+
+func init() {
+	explainCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order, including the ones containing overrides")
+	rootCmd.AddCommand(explainCmd)
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainEntry(t *testing.T) {
+	t.Parallel()
+	rs := testLoadSampleRuleset(t, true)
+	var buf bytes.Buffer
+	require.NoError(t, explainEntry(&buf, rs, rs.Rule("Run shell")))
+	assert.Equal(t, `Rule: Run shell
+Defined at: rules.yaml:17
+Overridden at: overrides.yaml:12
+Overridden at: overrides.yaml:15
+
+Condition (macros and lists inlined):
+  evt.type in (execve, execveat) and (proc.name in (bash, sh, zsh) or proc.name=ash)
+
+Condition tree:
+and  [rules.yaml:20]
+├── macro spawned_process  [rules.yaml:20]
+│   └── evt.type in (execve, execveat)  [rules.yaml:12]
+└── macro shell_procs  [rules.yaml:21]
+    └── or  [rules.yaml:15]
+        ├── proc.name in (bash, sh, zsh)  [rules.yaml:15]
+        │     (list shell_binaries: rules.yaml:8, overrides.yaml:2)
+        └── proc.name=ash  [overrides.yaml:8]
+`, buf.String())
+}
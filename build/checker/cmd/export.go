@@ -0,0 +1,717 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// sigmaField is the Sigma field a Falco field is converted to.
+type sigmaField struct {
+	Name string
+	// Basename is true if the Falco field is the file name of the path held
+	// by the Sigma field, such as `proc.name` for `Image`
+	Basename bool
+	// Partial is true if the Falco field only holds a part of the Sigma
+	// field, such as `proc.cmdline` not including the executable path of
+	// `CommandLine`, so that it can only be matched by substring or suffix
+	Partial bool
+}
+
+// sigmaCategory is a Sigma log source category of the linux product, the
+// Falco event types it covers, and how the fields of the rules of these
+// event types are converted.
+type sigmaCategory struct {
+	Name       string
+	EventTypes []string
+	Fields     map[string]sigmaField
+	// Implied are the field comparisons that are true for all the events of
+	// the category, in the format of condCheckExpr.String() without quotes
+	Implied []string
+}
+
+var sigmaProcessFields = map[string]sigmaField{
+	"proc.name":     {Name: "Image", Basename: true},
+	"proc.exepath":  {Name: "Image"},
+	"proc.cmdline":  {Name: "CommandLine", Partial: true},
+	"proc.pname":    {Name: "ParentImage", Basename: true},
+	"proc.pexepath": {Name: "ParentImage"},
+	"proc.pcmdline": {Name: "ParentCommandLine", Partial: true},
+	"proc.cwd":      {Name: "CurrentDirectory"},
+	"proc.pid":      {Name: "ProcessId"},
+	"proc.ppid":     {Name: "ParentProcessId"},
+	"user.name":     {Name: "User"},
+}
+
+// sigmaCategories are the Sigma log source categories that Falco rules can
+// be converted to.
+var sigmaCategories = []*sigmaCategory{
+	{
+		Name:       "process_creation",
+		EventTypes: []string{"execve", "execveat"},
+		Fields:     sigmaProcessFields,
+		Implied:    []string{"evt.dir=<"},
+	},
+	{
+		Name:       "file_event",
+		EventTypes: []string{"open", "openat", "openat2", "creat"},
+		Fields: map[string]sigmaField{
+			"fd.name":      {Name: "TargetFilename"},
+			"fd.filename":  {Name: "TargetFilename", Basename: true},
+			"proc.name":    {Name: "Image", Basename: true},
+			"proc.exepath": {Name: "Image"},
+			"user.name":    {Name: "User"},
+		},
+		Implied: []string{"evt.dir=<", "evt.is_open_write=true", "fd.typechar=f", "fd.num>=0"},
+	},
+	{
+		Name:       "network_connection",
+		EventTypes: []string{"connect", "accept", "accept4"},
+		Fields: map[string]sigmaField{
+			"fd.sip":       {Name: "DestinationIp"},
+			"fd.sip.name":  {Name: "DestinationHostname"},
+			"fd.sport":     {Name: "DestinationPort"},
+			"fd.cip":       {Name: "SourceIp"},
+			"fd.cport":     {Name: "SourcePort"},
+			"fd.l4proto":   {Name: "Protocol"},
+			"proc.name":    {Name: "Image", Basename: true},
+			"proc.exepath": {Name: "Image"},
+			"user.name":    {Name: "User"},
+		},
+		Implied: []string{"evt.dir=<", "fd.typechar=4", "fd.typechar=6"},
+	},
+}
+
+// sigmaOperatorModifiers are the Sigma value modifiers of the Falco
+// operators that have an equivalent one.
+var sigmaOperatorModifiers = map[string]string{
+	"=":          "",
+	"==":         "",
+	"in":         "",
+	"contains":   "contains",
+	"icontains":  "contains",
+	"startswith": "startswith",
+	"endswith":   "endswith",
+	"glob":       "",
+	"regex":      "re",
+	"exists":     "exists",
+	"<":          "lt",
+	"<=":         "lte",
+	">":          "gt",
+	">=":         "gte",
+}
+
+// sigmaLevels maps the Falco priorities to the Sigma levels.
+var sigmaLevels = map[string]string{
+	"emergency":     "critical",
+	"alert":         "critical",
+	"critical":      "critical",
+	"error":         "high",
+	"warning":       "medium",
+	"notice":        "low",
+	"info":          "informational",
+	"informational": "informational",
+	"debug":         "informational",
+}
+
+// sigmaStatuses maps the maturity levels of the rules to the Sigma statuses.
+var sigmaStatuses = map[string]string{
+	"stable":           "stable",
+	"incubating":       "test",
+	"sandbox":          "experimental",
+	maturityDeprecated: "deprecated",
+}
+
+// sigmaNode is a node of the Sigma detection resulting from the conversion
+// of a Falco condition. Nodes are either constants, boolean operators, or
+// field matches.
+type sigmaNode struct {
+	// Op is one of "true", "false", "and", "or", "not", and "match"
+	Op    string
+	Nodes []*sigmaNode
+	// Key is the field name and modifier of a match, such as "Image|endswith"
+	Key    string
+	Values []string
+}
+
+var (
+	sigmaTrue  = &sigmaNode{Op: "true"}
+	sigmaFalse = &sigmaNode{Op: "false"}
+)
+
+// sigmaJoin returns the and or or of some nodes, simplifying the constant
+// ones and merging the nested ones of the same kind.
+func sigmaJoin(op string, nodes []*sigmaNode) *sigmaNode {
+	neutral, absorbing := sigmaTrue, sigmaFalse
+	if op == "or" {
+		neutral, absorbing = sigmaFalse, sigmaTrue
+	}
+	res := &sigmaNode{Op: op}
+	for _, n := range nodes {
+		if n.Op == absorbing.Op {
+			return absorbing
+		}
+		switch n.Op {
+		case neutral.Op:
+		case op:
+			res.Nodes = append(res.Nodes, n.Nodes...)
+		default:
+			res.Nodes = append(res.Nodes, n)
+		}
+	}
+	switch len(res.Nodes) {
+	case 0:
+		return neutral
+	case 1:
+		return res.Nodes[0]
+	}
+	return res
+}
+
+func sigmaNot(n *sigmaNode) *sigmaNode {
+	switch n.Op {
+	case "true":
+		return sigmaFalse
+	case "false":
+		return sigmaTrue
+	case "not":
+		return n.Nodes[0]
+	}
+	return &sigmaNode{Op: "not", Nodes: []*sigmaNode{n}}
+}
+
+// sigmaEscape escapes the characters that Sigma interprets as wildcards
+// in the values of field matches.
+func sigmaEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`).Replace(s)
+}
+
+// sigmaCategoryOf returns the Sigma log source category covering all the
+// event types of a rule.
+func sigmaCategoryOf(eventTypes []string) (*sigmaCategory, error) {
+	var res *sigmaCategory
+	for _, t := range eventTypes {
+		var cat *sigmaCategory
+		for _, c := range sigmaCategories {
+			if strSliceContains(c.EventTypes, t) {
+				cat = c
+			}
+		}
+		if cat == nil {
+			return nil, fmt.Errorf("event type `%s` has no Sigma log source category", t)
+		}
+		if res != nil && res != cat {
+			return nil, fmt.Errorf("event types belong to more than one Sigma log source category (%s, %s)", res.Name, cat.Name)
+		}
+		res = cat
+	}
+	return res, nil
+}
+
+// sigmaCheck converts a Falco field comparison to a Sigma detection node, or
+// returns an error if it has no equivalent in the given category.
+func sigmaCheck(c *condCheckExpr, cat *sigmaCategory) (*sigmaNode, error) {
+	if v, ok := condConstantChecks[c.String()]; ok {
+		if v {
+			return sigmaTrue, nil
+		}
+		return sigmaFalse, nil
+	}
+	unquoted := &condCheckExpr{Field: c.Field, Op: c.Op}
+	for _, v := range c.Values {
+		v.Quote = 0
+		unquoted.Values = append(unquoted.Values, v)
+	}
+	if strSliceContains(cat.Implied, unquoted.String()) {
+		return sigmaTrue, nil
+	}
+	if isEvtTypeCheck(c) {
+		switch c.Op {
+		case "=", "==", "in":
+			matches := 0
+			for _, v := range c.Values {
+				if v.Field == nil && strSliceContains(cat.EventTypes, v.Text) {
+					matches++
+				}
+			}
+			// the event types of the category are not distinguished
+			if matches == len(c.Values) {
+				return sigmaTrue, nil
+			}
+			if matches == 0 {
+				return sigmaFalse, nil
+			}
+		}
+		return nil, fmt.Errorf("event type filter `%s` can't be expressed in the `%s` log source", c.String(), cat.Name)
+	}
+	if len(c.Field.Transformer) > 0 {
+		return nil, fmt.Errorf("transformer `%s` in `%s` has no Sigma equivalent", c.Field.Transformer, c.String())
+	}
+	f, ok := cat.Fields[c.Field.FullName()]
+	if !ok {
+		return nil, fmt.Errorf("field `%s` has no Sigma equivalent in the `%s` log source", c.Field.FullName(), cat.Name)
+	}
+	op := c.Op
+	negated := op == "!="
+	if negated {
+		op = "="
+	}
+	if op == "pmatch" {
+		// a path prefix matches itself and all the paths below it
+		var values []condValue
+		for _, v := range c.Values {
+			values = append(values, v)
+		}
+		exact, err := sigmaCheck(&condCheckExpr{Field: c.Field, Op: "in", Values: values}, cat)
+		if err != nil {
+			return nil, err
+		}
+		for i := range values {
+			values[i].Text = strings.TrimSuffix(values[i].Text, "/") + "/"
+		}
+		below, err := sigmaCheck(&condCheckExpr{Field: c.Field, Op: "startswith", Values: values}, cat)
+		if err != nil {
+			return nil, err
+		}
+		return sigmaJoin("or", []*sigmaNode{exact, below}), nil
+	}
+	modifier, ok := sigmaOperatorModifiers[op]
+	if !ok {
+		return nil, fmt.Errorf("operator `%s` in `%s` has no Sigma equivalent", c.Op, c.String())
+	}
+
+	res := &sigmaNode{Op: "match"}
+	if op == "exists" {
+		res.Values = []string{"true"}
+	}
+	for _, v := range c.Values {
+		if v.Field != nil {
+			return nil, fmt.Errorf("comparison between fields in `%s` has no Sigma equivalent", c.String())
+		}
+		s := v.Text
+		switch op {
+		case "glob":
+			if strings.ContainsAny(s, "[]") {
+				return nil, fmt.Errorf("character classes in `%s` have no Sigma equivalent", c.String())
+			}
+		case "regex":
+		default:
+			s = sigmaEscape(s)
+		}
+		res.Values = append(res.Values, s)
+	}
+
+	inexact := fmt.Errorf("operator `%s` on `%s` has no exact Sigma equivalent", c.Op, c.Field.FullName())
+	switch {
+	case f.Partial:
+		switch op {
+		case "contains", "icontains", "endswith":
+		case "glob":
+			for _, v := range res.Values {
+				if !strings.HasPrefix(v, "*") {
+					return nil, inexact
+				}
+			}
+		default:
+			return nil, inexact
+		}
+	case f.Basename:
+		switch op {
+		case "=", "==", "in", "glob":
+			modifier = "endswith"
+			if op == "glob" {
+				modifier = ""
+			}
+			for i, v := range res.Values {
+				res.Values[i] = "/" + v
+				if op == "glob" {
+					res.Values[i] = "*/" + v
+				}
+			}
+		case "endswith", "exists":
+		default:
+			return nil, inexact
+		}
+	}
+	res.Key = f.Name
+	if len(modifier) > 0 {
+		res.Key += "|" + modifier
+	}
+	if negated {
+		return sigmaNot(res), nil
+	}
+	return res, nil
+}
+
+// sigmaDetection converts a Falco condition to a Sigma detection node.
+func sigmaDetection(e condExpr, cat *sigmaCategory) (*sigmaNode, error) {
+	switch v := e.(type) {
+	case *condAndExpr, *condOrExpr:
+		exprs, isAnd := flattenCondition(v)
+		var nodes []*sigmaNode
+		for _, c := range exprs {
+			n, err := sigmaDetection(c, cat)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		}
+		if isAnd {
+			return sigmaJoin("and", nodes), nil
+		}
+		return sigmaJoin("or", nodes), nil
+	case *condNotExpr:
+		n, err := sigmaDetection(v.Expr, cat)
+		if err != nil {
+			return nil, err
+		}
+		return sigmaNot(n), nil
+	case *condCheckExpr:
+		return sigmaCheck(v, cat)
+	}
+	return nil, fmt.Errorf("`%s` has no Sigma equivalent", e.String())
+}
+
+// sigmaSelections renders a Sigma detection node as a condition, adding
+// the field matches it references to the given selections. The positive
+// field matches of an and are grouped in the same selection, and the ones of
+// an or on the same field are merged in a single match.
+func sigmaSelections(n *sigmaNode, selections *[]*yaml.Node, nested bool) string {
+	newSelection := func() *yaml.Node {
+		sel := &yaml.Node{Kind: yaml.MappingNode}
+		*selections = append(*selections, sel)
+		return sel
+	}
+	addMatch := func(sel *yaml.Node, m *sigmaNode) {
+		value := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, v := range m.Values {
+			value.Content = append(value.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: v})
+		}
+		if len(m.Values) == 1 {
+			value = value.Content[0]
+		}
+		sel.Content = append(sel.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: m.Key}, value)
+	}
+	name := func() string { return fmt.Sprintf("selection_%d", len(*selections)) }
+
+	switch n.Op {
+	case "match":
+		addMatch(newSelection(), n)
+		return name()
+	case "not":
+		return "not " + sigmaSelections(n.Nodes[0], selections, true)
+	}
+
+	var parts []string
+	var sel *yaml.Node
+	keys := make(map[string]bool)
+	for i, c := range n.Nodes {
+		if c.Op != "match" {
+			parts = append(parts, sigmaSelections(c, selections, true))
+			continue
+		}
+		if n.Op == "or" {
+			// the values of a field match are alternatives
+			if keys[c.Key] {
+				continue
+			}
+			merged := &sigmaNode{Op: "match", Key: c.Key}
+			for _, o := range n.Nodes[i:] {
+				if o.Op == "match" && o.Key == c.Key {
+					merged.Values = append(merged.Values, o.Values...)
+				}
+			}
+			keys[c.Key] = true
+			addMatch(newSelection(), merged)
+			parts = append(parts, name())
+			continue
+		}
+		if sel == nil || keys[c.Key] {
+			sel = newSelection()
+			keys = make(map[string]bool)
+			parts = append(parts, name())
+		}
+		keys[c.Key] = true
+		addMatch(sel, c)
+	}
+	res := strings.Join(parts, " "+n.Op+" ")
+	if nested && len(parts) > 1 {
+		res = "(" + res + ")"
+	}
+	return res
+}
+
+// sigmaRuleID returns the identifier of the Sigma rule converted from a
+// Falco rule, derived from the rule name so that it is stable across
+// exports.
+func sigmaRuleID(rule string) string {
+	h := sha1.Sum([]byte("falco-rules/" + rule))
+	h[6] = (h[6] & 0x0f) | 0x50
+	h[8] = (h[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+// sigmaTags converts the MITRE ATT&CK tags of a Falco rule, such as
+// `mitre_execution` and `T1059.004`, to the ATT&CK tags of Sigma.
+func sigmaTags(tags []string) []string {
+	var res []string
+	for _, t := range tags {
+		switch {
+		case strings.HasPrefix(t, "mitre_"):
+			res = append(res, "attack."+strings.ReplaceAll(strings.TrimPrefix(t, "mitre_"), "_", "-"))
+		case len(t) > 1 && t[0] == 'T' && strings.Trim(t[1:], "0123456789.") == "":
+			res = append(res, "attack."+strings.ToLower(t))
+		}
+	}
+	return res
+}
+
+// sigmaRule converts a Falco rule of the syscall source to a Sigma rule, or
+// returns an error explaining why it can't be converted. The field matches
+// of Sigma are case-insensitive, differently from most Falco operators.
+func sigmaRule(rs *ruleset, r *rulesetEntry) (*yaml.Node, error) {
+	if r.RuleSource() != defaultRuleSource {
+		return nil, fmt.Errorf("source `%s` is not supported", r.RuleSource())
+	}
+	c, err := rs.ExpandCondition(r)
+	if err != nil {
+		return nil, err
+	}
+	if !restrictsEventTypes(c, false) {
+		return nil, fmt.Errorf("condition matches all event types")
+	}
+	cat, err := sigmaCategoryOf(conditionEventTypes(c))
+	if err != nil {
+		return nil, err
+	}
+	n, err := sigmaDetection(c, cat)
+	if err != nil {
+		return nil, err
+	}
+	switch n.Op {
+	case "true":
+		return nil, fmt.Errorf("condition has no field comparison that can be expressed in the `%s` log source", cat.Name)
+	case "false":
+		return nil, fmt.Errorf("condition can never match")
+	}
+
+	level, ok := sigmaLevels[strings.ToLower(r.Priority)]
+	if !ok {
+		return nil, fmt.Errorf("priority `%s` has no Sigma level", r.Priority)
+	}
+	status := "experimental"
+	for _, t := range r.Tags {
+		if s, ok := sigmaStatuses[strings.TrimPrefix(t, "maturity_")]; ok && strings.HasPrefix(t, "maturity_") {
+			status = s
+		}
+	}
+
+	var selections []*yaml.Node
+	condition := sigmaSelections(n, &selections, false)
+	detection := &yaml.Node{Kind: yaml.MappingNode}
+	for i, sel := range selections {
+		name := fmt.Sprintf("selection_%d", i+1)
+		if len(selections) == 1 {
+			name = "selection"
+			condition = strings.ReplaceAll(condition, "selection_1", name)
+		}
+		detection.Content = append(detection.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, sel)
+	}
+	detection.Content = append(detection.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: "condition"},
+		&yaml.Node{Kind: yaml.ScalarNode, Value: condition})
+
+	res := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value *yaml.Node) {
+		res.Content = append(res.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	}
+	scalar := func(s string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: s}
+	}
+	add("title", scalar(r.Name()))
+	add("id", scalar(sigmaRuleID(r.Name())))
+	add("status", scalar(status))
+	add("description", scalar(strings.Join(strings.Fields(r.Desc), " ")))
+	if tags := sigmaTags(r.Tags); len(tags) > 0 {
+		seq := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, t := range tags {
+			seq.Content = append(seq.Content, scalar(t))
+		}
+		add("tags", seq)
+	}
+	add("logsource", &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		scalar("product"), scalar("linux"),
+		scalar("category"), scalar(cat.Name),
+	}})
+	add("detection", detection)
+	add("level", scalar(level))
+	return res, nil
+}
+
+// exportSkippedRule is a rule that could not be exported.
+type exportSkippedRule struct {
+	Rule     string `json:"rule"`
+	Location string `json:"location"`
+	Reason   string `json:"reason"`
+}
+
+// exportReport reports the rules exported and the ones that could not be
+// converted.
+type exportReport struct {
+	Format   string              `json:"format"`
+	Exported []string            `json:"exported"`
+	Skipped  []exportSkippedRule `json:"skipped"`
+}
+
+// exportSigmaRules converts the rules of a ruleset to a stream of Sigma YAML
+// documents, and reports the rules that could not be converted.
+func exportSigmaRules(rs *ruleset, skipDisabled bool) ([]byte, *exportReport, error) {
+	rep := &exportReport{Format: "sigma"}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	for _, r := range rs.Rules {
+		if skipDisabled && !r.IsEnabled() {
+			continue
+		}
+		doc, err := sigmaRule(rs, r)
+		if err != nil {
+			rep.Skipped = append(rep.Skipped, exportSkippedRule{Rule: r.Name(), Location: r.Loc.String(), Reason: err.Error()})
+			continue
+		}
+		if err := enc.Encode(doc); err != nil {
+			return nil, nil, err
+		}
+		rep.Exported = append(rep.Exported, r.Name())
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(rep.Skipped, func(i, j int) bool { return rep.Skipped[i].Reason < rep.Skipped[j].Reason })
+	return buf.Bytes(), rep, nil
+}
+
+func writeExportReport(w io.Writer, rep *exportReport) {
+	fmt.Fprintf(w, "Exported %d rules to %s\n", len(rep.Exported), rep.Format)
+	if len(rep.Skipped) == 0 {
+		return
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "**Not converted** rules:")
+	for _, s := range rep.Skipped {
+		fmt.Fprintf(w, "* Rule `%s` (%s): %s\n", s.Rule, s.Location, s.Reason)
+	}
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Convert rules to other detection formats, reporting the rules that could not be converted",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		exportFormat, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+
+		writePath, err := cmd.Flags().GetString("write")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		skipDisabled, err := cmd.Flags().GetBool("skip-disabled")
+		if err != nil {
+			return err
+		}
+
+		if exportFormat != "sigma" {
+			return fmt.Errorf("unsupported export format '%s'", exportFormat)
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		rs, err := loadRuleset(rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+
+		content, report, err := exportSigmaRules(rs, skipDisabled)
+		if err != nil {
+			return err
+		}
+
+		// the report goes to stderr if the exported rules are printed
+		w := cmd.OutOrStdout()
+		if len(writePath) > 0 {
+			if err := os.WriteFile(writePath, content, 0644); err != nil {
+				return err
+			}
+		} else {
+			cmd.OutOrStdout().Write(content)
+			w = cmd.ErrOrStderr()
+		}
+
+		switch format {
+		case "text":
+			writeExportReport(w, report)
+		case "json":
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(report)
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	exportCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	exportCmd.Flags().String("format", "sigma", "Format the rules are converted to, one of: sigma")
+	exportCmd.Flags().StringP("write", "w", "", "File to write the converted rules to, instead of printing them")
+	exportCmd.Flags().StringP("output", "o", "text", "Output format of the export report, one of: text, json")
+	exportCmd.Flags().Bool("skip-disabled", false, "Skip the rules that are disabled at default")
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigmaTags(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t,
+		[]string{"attack.execution", "attack.privilege-escalation", "attack.t1059", "attack.t1059.004"},
+		sigmaTags([]string{"maturity_stable", "mitre_execution", "host", "mitre_privilege_escalation", "T1059", "T1059.004", "Tx"}))
+}
+
+func TestSigmaRuleID(t *testing.T) {
+	t.Parallel()
+	id := sigmaRuleID("Terminal shell")
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, id)
+	assert.Equal(t, id, sigmaRuleID("Terminal shell"))
+	assert.NotEqual(t, id, sigmaRuleID("Terminal shell in container"))
+}
+
+func TestExportSigmaRules(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- list: shell_binaries
+  items: [bash, sh]
+
+- macro: spawned_process
+  condition: evt.type in (execve, execveat) and evt.dir=<
+
+- macro: open_write
+  condition: evt.type in (open, openat, openat2) and evt.is_open_write=true and fd.typechar='f' and fd.num>=0
+
+- macro: user_known_shells
+  condition: (evt.num=0)
+
+- rule: Shell spawned
+  desc: >
+    Detect a shell
+    spawned by a web server.
+  condition: >
+    spawned_process and proc.name in (shell_binaries) and proc.pname = nginx
+    and not proc.cmdline contains "-c *" and not user_known_shells
+  output: shell (proc=%proc.name)
+  priority: WARNING
+  tags: [maturity_stable, host, mitre_execution, T1059.004]
+
+- rule: Write below etc
+  desc: Detect writes below /etc.
+  condition: open_write and (fd.name pmatch (/etc) or fd.name glob "/usr/*/etc/*") and proc.exepath != /usr/bin/vi
+  output: write (file=%fd.name)
+  priority: ERROR
+  tags: [maturity_sandbox]
+
+- rule: Read sensitive file
+  desc: test
+  condition: evt.type = open and evt.is_open_read=true and fd.name = /etc/shadow
+  output: read (file=%fd.name)
+  priority: WARNING
+
+- rule: Ptrace
+  desc: test
+  condition: evt.type = ptrace and proc.name = gdb
+  output: ptrace
+  priority: NOTICE
+
+- rule: Mixed
+  desc: test
+  condition: (evt.type = execve and proc.name = sh) or (evt.type = open and fd.name = /etc/passwd)
+  output: mixed
+  priority: NOTICE
+
+- rule: Shell ancestor
+  desc: test
+  condition: spawned_process and tolower(proc.name) = bash
+  output: shell
+  priority: NOTICE
+
+- rule: Shell cmdline
+  desc: test
+  condition: spawned_process and proc.cmdline startswith "bash -c"
+  output: shell
+  priority: NOTICE
+
+- rule: Never
+  desc: test
+  condition: spawned_process and user_known_shells
+  output: never
+  priority: NOTICE
+
+- rule: All events
+  desc: test
+  condition: proc.name = sh
+  output: all
+  priority: NOTICE
+
+- rule: Audit
+  desc: test
+  condition: ka.verb = create
+  output: audit
+  priority: INFO
+  source: k8s_audit
+`)
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+
+	content, rep, err := exportSigmaRules(rs, false)
+	require.NoError(t, err)
+	assert.Equal(t, "sigma", rep.Format)
+	assert.Equal(t, []string{"Shell spawned", "Write below etc"}, rep.Exported)
+	assert.Equal(t, `title: Shell spawned
+id: `+sigmaRuleID("Shell spawned")+`
+status: stable
+description: Detect a shell spawned by a web server.
+tags:
+    - attack.execution
+    - attack.t1059.004
+logsource:
+    product: linux
+    category: process_creation
+detection:
+    selection_1:
+        Image|endswith:
+            - /bash
+            - /sh
+        ParentImage|endswith: /nginx
+    selection_2:
+        CommandLine|contains: -c \*
+    condition: selection_1 and not selection_2
+level: medium
+---
+title: Write below etc
+id: `+sigmaRuleID("Write below etc")+`
+status: experimental
+description: Detect writes below /etc.
+logsource:
+    product: linux
+    category: file_event
+detection:
+    selection_1:
+        TargetFilename:
+            - /etc
+            - /usr/*/etc/*
+    selection_2:
+        TargetFilename|startswith: /etc/
+    selection_3:
+        Image: /usr/bin/vi
+    condition: (selection_1 or selection_2) and not selection_3
+level: high
+`, string(content))
+
+	reasons := make(map[string]string)
+	for _, s := range rep.Skipped {
+		reasons[s.Rule] = s.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"Read sensitive file": "field `evt.is_open_read` has no Sigma equivalent in the `file_event` log source",
+		"Ptrace":              "event type `ptrace` has no Sigma log source category",
+		"Mixed":               "event types belong to more than one Sigma log source category (process_creation, file_event)",
+		"Shell ancestor":      "transformer `tolower` in `tolower(proc.name)=bash` has no Sigma equivalent",
+		"Shell cmdline":       "operator `startswith` on `proc.cmdline` has no exact Sigma equivalent",
+		"Never":               "condition can never match",
+		"All events":          "condition matches all event types",
+		"Audit":               "source `k8s_audit` is not supported",
+	}, reasons)
+
+	var buf bytes.Buffer
+	writeExportReport(&buf, rep)
+	assert.Contains(t, buf.String(), "Exported 2 rules to sigma\n\n**Not converted** rules:\n")
+	assert.Contains(t, buf.String(), "* Rule `Ptrace` (a.yaml:38): event type `ptrace` has no Sigma log source category\n")
+}
@@ -0,0 +1,797 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultFmtWidth is the maximum line width used when wrapping flow lists.
+const defaultFmtWidth = 120
+
+// fmtKeysOrder is the canonical order of the keys of rules file items and of
+// their nested objects, indexed by item kind or by the key containing them.
+// Keys not listed here follow in their original order.
+var fmtKeysOrder = map[string][]string{
+	itemKindRule:   {"rule", "desc", "condition", "output", "priority", "tags", "source", "enabled", "exceptions", "append", "override"},
+	itemKindMacro:  {"macro", "condition", "append", "override"},
+	itemKindList:   {"list", "items", "append", "override"},
+	itemKindPlugin: {"name", "version", "alternatives"},
+	"alternatives": {"name", "version"},
+	"exceptions":   {"name", "fields", "comps", "values"},
+}
+
+// fmtComment is a block of full-line comments preceding a node.
+type fmtComment struct {
+	// Lines contains the comment lines, with empty strings for blank lines
+	Lines []string
+	// BlankAfter is true if a blank line separates the comment from the node
+	BlankAfter bool
+}
+
+// rulesFileFormatter rewrites a rules file in canonical style. Scalars are
+// copied from the source text, so that quoting and the line breaks of
+// multi-line conditions are preserved.
+type rulesFileFormatter struct {
+	lines []string
+	width int
+	// anchors contains the first node starting at each line, to which the
+	// full-line comments preceding the line are attached
+	anchors  map[int]*yaml.Node
+	comments map[*yaml.Node]*fmtComment
+	footer   *fmtComment
+	// ends contains the last line of each scalar
+	ends map[*yaml.Node]int
+	out  []string
+}
+
+// formatRulesFile returns the content of a rules file in canonical style.
+// An error is returned if the formatted content would not be equivalent to
+// the original one, or if some comments would be lost.
+func formatRulesFile(content []byte, width int) ([]byte, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(content))
+	var root yaml.Node
+	if err := dec.Decode(&root); err != nil {
+		if errors.Is(err, io.EOF) {
+			return content, nil
+		}
+		return nil, err
+	}
+	var next yaml.Node
+	if err := dec.Decode(&next); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("multiple YAML documents are not supported")
+	}
+	if len(root.Content) == 0 {
+		return content, nil
+	}
+	seq := root.Content[0]
+	if seq.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("rules content is not yaml array of objects")
+	}
+	if len(seq.Content) == 0 {
+		return content, nil
+	}
+
+	f := &rulesFileFormatter{
+		lines:    strings.Split(strings.TrimRight(string(content), "\n"), "\n"),
+		width:    width,
+		anchors:  make(map[int]*yaml.Node),
+		comments: make(map[*yaml.Node]*fmtComment),
+		ends:     make(map[*yaml.Node]int),
+	}
+	for _, item := range seq.Content {
+		f.addAnchor(item)
+		if err := f.index(item); err != nil {
+			return nil, err
+		}
+	}
+	f.indexComments()
+
+	for i, item := range seq.Content {
+		if item.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%d: unexpected element type, expected an object", item.Line)
+		}
+		if i > 0 {
+			f.write("")
+		}
+		f.writeComment(f.comments[item], 0)
+		if err := f.emitMapping(item, 2, true, fmtItemKind(item)); err != nil {
+			return nil, err
+		}
+	}
+	if f.footer != nil {
+		f.write("")
+		f.writeComment(f.footer, 0)
+	}
+
+	res := []byte(strings.Join(f.out, "\n") + "\n")
+	if err := checkFormattedRulesFile(content, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// checkFormattedRulesFile returns an error if the formatted content of a rules
+// file doesn't decode to the same data as the original one, or if it doesn't
+// contain the same comments.
+func checkFormattedRulesFile(orig, formatted []byte) error {
+	var o, f interface{}
+	if err := yaml.Unmarshal(orig, &o); err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(formatted, &f); err != nil {
+		return fmt.Errorf("formatted content is not valid YAML: %s", err.Error())
+	}
+	if !reflect.DeepEqual(o, f) {
+		return fmt.Errorf("formatting would change the content of the file")
+	}
+	var on, fn yaml.Node
+	if err := yaml.Unmarshal(orig, &on); err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(formatted, &fn); err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(yamlComments(&on, nil), yamlComments(&fn, nil)) {
+		return fmt.Errorf("formatting would lose or move some comments")
+	}
+	return nil
+}
+
+// yamlComments returns the sorted comment lines of a YAML node and its
+// descendants.
+func yamlComments(n *yaml.Node, res []string) []string {
+	for _, c := range []string{n.HeadComment, n.LineComment, n.FootComment} {
+		for _, l := range strings.Split(c, "\n") {
+			if l = strings.TrimSpace(l); len(l) > 0 {
+				res = append(res, l)
+			}
+		}
+	}
+	for _, c := range n.Content {
+		res = yamlComments(c, res)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// fmtItemKind returns the kind of a rules file item, or an empty string if
+// unknown.
+func fmtItemKind(item *yaml.Node) string {
+	for _, k := range []string{itemKindRule, itemKindMacro, itemKindList, itemKindEngine, itemKindPlugin} {
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			if item.Content[i].Value == k {
+				return k
+			}
+		}
+	}
+	return ""
+}
+
+func (f *rulesFileFormatter) addAnchor(n *yaml.Node) {
+	if _, ok := f.anchors[n.Line]; !ok {
+		f.anchors[n.Line] = n
+	}
+}
+
+// index collects the anchors of the comments and the lines of the scalars
+// of a node and of its descendants.
+func (f *rulesFileFormatter) index(n *yaml.Node) error {
+	if n.Kind == yaml.AliasNode || len(n.Anchor) > 0 {
+		return fmt.Errorf("%d: anchors and aliases are not supported", n.Line)
+	}
+	if n.Style&yaml.TaggedStyle != 0 {
+		return fmt.Errorf("%d: explicit tags are not supported", n.Line)
+	}
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			f.addAnchor(n.Content[i])
+			if err := f.index(n.Content[i]); err != nil {
+				return err
+			}
+			if err := f.index(n.Content[i+1]); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, e := range n.Content {
+			f.addAnchor(e)
+			if err := f.index(e); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		lines, err := f.scalarLines(n)
+		if err != nil {
+			return err
+		}
+		f.ends[n] = n.Line + len(lines) - 1
+	}
+	return nil
+}
+
+// indexComments attaches each block of full-line comments to the first node
+// starting at or after the line following it. The comments following all the
+// nodes are the footer of the file.
+func (f *rulesFileFormatter) indexComments() {
+	inScalar := make(map[int]bool)
+	for n, end := range f.ends {
+		for l := n.Line + 1; l <= end; l++ {
+			inScalar[l] = true
+		}
+	}
+	var anchorLines []int
+	for l := range f.anchors {
+		anchorLines = append(anchorLines, l)
+	}
+	sort.Ints(anchorLines)
+
+	var pending *fmtComment
+	blank := false
+	for i, l := range f.lines {
+		line := i + 1
+		t := strings.TrimSpace(l)
+		switch {
+		case inScalar[line]:
+			continue
+		case len(t) == 0:
+			blank = true
+		case strings.HasPrefix(t, "#"):
+			if pending == nil {
+				pending = &fmtComment{}
+			} else if blank {
+				pending.Lines = append(pending.Lines, "")
+			}
+			pending.Lines = append(pending.Lines, t)
+			blank = false
+		default:
+			if pending != nil {
+				pending.BlankAfter = blank
+				idx := sort.SearchInts(anchorLines, line)
+				if idx < len(anchorLines) {
+					n := f.anchors[anchorLines[idx]]
+					if c, ok := f.comments[n]; ok {
+						pending.Lines = append(c.Lines, pending.Lines...)
+					}
+					f.comments[n] = pending
+				} else {
+					f.footer = pending
+				}
+				pending = nil
+			}
+			blank = false
+		}
+	}
+	if pending != nil {
+		if f.footer != nil {
+			pending.Lines = append(append(f.footer.Lines, ""), pending.Lines...)
+		}
+		f.footer = pending
+	}
+}
+
+// lineSuffix returns the part of a line starting at the given 1-based column.
+func lineSuffix(line string, column int) string {
+	r := []rune(line)
+	if column-1 > len(r) {
+		return ""
+	}
+	return string(r[column-1:])
+}
+
+// quotedScalarEnd returns the index of the quote closing the quoted scalar at
+// the beginning of s, or -1 if not found.
+func quotedScalarEnd(s string) int {
+	if len(s) == 0 {
+		return -1
+	}
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case s[i] == q && q == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == q:
+			return i
+		}
+	}
+	return -1
+}
+
+// plainScalarText returns the text of a plain scalar on a line, stopping at
+// comments, and at flow indicators if in a flow collection.
+func plainScalarText(s string, flow bool) string {
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = s[:i]
+	}
+	if flow {
+		if i := strings.IndexAny(s, ",]}"); i >= 0 {
+			s = s[:i]
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// foldPlainLines folds the lines of a multi-line plain scalar.
+func foldPlainLines(lines []string) string {
+	var b strings.Builder
+	breaks := 0
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+		if len(l) == 0 {
+			breaks++
+			continue
+		}
+		if b.Len() > 0 {
+			if breaks == 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(strings.Repeat("\n", breaks))
+		}
+		b.WriteString(l)
+		breaks = 0
+	}
+	return b.String()
+}
+
+// scalarLines returns the source lines of a scalar, starting from its first
+// character. Block scalars include their header line, and the comments of
+// plain scalars are removed.
+func (f *rulesFileFormatter) scalarLines(n *yaml.Node) ([]string, error) {
+	first := lineSuffix(f.lines[n.Line-1], n.Column)
+	switch {
+	case n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		res := []string{first}
+		indent := -1
+		for _, l := range f.lines[n.Line:] {
+			if len(strings.TrimSpace(l)) == 0 {
+				res = append(res, "")
+				continue
+			}
+			cur := len(l) - len(strings.TrimLeft(l, " "))
+			if indent < 0 {
+				indent = cur
+			}
+			if cur < indent {
+				break
+			}
+			res = append(res, l)
+		}
+		for len(res) > 1 && len(res[len(res)-1]) == 0 {
+			res = res[:len(res)-1]
+		}
+		return res, nil
+	case n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0:
+		text := strings.Join(append([]string{first}, f.lines[n.Line:]...), "\n")
+		end := quotedScalarEnd(text)
+		if end < 0 {
+			return nil, fmt.Errorf("%d: unterminated quoted scalar", n.Line)
+		}
+		return strings.Split(text[:end+1], "\n"), nil
+	}
+	// single-line plain scalars, either values, keys, or items of flow
+	// collections
+	text := plainScalarText(first, false)
+	candidates := []string{text, plainScalarText(first, true)}
+	if i := strings.Index(first+" ", ": "); i >= 0 {
+		candidates = append(candidates, strings.TrimSpace(first[:i]))
+	}
+	for _, c := range candidates {
+		if c == n.Value || len(c) == 0 && n.Tag == "!!null" {
+			return []string{c}, nil
+		}
+	}
+	res := []string{text}
+	for i := n.Line; i < len(f.lines) && foldPlainLines(res) != n.Value; i++ {
+		res = append(res, plainScalarText(f.lines[i], false))
+	}
+	if foldPlainLines(res) != n.Value {
+		return nil, fmt.Errorf("%d: can't find the source text of scalar '%s'", n.Line, n.Value)
+	}
+	return res, nil
+}
+
+func (f *rulesFileFormatter) write(line string) {
+	f.out = append(f.out, line)
+}
+
+func (f *rulesFileFormatter) writeComment(c *fmtComment, indent int) {
+	if c == nil {
+		return
+	}
+	for _, l := range c.Lines {
+		if len(l) == 0 {
+			f.write("")
+		} else {
+			f.write(strings.Repeat(" ", indent) + l)
+		}
+	}
+	if c.BlankAfter {
+		f.write("")
+	}
+}
+
+// lineComment returns the trailing comment of the given nodes, if any.
+func lineComment(nodes ...*yaml.Node) string {
+	var res []string
+	for _, n := range nodes {
+		if len(n.LineComment) > 0 {
+			res = append(res, n.LineComment)
+		}
+	}
+	if len(res) == 0 {
+		return ""
+	}
+	return "  " + strings.Join(res, " ")
+}
+
+// orderedPairs returns the key-value pairs of a mapping in canonical order.
+func orderedPairs(m *yaml.Node, kind string) [][2]*yaml.Node {
+	var res [][2]*yaml.Node
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		res = append(res, [2]*yaml.Node{m.Content[i], m.Content[i+1]})
+	}
+	order := fmtKeysOrder[kind]
+	rank := func(k string) int {
+		for i, o := range order {
+			if o == k {
+				return i
+			}
+		}
+		return len(order)
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return rank(res[i][0].Value) < rank(res[j][0].Value)
+	})
+	return res
+}
+
+// emitMapping writes a block mapping with keys at the given indentation,
+// prefixing the first key with a dash if the mapping is a sequence element.
+func (f *rulesFileFormatter) emitMapping(m *yaml.Node, indent int, dash bool, kind string) error {
+	for i, p := range orderedPairs(m, kind) {
+		prefix := strings.Repeat(" ", indent)
+		if i == 0 && dash {
+			f.writeComment(f.comments[p[0]], indent-2)
+			prefix = strings.Repeat(" ", indent-2) + "- "
+		} else {
+			f.writeComment(f.comments[p[0]], indent)
+		}
+		key, err := f.flowScalar(p[0], false)
+		if err != nil {
+			return err
+		}
+		head := prefix + key + ":"
+		comment := lineComment(p[0], p[1])
+		switch p[1].Kind {
+		case yaml.ScalarNode:
+			err = f.emitScalar(head, comment, p[1], indent+2)
+		case yaml.SequenceNode:
+			err = f.emitSequence(head, comment, p[0].Value, p[1], indent+2)
+		case yaml.MappingNode:
+			f.write(head + comment)
+			err = f.emitMapping(p[1], indent+2, false, p[0].Value)
+		default:
+			err = fmt.Errorf("%d: unsupported value of key '%s'", p[1].Line, p[0].Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// emitScalar writes the value of a key. Single-line scalars and block scalars
+// are copied as-is, while multi-line plain scalars are turned into folded
+// block scalars.
+func (f *rulesFileFormatter) emitScalar(head, comment string, n *yaml.Node, indent int) error {
+	lines, err := f.scalarLines(n)
+	if err != nil {
+		return err
+	}
+	pad := strings.Repeat(" ", indent)
+	if len(lines) == 1 && n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+		if len(lines[0]) == 0 {
+			f.write(head + comment)
+		} else {
+			f.write(head + " " + lines[0] + comment)
+		}
+		return nil
+	}
+
+	switch {
+	case n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0:
+		header := strings.Fields(lines[0])[0]
+		if strings.ContainsAny(header, "+0123456789") {
+			return fmt.Errorf("%d: block scalars with explicit indentation or keep chomping are not supported", n.Line)
+		}
+		f.write(head + " " + header + comment)
+		indent := -1
+		for _, l := range lines[1:] {
+			if cur := len(l) - len(strings.TrimLeft(l, " ")); len(l) > 0 && (indent < 0 || cur < indent) {
+				indent = cur
+			}
+		}
+		for _, l := range lines[1:] {
+			if len(l) == 0 {
+				f.write("")
+			} else {
+				f.write(pad + l[indent:])
+			}
+		}
+	case n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0:
+		f.write(head + " " + strings.TrimRight(lines[0], " "))
+		for i, l := range lines[1:] {
+			l = strings.TrimSpace(l)
+			if i == len(lines)-2 {
+				l += comment
+			}
+			if len(l) == 0 {
+				f.write("")
+			} else {
+				f.write(pad + l)
+			}
+		}
+	default:
+		f.write(head + " >-" + comment)
+		for _, l := range lines {
+			if len(l) == 0 {
+				f.write("")
+			} else {
+				f.write(pad + l)
+			}
+		}
+	}
+	return nil
+}
+
+// flowScalar returns the text of a single-line scalar, quoting plain scalars
+// that are not valid in flow collections.
+func (f *rulesFileFormatter) flowScalar(n *yaml.Node, flow bool) (string, error) {
+	lines, err := f.scalarLines(n)
+	if err != nil {
+		return "", err
+	}
+	if len(lines) != 1 || n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		return "", fmt.Errorf("%d: multi-line scalars are not supported in lists and keys", n.Line)
+	}
+	text := lines[0]
+	if flow && n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) == 0 &&
+		(strings.ContainsAny(text, ",[]{}") || strings.Contains(text, ": ") || strings.HasSuffix(text, ":")) {
+		var b bytes.Buffer
+		enc := json.NewEncoder(&b)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(n.Value); err != nil {
+			return "", err
+		}
+		text = strings.TrimSpace(b.String())
+	}
+	if len(text) == 0 && n.Tag == "!!null" {
+		text = "null"
+	}
+	return text, nil
+}
+
+// emitSequence writes the value of a key that is a sequence. Lists of scalars
+// are written in flow style, wrapped to the maximum line width or following
+// the line grouping of the source if their items have comments. Lists of
+// lists of scalars are written as block sequences of flow sequences.
+func (f *rulesFileFormatter) emitSequence(head, comment, key string, n *yaml.Node, indent int) error {
+	if len(n.Content) == 0 {
+		f.write(head + " []" + comment)
+		return nil
+	}
+	scalars, tuples := true, true
+	for _, e := range n.Content {
+		scalars = scalars && e.Kind == yaml.ScalarNode
+		tuples = tuples && e.Kind == yaml.SequenceNode && isScalarsSequence(e)
+	}
+	pad := strings.Repeat(" ", indent)
+	switch {
+	case scalars:
+		return f.emitFlowSequence(head, comment, n, indent)
+	case tuples:
+		f.write(head + comment)
+		for _, e := range n.Content {
+			f.writeComment(f.comments[e], indent)
+			toks, err := f.flowScalars(e)
+			if err != nil {
+				return err
+			}
+			f.write(pad + "- [" + strings.Join(toks, ", ") + "]" + lineComment(e))
+		}
+		return nil
+	}
+	f.write(head + comment)
+	for _, e := range n.Content {
+		f.writeComment(f.comments[e], indent)
+		switch e.Kind {
+		case yaml.MappingNode:
+			if err := f.emitMapping(e, indent+2, true, key); err != nil {
+				return err
+			}
+		case yaml.ScalarNode:
+			text, err := f.flowScalar(e, false)
+			if err != nil {
+				return err
+			}
+			f.write(pad + "- " + text + lineComment(e))
+		default:
+			return fmt.Errorf("%d: unsupported nested sequence", e.Line)
+		}
+	}
+	return nil
+}
+
+func isScalarsSequence(n *yaml.Node) bool {
+	for _, e := range n.Content {
+		if e.Kind != yaml.ScalarNode {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *rulesFileFormatter) flowScalars(n *yaml.Node) ([]string, error) {
+	var res []string
+	for _, e := range n.Content {
+		text, err := f.flowScalar(e, true)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, text)
+	}
+	return res, nil
+}
+
+func (f *rulesFileFormatter) emitFlowSequence(head, comment string, n *yaml.Node, indent int) error {
+	toks, err := f.flowScalars(n)
+	if err != nil {
+		return err
+	}
+	grouped := false
+	for _, e := range n.Content {
+		grouped = grouped || f.comments[e] != nil || len(e.LineComment) > 0
+	}
+	if line := head + " [" + strings.Join(toks, ", ") + "]"; !grouped && len(line) <= f.width {
+		f.write(line + comment)
+		return nil
+	}
+
+	f.write(head + " [" + comment)
+	pad := strings.Repeat(" ", indent)
+	if grouped {
+		// the items of each source line are kept together, along with their
+		// comments
+		start := 0
+		for i := range n.Content {
+			last := i == len(n.Content)-1
+			if !last && n.Content[i+1].Line == n.Content[i].Line && f.comments[n.Content[i+1]] == nil {
+				continue
+			}
+			f.writeComment(f.comments[n.Content[start]], indent)
+			line := pad + strings.Join(toks[start:i+1], ", ")
+			if !last {
+				line += ","
+			}
+			f.write(line + lineComment(n.Content[start:i+1]...))
+			start = i + 1
+		}
+	} else {
+		cur := ""
+		for _, t := range toks {
+			switch {
+			case len(cur) == 0:
+				cur = t
+			case len(pad)+len(cur)+len(t)+3 > f.width:
+				f.write(pad + cur + ",")
+				cur = t
+			default:
+				cur += ", " + t
+			}
+		}
+		f.write(pad + cur)
+	}
+	f.write(pad + "]")
+	return nil
+}
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [flags] [rules files...]",
+	Short: "Rewrite rules files in canonical style, preserving comments",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+		// rules files can also be passed as arguments, like with gofmt
+		rulesFilesPaths = append(rulesFilesPaths, args...)
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		check, err := cmd.Flags().GetBool("check")
+		if err != nil {
+			return err
+		}
+
+		width, err := cmd.Flags().GetInt("width")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		err = nil
+		for _, path := range rulesFilesPaths {
+			content, readErr := os.ReadFile(path)
+			if readErr != nil {
+				err = errAppend(err, readErr)
+				continue
+			}
+			formatted, fmtErr := formatRulesFile(content, width)
+			if fmtErr != nil {
+				err = errAppend(err, fmt.Errorf("%s: %s", path, fmtErr.Error()))
+				continue
+			}
+			if bytes.Equal(content, formatted) {
+				continue
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+			if check {
+				err = errAppend(err, fmt.Errorf("%s: rules file is not formatted", path))
+				continue
+			}
+			info, statErr := os.Stat(path)
+			if statErr != nil {
+				err = errAppend(err, statErr)
+				continue
+			}
+			if writeErr := os.WriteFile(path, formatted, info.Mode()); writeErr != nil {
+				err = errAppend(err, writeErr)
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	fmtCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be formatted, in addition to the ones passed as arguments (defaults to the rules files of the registry)")
+	fmtCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	fmtCmd.Flags().Bool("check", false, "Only list the rules files that are not formatted, failing if any")
+	fmtCmd.Flags().Int("width", defaultFmtWidth, "Maximum line width when wrapping lists")
+	rootCmd.AddCommand(fmtCmd)
+}
@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatRulesFile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input    string
+		expected string
+	}{
+		"key-order": {
+			input: `- rule: r1
+  tags: [a, b]
+  priority: WARNING
+  enabled: false
+  condition: evt.type = execve
+  output: "proc=%proc.name"
+  desc: my rule
+`,
+			expected: `- rule: r1
+  desc: my rule
+  condition: evt.type = execve
+  output: "proc=%proc.name"
+  priority: WARNING
+  tags: [a, b]
+  enabled: false
+`,
+		},
+		"flow-tags": {
+			input: `- rule: r1
+  desc: my rule
+  condition: evt.type = execve
+  output: test
+  priority: WARNING
+  tags:
+    - a
+    - b
+`,
+			expected: `- rule: r1
+  desc: my rule
+  condition: evt.type = execve
+  output: test
+  priority: WARNING
+  tags: [a, b]
+`,
+		},
+		"list-wrapping": {
+			input: `- list: l1
+  items: [aaaaaaaaaa, bbbbbbbbbb,
+          cccccccccc, "dd,dd"]
+- list: l2
+  items:
+    - aaaaaaaaaa
+    - bbbbbbbbbb
+    - cccccccccc
+    - dd,dd
+    - 'e'
+`,
+			expected: `- list: l1
+  items: [
+    aaaaaaaaaa, bbbbbbbbbb, cccccccccc,
+    "dd,dd"
+    ]
+
+- list: l2
+  items: [
+    aaaaaaaaaa, bbbbbbbbbb, cccccccccc,
+    "dd,dd", 'e'
+    ]
+`,
+		},
+		"short-list": {
+			input: `- list: l1
+  items: [
+    a, b
+    ]
+- list: l2
+  items:
+`,
+			expected: `- list: l1
+  items: [a, b]
+
+- list: l2
+  items:
+`,
+		},
+		"conditions": {
+			input: `- macro: m1
+  condition: (proc.name = a or
+              proc.name = b)
+- macro: m2
+  condition: >
+      proc.name = a
+        or proc.name = b
+- macro: m3
+  condition: proc.name = "a
+    b"
+`,
+			expected: `- macro: m1
+  condition: >-
+    (proc.name = a or
+    proc.name = b)
+
+- macro: m2
+  condition: >
+    proc.name = a
+      or proc.name = b
+
+- macro: m3
+  condition: >-
+    proc.name = "a
+    b"
+`,
+		},
+		"comments": {
+			input: `# license
+
+# header
+- required_engine_version: 0.31.0
+# list comment
+- list: l1  # trailing
+  # items comment
+  items: [
+    a, b,  # ab
+    # c comment
+    c
+    ]
+
+
+- macro: m1
+  # condition comment
+  condition: >
+    a
+    # not a comment
+    and b
+
+# footer
+`,
+			expected: `# license
+
+# header
+- required_engine_version: 0.31.0
+
+# list comment
+- list: l1  # trailing
+  # items comment
+  items: [
+    a, b,  # ab
+    # c comment
+    c
+    ]
+
+- macro: m1
+  # condition comment
+  condition: >
+    a
+    # not a comment
+    and b
+
+# footer
+`,
+		},
+		"exceptions": {
+			input: `- rule: r1
+  desc: my rule
+  condition: evt.type = execve
+  output: test
+  priority: WARNING
+  exceptions:
+    - values: [[a, b], [c, d]]
+      fields: [proc.name, fd.name]
+      name: ex1
+  tags: [a]
+`,
+			expected: `- rule: r1
+  desc: my rule
+  condition: evt.type = execve
+  output: test
+  priority: WARNING
+  tags: [a]
+  exceptions:
+    - name: ex1
+      fields: [proc.name, fd.name]
+      values:
+        - [a, b]
+        - [c, d]
+`,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			res, err := formatRulesFile([]byte(test.input), 40)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(res))
+			again, err := formatRulesFile(res, 40)
+			require.NoError(t, err)
+			assert.Equal(t, string(res), string(again))
+		})
+	}
+
+	t.Run("unsupported", func(t *testing.T) {
+		t.Parallel()
+		_, err := formatRulesFile([]byte("- list: a\n  items: [a]\n---\n- list: b\n  items: [b]\n"), 40)
+		assert.Error(t, err)
+		_, err = formatRulesFile([]byte("- list: a\n  items: &x [a]\n- list: b\n  items: *x\n"), 40)
+		assert.Error(t, err)
+		_, err = formatRulesFile([]byte("list: a\n"), 40)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+)
+
+// releaseTagRegex matches the git tags of the releases of a rules file, such
+// as `falco-rules-1.2.3`.
+var releaseTagRegex = regexp.MustCompile(`^(.+)-([0-9]+\.[0-9]+\.[0-9]+(?:-[0-9A-Za-z.-]+)?)$`)
+
+// parseReleaseTag returns the rules file name and the version of a release
+// tag, and false if the tag is not a release tag.
+func parseReleaseTag(tag string) (string, semver.Version, bool) {
+	m := releaseTagRegex.FindStringSubmatch(tag)
+	if m == nil {
+		return "", semver.Version{}, false
+	}
+	v, err := semver.Parse(m[2])
+	if err != nil {
+		return "", semver.Version{}, false
+	}
+	return m[1], v, true
+}
+
+// runGit runs a git command in the current directory and returns its
+// standard output.
+func runGit(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("git", args...)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		return nil, fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// gitShowFile returns the content of a file at a git revision, and false if
+// the file doesn't exist at the revision. The path is relative to the current
+// directory.
+func gitShowFile(rev, path string) ([]byte, bool, error) {
+	if _, err := runGit("rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		return nil, false, fmt.Errorf("unknown git revision '%s'", rev)
+	}
+	obj := rev + ":./" + filepath.ToSlash(filepath.Clean(path))
+	if _, err := runGit("cat-file", "-e", obj); err != nil {
+		return nil, false, nil
+	}
+	content, err := runGit("show", obj)
+	return content, err == nil, err
+}
+
+// loadRulesFileAt loads a rules file at a git revision, and returns nil if the
+// file doesn't exist at the revision.
+func loadRulesFileAt(rev, path string) (*rulesFile, error) {
+	content, ok, err := gitShowFile(rev, path)
+	if err != nil || !ok {
+		return nil, err
+	}
+	f, err := parseRulesFile(path, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s (at %s): %s", path, rev, err.Error())
+	}
+	return f, nil
+}
+
+// loadRulesetAt loads the rules files existing at a git revision in order.
+func loadRulesetAt(rev string, paths ...string) (*ruleset, error) {
+	rs := newRuleset()
+	for _, path := range paths {
+		f, err := loadRulesFileAt(rev, path)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			continue
+		}
+		if err := rs.Add(f); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
+
+// gitReleaseTags returns the release tags of a rules file, sorted by
+// increasing version.
+func gitReleaseTags(name string) ([]string, error) {
+	out, err := runGit("tag", "--list", name+"-*")
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	versions := make(map[string]semver.Version)
+	for _, tag := range strings.Fields(string(out)) {
+		if n, v, ok := parseReleaseTag(tag); ok && n == name {
+			res = append(res, tag)
+			versions[tag] = v
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool { return versions[res[i]].LT(versions[res[j]]) })
+	return res, nil
+}
+
+// gitRevDate returns the commit date of a git revision, formatted as
+// YYYY-MM-DD.
+func gitRevDate(rev string) (string, error) {
+	out, err := runGit("log", "-1", "--format=%cs", rev)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,341 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// depGraphNode is a rule, macro, or list in a dependency graph.
+type depGraphNode struct {
+	ID        string `json:"id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Unused    bool   `json:"unused"`
+	Undefined bool   `json:"undefined"`
+}
+
+// depGraphEdge is a dependency from a rule, macro, or list to a macro or list.
+type depGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// depGraph is the dependency graph between the rules, macros and lists
+// of a ruleset.
+type depGraph struct {
+	Nodes []*depGraphNode `json:"nodes"`
+	Edges []depGraphEdge  `json:"edges"`
+	nodes map[string]*depGraphNode
+	edges map[depGraphEdge]bool
+}
+
+func depGraphNodeID(kind, name string) string {
+	return kind + ":" + name
+}
+
+func (g *depGraph) addNode(kind, name string) *depGraphNode {
+	id := depGraphNodeID(kind, name)
+	if n, ok := g.nodes[id]; ok {
+		return n
+	}
+	n := &depGraphNode{ID: id, Kind: kind, Name: name}
+	g.nodes[id] = n
+	g.Nodes = append(g.Nodes, n)
+	return n
+}
+
+func (g *depGraph) addEdge(from, to string) {
+	e := depGraphEdge{From: from, To: to}
+	if !g.edges[e] {
+		g.edges[e] = true
+		g.Edges = append(g.Edges, e)
+	}
+}
+
+// Node returns the node with the given id, or nil if not found.
+func (g *depGraph) Node(id string) *depGraphNode {
+	return g.nodes[id]
+}
+
+// Dependencies returns the ids of the nodes that a given node depends on.
+func (g *depGraph) Dependencies(id string) []string {
+	var res []string
+	for _, e := range g.Edges {
+		if e.From == id {
+			res = append(res, e.To)
+		}
+	}
+	return res
+}
+
+// Dependents returns the ids of the nodes that depend on a given node.
+func (g *depGraph) Dependents(id string) []string {
+	var res []string
+	for _, e := range g.Edges {
+		if e.To == id {
+			res = append(res, e.From)
+		}
+	}
+	return res
+}
+
+// reachable returns the set of node ids reachable from the given ones,
+// following edges forward or backward.
+func (g *depGraph) reachable(ids []string, reverse bool) map[string]bool {
+	res := make(map[string]bool)
+	stack := append([]string{}, ids...)
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if res[id] {
+			continue
+		}
+		res[id] = true
+		if reverse {
+			stack = append(stack, g.Dependents(id)...)
+		} else {
+			stack = append(stack, g.Dependencies(id)...)
+		}
+	}
+	return res
+}
+
+// Filter returns a new graph containing only the given nodes.
+func (g *depGraph) Filter(ids map[string]bool) *depGraph {
+	res := newDepGraph()
+	for _, n := range g.Nodes {
+		if ids[n.ID] {
+			c := *n
+			res.nodes[c.ID] = &c
+			res.Nodes = append(res.Nodes, &c)
+		}
+	}
+	for _, e := range g.Edges {
+		if ids[e.From] && ids[e.To] {
+			res.addEdge(e.From, e.To)
+		}
+	}
+	return res
+}
+
+func newDepGraph() *depGraph {
+	return &depGraph{
+		nodes: make(map[string]*depGraphNode),
+		edges: make(map[depGraphEdge]bool),
+	}
+}
+
+// buildDepGraph returns the dependency graph of a ruleset. Macros and lists
+// that no rule depends on, directly or transitively, are marked as unused,
+// and references to macros and lists that are not defined are marked as
+// undefined.
+func buildDepGraph(rs *ruleset) (*depGraph, error) {
+	g := newDepGraph()
+	isList := func(name string) bool { return rs.List(name) != nil }
+
+	for _, r := range rs.Rules {
+		g.addNode(itemKindRule, r.Name())
+	}
+	for _, m := range rs.Macros {
+		g.addNode(itemKindMacro, m.Name())
+	}
+	for _, l := range rs.Lists {
+		g.addNode(itemKindList, l.Name())
+	}
+
+	for _, e := range append(append([]*rulesetEntry{}, rs.Rules...), rs.Macros...) {
+		c, err := e.ParseCondition()
+		if err != nil {
+			return nil, err
+		}
+		from := depGraphNodeID(e.Kind, e.Name())
+		for _, m := range conditionMacroRefs(c) {
+			n := g.addNode(itemKindMacro, m)
+			n.Undefined = rs.Macro(m) == nil
+			g.addEdge(from, n.ID)
+		}
+		for _, l := range conditionListRefs(c, isList) {
+			g.addEdge(from, g.addNode(itemKindList, l).ID)
+		}
+	}
+	for _, l := range rs.Lists {
+		from := depGraphNodeID(itemKindList, l.Name())
+		for _, item := range l.Items {
+			if isList(item) {
+				g.addEdge(from, depGraphNodeID(itemKindList, item))
+			}
+		}
+	}
+
+	var ruleIDs []string
+	for _, r := range rs.Rules {
+		ruleIDs = append(ruleIDs, depGraphNodeID(itemKindRule, r.Name()))
+	}
+	used := g.reachable(ruleIDs, false)
+	for _, n := range g.Nodes {
+		n.Unused = !used[n.ID]
+	}
+	return g, nil
+}
+
+// WriteDOT writes the graph in the Graphviz DOT format.
+func (g *depGraph) WriteDOT(w io.Writer) {
+	shapes := map[string]string{itemKindRule: "box", itemKindMacro: "ellipse", itemKindList: "note"}
+	fmt.Fprintln(w, "digraph rules {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	for _, n := range g.Nodes {
+		attrs := fmt.Sprintf("label=%s, shape=%s", dotQuote(n.Name), shapes[n.Kind])
+		if n.Undefined {
+			attrs += ", style=dashed, color=red"
+		} else if n.Unused {
+			attrs += ", style=filled, fillcolor=orange"
+		}
+		fmt.Fprintf(w, "  %s [%s];\n", dotQuote(n.ID), attrs)
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  %s -> %s;\n", dotQuote(e.From), dotQuote(e.To))
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(s, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// WriteMermaid writes the graph in the Mermaid flowchart format.
+func (g *depGraph) WriteMermaid(w io.Writer) {
+	ids := make(map[string]string)
+	fmt.Fprintln(w, "graph LR")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		label := strings.ReplaceAll(n.Name, `"`, "#quot;")
+		switch n.Kind {
+		case itemKindRule:
+			fmt.Fprintf(w, "  %s[\"%s\"]\n", ids[n.ID], label)
+		case itemKindMacro:
+			fmt.Fprintf(w, "  %s(\"%s\")\n", ids[n.ID], label)
+		default:
+			fmt.Fprintf(w, "  %s[/\"%s\"/]\n", ids[n.ID], label)
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(w, "  %s --> %s\n", ids[e.From], ids[e.To])
+	}
+	fmt.Fprintln(w, "  classDef unused fill:#f96")
+	fmt.Fprintln(w, "  classDef undefined stroke:#f00,stroke-dasharray:5")
+	for _, n := range g.Nodes {
+		if n.Undefined {
+			fmt.Fprintf(w, "  class %s undefined\n", ids[n.ID])
+		} else if n.Unused {
+			fmt.Fprintf(w, "  class %s unused\n", ids[n.ID])
+		}
+	}
+}
+
+// WriteJSON writes the graph in JSON format.
+func (g *depGraph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph [flags] <rules files...>",
+	Short: "Export the dependency graph between the rules, macros and lists of one or more rules files",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		rules, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		reverse, err := cmd.Flags().GetStringArray("reverse")
+		if err != nil {
+			return err
+		}
+
+		rs, err := loadRuleset(args...)
+		if err != nil {
+			return err
+		}
+
+		g, err := buildDepGraph(rs)
+		if err != nil {
+			return err
+		}
+
+		if len(rules) > 0 {
+			var ids []string
+			for _, r := range rules {
+				if rs.Rule(r) == nil {
+					return fmt.Errorf("no rule found with name `%s`", r)
+				}
+				ids = append(ids, depGraphNodeID(itemKindRule, r))
+			}
+			g = g.Filter(g.reachable(ids, false))
+		}
+
+		if len(reverse) > 0 {
+			var ids []string
+			for _, r := range reverse {
+				var id string
+				for _, kind := range []string{itemKindList, itemKindMacro} {
+					if g.Node(depGraphNodeID(kind, r)) != nil {
+						id = depGraphNodeID(kind, r)
+						break
+					}
+				}
+				if len(id) == 0 {
+					return fmt.Errorf("no macro or list found with name `%s`", r)
+				}
+				ids = append(ids, id)
+			}
+			g = g.Filter(g.reachable(ids, true))
+		}
+
+		switch format {
+		case "dot":
+			g.WriteDOT(cmd.OutOrStdout())
+		case "mermaid":
+			g.WriteMermaid(cmd.OutOrStdout())
+		case "json":
+			return g.WriteJSON(cmd.OutOrStdout())
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+		return nil
+	},
+}
+
+func init() {
+	graphCmd.Flags().StringP("output", "o", "dot", "Output format, one of: dot, mermaid, json")
+	graphCmd.Flags().StringArray("rule", []string{}, "Only include the dependencies of the given rules")
+	graphCmd.Flags().StringArray("reverse", []string{}, "Only include the rules, macros, and lists that depend on the given macros or lists")
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildDepGraph(t *testing.T) {
+	t.Parallel()
+	rs := testLoadSampleRuleset(t, false)
+	f, err := parseRulesFile("extra.yaml", []byte(`
+- list: unused_list
+  items: [shell_binaries]
+
+- macro: unused_macro
+  condition: proc.name in (unused_list) and undefined_macro
+`))
+	require.NoError(t, err)
+	require.NoError(t, rs.Add(f))
+
+	g, err := buildDepGraph(rs)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"macro:spawned_process", "macro:shell_procs"}, g.Dependencies("rule:Run shell"))
+	assert.ElementsMatch(t, []string{"macro:shell_procs", "list:unused_list"}, g.Dependents("list:shell_binaries"))
+	assert.False(t, g.Node("list:shell_binaries").Unused)
+	assert.True(t, g.Node("list:unused_list").Unused)
+	assert.True(t, g.Node("macro:unused_macro").Unused)
+	assert.True(t, g.Node("macro:undefined_macro").Undefined)
+
+	t.Run("filter", func(t *testing.T) {
+		t.Parallel()
+		f := g.Filter(g.reachable([]string{"list:shell_binaries"}, true))
+		assert.Len(t, f.Nodes, 5)
+		assert.Nil(t, f.Node("macro:spawned_process"))
+		f = g.Filter(g.reachable([]string{"rule:Run shell"}, false))
+		assert.Len(t, f.Nodes, 4)
+		assert.Nil(t, f.Node("list:unused_list"))
+	})
+
+	t.Run("formats", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		g.WriteDOT(&buf)
+		assert.Contains(t, buf.String(), `"rule:Run shell" -> "macro:spawned_process";`)
+		assert.Contains(t, buf.String(), `"list:unused_list" [label="unused_list", shape=note, style=filled, fillcolor=orange];`)
+
+		buf.Reset()
+		g.WriteMermaid(&buf)
+		assert.Contains(t, buf.String(), `n0["Run shell"]`)
+		assert.Contains(t, buf.String(), "n0 --> n2")
+
+		buf.Reset()
+		require.NoError(t, g.WriteJSON(&buf))
+		var out depGraph
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		assert.Len(t, out.Nodes, len(g.Nodes))
+		assert.Len(t, out.Edges, len(g.Edges))
+	})
+}
@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/falcosecurity/testing/pkg/falco"
+	"github.com/spf13/cobra"
+)
+
+// historyRelease is a release of a rules file.
+type historyRelease struct {
+	Tag  string
+	Date string
+	File *rulesFile
+}
+
+// historyChange is the change of a list, macro, or rule in a release.
+type historyChange struct {
+	Rulesfile string `json:"rulesfile"`
+	Tag       string `json:"tag"`
+	Date      string `json:"date"`
+	// Status is either added, removed, or changed
+	Status string `json:"status"`
+	// Classification is the most dominant version change that compare
+	// reports for the object, either major, minor, or patch
+	Classification string   `json:"classification,omitempty"`
+	Details        []string `json:"details,omitempty"`
+}
+
+func joinQuoted(s []string) string {
+	var res []string
+	for _, v := range s {
+		res = append(res, "`"+v+"`")
+	}
+	return strings.Join(res, ", ")
+}
+
+// rulesetEntryDiff returns the differences between two versions of a list,
+// macro, or rule.
+func rulesetEntryDiff(l, r *rulesetEntry) []string {
+	var res []string
+	changed := func(key, before, after string) {
+		if before != after {
+			res = append(res, fmt.Sprintf("%s changed from `%s` to `%s`", capitalize(key), before, after))
+		}
+	}
+	added := func(key string, before, after []string) {
+		if d := diffStrSet(after, before); len(d) > 0 {
+			res = append(res, fmt.Sprintf("%s added: %s", capitalize(key), joinQuoted(sortedSetKeys(d))))
+		}
+		if d := diffStrSet(before, after); len(d) > 0 {
+			res = append(res, fmt.Sprintf("%s removed: %s", capitalize(key), joinQuoted(sortedSetKeys(d))))
+		}
+	}
+
+	switch l.Kind {
+	case itemKindList:
+		added("items", l.Items, r.Items)
+	case itemKindMacro:
+		changed("condition", normalizeCondition(l.Condition), normalizeCondition(r.Condition))
+	case itemKindRule:
+		changed("condition", normalizeCondition(l.Condition), normalizeCondition(r.Condition))
+		changed("priority", l.Priority, r.Priority)
+		changed("source", l.RuleSource(), r.RuleSource())
+		changed("enabled", fmt.Sprint(l.IsEnabled()), fmt.Sprint(r.IsEnabled()))
+		added("tags", l.Tags, r.Tags)
+		changed("output", normalizeCondition(l.Output), normalizeCondition(r.Output))
+		if normalizeCondition(l.Desc) != normalizeCondition(r.Desc) {
+			res = append(res, "Description changed")
+		}
+
+		var lNames, rNames []string
+		for _, e := range l.Exceptions {
+			lNames = append(lNames, e.Name)
+		}
+		for _, e := range r.Exceptions {
+			rNames = append(rNames, e.Name)
+		}
+		added("exceptions", lNames, rNames)
+		for _, le := range l.Exceptions {
+			for _, re := range r.Exceptions {
+				if le.Name != re.Name {
+					continue
+				}
+				if !reflect.DeepEqual(le.Fields, re.Fields) || !reflect.DeepEqual(le.Comps, re.Comps) {
+					res = append(res, fmt.Sprintf("Exception `%s` changed its fields or comps", le.Name))
+				}
+				if !reflect.DeepEqual(le.Values, re.Values) {
+					res = append(res, fmt.Sprintf("Exception `%s` changed its values", le.Name))
+				}
+			}
+		}
+	}
+	return res
+}
+
+// compareDiffMentions returns true if a change reported by compare is about
+// the list, macro, or rule with the given name.
+func compareDiffMentions(diff, kind, name string) bool {
+	return strings.HasPrefix(diff, capitalize(kind)+" ") &&
+		(strings.Contains(diff, "`"+name+"`") || strings.Contains(diff, "'"+name+"'"))
+}
+
+// compareClassification returns the most dominant version change reported
+// by compare for an object between two rulesets, or an empty string if none.
+func compareClassification(left, right *falco.RulesetDescription, kind, name string) string {
+	for _, c := range []struct {
+		name string
+		diff []string
+	}{
+		{"major", compareRulesMajor(left, right)},
+		{"minor", compareRulesMinor(left, right)},
+		{"patch", compareRulesPatch(left, right)},
+	} {
+		for _, d := range c.diff {
+			if compareDiffMentions(d, kind, name) {
+				return c.name
+			}
+		}
+	}
+	return ""
+}
+
+// objectHistory returns the changes of a list, macro, or rule across the
+// releases of a rules file, given in order.
+func objectHistory(rulesfile, kind, name string, releases []*historyRelease) ([]*historyChange, error) {
+	var res []*historyChange
+	var prev *rulesetEntry
+	prevDesc := &falco.RulesetDescription{}
+	for _, rel := range releases {
+		rs := newRuleset()
+		if err := rs.Add(rel.File); err != nil {
+			return nil, fmt.Errorf("%s: %s", rel.Tag, err.Error())
+		}
+		desc := rulesetDescription(rs)
+		cur := rulesetEntryOf(rs, kind, name)
+		change := &historyChange{Rulesfile: rulesfile, Tag: rel.Tag, Date: rel.Date}
+		switch {
+		case prev == nil && cur != nil:
+			change.Status = "added"
+		case prev != nil && cur == nil:
+			change.Status = "removed"
+		case prev != nil:
+			change.Status = "changed"
+			change.Details = rulesetEntryDiff(prev, cur)
+		}
+		if len(change.Status) > 0 && (change.Status != "changed" || len(change.Details) > 0) {
+			change.Classification = compareClassification(prevDesc, desc, kind, name)
+			res = append(res, change)
+		}
+		prev, prevDesc = cur, desc
+	}
+	return res, nil
+}
+
+// rulesfilePathAt returns the path of a rules file of the registry at a git
+// revision, using the current registry if the revision has none. Returns
+// false if the registry has no rules file with the given name.
+func rulesfilePathAt(registryPath, name, rev string) (string, bool, error) {
+	content, ok, err := gitShowFile(rev, registryPath)
+	if err != nil {
+		return "", false, err
+	}
+	reg, err := loadRegistry(registryPath)
+	if ok {
+		reg, err = parseRegistry(registryPath, content)
+	}
+	if err != nil {
+		return "", false, err
+	}
+	rf, ok := reg.Rulesfile(name)
+	return rf.Path, ok, nil
+}
+
+// loadReleaseHistory loads the releases of a rules file of the registry from
+// its git tags, resolving its path with the registry of each release.
+func loadReleaseHistory(registryPath, name string) ([]*historyRelease, error) {
+	tags, err := gitReleaseTags(name)
+	if err != nil {
+		return nil, err
+	}
+	var res []*historyRelease
+	for _, tag := range tags {
+		path, ok, err := rulesfilePathAt(registryPath, name, tag)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		f, err := loadRulesFileAt(tag, path)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil {
+			continue
+		}
+		date, err := gitRevDate(tag)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &historyRelease{Tag: tag, Date: date, File: f})
+	}
+	return res, nil
+}
+
+func writeHistory(w io.Writer, kind, name string, changes []*historyChange) {
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "No releases found for %s `%s`\n", kind, name)
+		return
+	}
+	fmt.Fprintf(w, "# %s `%s`\n", capitalize(kind), name)
+	rulesfile := ""
+	for _, c := range changes {
+		if c.Rulesfile != rulesfile {
+			rulesfile = c.Rulesfile
+			fmt.Fprintf(w, "\n## %s\n\n", rulesfile)
+		}
+		status := c.Status
+		if len(c.Classification) > 0 {
+			status += fmt.Sprintf(" (%s)", c.Classification)
+		}
+		fmt.Fprintf(w, "* %s (%s): %s\n", c.Tag, c.Date, status)
+		for _, d := range c.Details {
+			fmt.Fprintln(w, "  * "+d)
+		}
+	}
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history <name>",
+	Short: "Report how a rule, macro, or list changed across the releases of the rules files",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kind, err := cmd.Flags().GetString("kind")
+		if err != nil {
+			return err
+		}
+
+		if kind != itemKindRule && kind != itemKindMacro && kind != itemKindList {
+			return fmt.Errorf("unsupported kind '%s'", kind)
+		}
+
+		names, err := cmd.Flags().GetStringArray("rulesfile")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			reg, err := loadRegistry(registryPath)
+			if err != nil {
+				return err
+			}
+			for _, rf := range reg.Rulesfiles {
+				names = append(names, rf.Name)
+			}
+		}
+
+		changes := []*historyChange{}
+		for _, name := range names {
+			releases, err := loadReleaseHistory(registryPath, name)
+			if err != nil {
+				return err
+			}
+			c, err := objectHistory(name, kind, args[0], releases)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, c...)
+		}
+
+		switch format {
+		case "text":
+			writeHistory(cmd.OutOrStdout(), kind, args[0], changes)
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			return enc.Encode(changes)
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().StringP("kind", "k", itemKindRule, "Kind of the object, one of: rule, macro, list")
+	historyCmd.Flags().StringArray("rulesfile", []string{}, "Names of the rules files of the registry whose releases are walked (defaults to all)")
+	historyCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	historyCmd.Flags().StringP("output", "o", "text", "Output format, one of: text, json")
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectHistory(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, `
+- list: l1
+  items: [a]
+`, `
+- list: l1
+  items: [a, b]
+- rule: r1
+  desc: rule 1
+  condition: proc.name in (l1)
+  output: out
+  priority: WARNING
+  tags: [host]
+`, `
+- list: l1
+  items: [a, b]
+- rule: r1
+  desc: rule 1
+  condition: proc.name in (l1)
+  output: out
+  priority: WARNING
+  tags: [host]
+`, `
+- list: l1
+  items: [b]
+- rule: r1
+  desc: rule 1
+  condition: proc.name in (l1) and evt.type = execve
+  output: out
+  priority: NOTICE
+  tags: [container]
+  enabled: false
+  exceptions:
+    - name: e1
+      fields: [proc.pname]
+`, `
+- list: l1
+  items: [b]
+`)
+	var releases []*historyRelease
+	for i, f := range files {
+		releases = append(releases, &historyRelease{Tag: "r-1." + string(rune('0'+i)) + ".0", File: f})
+	}
+
+	changes, err := objectHistory("r", itemKindRule, "r1", releases)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, "r-1.1.0", changes[0].Tag)
+	assert.Equal(t, "added", changes[0].Status)
+	assert.Equal(t, "minor", changes[0].Classification)
+
+	assert.Equal(t, "r-1.3.0", changes[1].Tag)
+	assert.Equal(t, "changed", changes[1].Status)
+	assert.Equal(t, "major", changes[1].Classification)
+	assert.Equal(t, []string{
+		"Condition changed from `proc.name in (l1)` to `proc.name in (l1) and evt.type = execve`",
+		"Priority changed from `WARNING` to `NOTICE`",
+		"Enabled changed from `true` to `false`",
+		"Tags added: `container`",
+		"Tags removed: `host`",
+		"Exceptions added: `e1`",
+	}, changes[1].Details)
+
+	assert.Equal(t, "r-1.4.0", changes[2].Tag)
+	assert.Equal(t, "removed", changes[2].Status)
+	assert.Equal(t, "major", changes[2].Classification)
+
+	changes, err = objectHistory("r", itemKindList, "l1", releases)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	assert.Equal(t, []string{"Items added: `b`"}, changes[1].Details)
+	assert.Equal(t, "patch", changes[1].Classification)
+	assert.Equal(t, []string{"Items removed: `a`"}, changes[2].Details)
+}
+
+func TestObjectHistoryEventTypes(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, `
+- macro: spawned_process
+  condition: evt.type in (execve, execveat)
+- rule: r1
+  desc: rule 1
+  condition: spawned_process and proc.name = sh
+  output: "%proc.name"
+  priority: WARNING
+`, `
+- macro: spawned_process
+  condition: evt.type in (execve, execveat)
+- rule: r1
+  desc: rule 1
+  condition: spawned_process and proc.name = sh
+  output: "%proc.name %proc.cmdline"
+  priority: WARNING
+`, `
+- macro: spawned_process
+  condition: evt.type in (execve, execveat)
+- rule: r1
+  desc: rule 1
+  condition: evt.type = execve and proc.name = sh
+  output: "%proc.name %proc.cmdline"
+  priority: WARNING
+`)
+	var releases []*historyRelease
+	for i, f := range files {
+		releases = append(releases, &historyRelease{Tag: "r-1." + string(rune('0'+i)) + ".0", File: f})
+	}
+
+	changes, err := objectHistory("r", itemKindRule, "r1", releases)
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	// adding output fields is a patch change
+	assert.Equal(t, "r-1.1.0", changes[1].Tag)
+	assert.Equal(t, "patch", changes[1].Classification)
+	// matching less event types is a major change
+	assert.Equal(t, "r-1.2.0", changes[2].Tag)
+	assert.Equal(t, "major", changes[2].Classification)
+}
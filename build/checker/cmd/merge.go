@@ -0,0 +1,441 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// mergeVersion is the version of a rules file object or of one of its keys in
+// one of the sides of a three-way merge.
+type mergeVersion struct {
+	Data  interface{}
+	Lines []string
+}
+
+func mergeVersionsEqual(l, r *mergeVersion) bool {
+	if l == nil || r == nil {
+		return l == r
+	}
+	return reflect.DeepEqual(l.Data, r.Data)
+}
+
+// mergeChoice resolves a three-way merge between versions, where nil means
+// that the side doesn't have the object. Returns false if both sides changed
+// the base version differently.
+func mergeChoice(base, ours, theirs *mergeVersion) (*mergeVersion, bool) {
+	switch {
+	case mergeVersionsEqual(ours, theirs), mergeVersionsEqual(base, theirs):
+		return ours, true
+	case mergeVersionsEqual(base, ours):
+		return theirs, true
+	}
+	return nil, false
+}
+
+// mergeObject is a top-level item of a rules file, along with its text.
+type mergeObject struct {
+	Key  string
+	Item *rulesFileItem
+	// Gap contains the lines separating the object from the previous one,
+	// and is empty for the first object
+	Gap []string
+	// Lines contains the lines of the object, including its comments
+	Lines []string
+	// Offset is the index of the first line of the object in the file
+	Offset int
+}
+
+func (o *mergeObject) Version() *mergeVersion {
+	if o == nil {
+		return nil
+	}
+	var data interface{}
+	o.Item.Node.Decode(&data)
+	return &mergeVersion{Data: data, Lines: o.Lines}
+}
+
+// Describe returns a human readable description of the object.
+func (o *mergeObject) Describe() string {
+	if len(o.Item.Name()) == 0 {
+		return "`" + o.Item.Kind + "`"
+	}
+	return fmt.Sprintf("%s `%s`", capitalize(o.Item.Kind), o.Item.Name())
+}
+
+// Keys returns the versions of the top-level keys of the object, in order.
+// The comments preceding the object are considered as a key named `#`.
+func (o *mergeObject) Keys() ([]string, map[string]*mergeVersion) {
+	res := make(map[string]*mergeVersion)
+	var names []string
+	n := o.Item.Node
+	first := o.Item.Loc.Line - 1 - o.Offset
+	if first > 0 {
+		names = append(names, "#")
+		res["#"] = &mergeVersion{Data: strings.Join(o.Lines[:first], "\n"), Lines: o.Lines[:first]}
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		start := n.Content[i].Line - 1 - o.Offset
+		end := len(o.Lines)
+		if i+3 < len(n.Content) {
+			end = n.Content[i+2].Line - 1 - o.Offset
+		}
+		lines := append([]string{}, o.Lines[start:end]...)
+		if start == first {
+			// the dash of the sequence entry is restored when joining the keys
+			col := n.Content[i].Column - 1
+			lines[0] = strings.Repeat(" ", col) + lines[0][col:]
+		}
+		var data interface{}
+		n.Content[i+1].Decode(&data)
+		name := n.Content[i].Value
+		names = append(names, name)
+		res[name] = &mergeVersion{Data: data, Lines: lines}
+	}
+	return names, res
+}
+
+// mergeObjects splits a rules file into its objects, and returns them along
+// with the lines preceding the first one and following the last one.
+func mergeObjects(f *rulesFile) ([]*mergeObject, []string, []string) {
+	if len(f.Items) == 0 {
+		return nil, f.Lines, nil
+	}
+	spans := rulesFileItemSpans(f)
+	var res []*mergeObject
+	counts := make(map[string]int)
+	for i, item := range f.Items {
+		key := item.Kind
+		if len(item.Name()) > 0 {
+			key = depGraphNodeID(item.Kind, item.Name())
+		}
+		counts[key]++
+		if counts[key] > 1 {
+			key = fmt.Sprintf("%s#%d", key, counts[key])
+		}
+		o := &mergeObject{
+			Key:    key,
+			Item:   item,
+			Lines:  f.Lines[spans[i][0] : spans[i][1]+1],
+			Offset: spans[i][0],
+		}
+		if i > 0 {
+			o.Gap = f.Lines[spans[i-1][1]+1 : spans[i][0]]
+		} else {
+			o.Gap = []string{}
+		}
+		res = append(res, o)
+	}
+	return res, f.Lines[:spans[0][0]], f.Lines[spans[len(spans)-1][1]+1:]
+}
+
+// mergeConflict is an object of a rules file that has been changed
+// differently on both sides of a merge.
+type mergeConflict struct {
+	Object string `json:"object"`
+	Reason string `json:"reason"`
+	// Keys are the conflicting keys of objects changed on both sides
+	Keys []string `json:"keys,omitempty"`
+}
+
+// mergeReport summarizes the result of a three-way merge of rules files.
+type mergeReport struct {
+	// Theirs contains the changes taken from theirs
+	Theirs []string `json:"theirs"`
+	// Merged contains the objects changed on both sides that have been merged
+	// key by key
+	Merged    []string         `json:"merged"`
+	Conflicts []*mergeConflict `json:"conflicts"`
+}
+
+// mergeRulesFiles merges the changes between a base rules file and theirs
+// into ours, at the granularity of lists, macros, and rules. Objects changed
+// on both sides are merged key by key, and conflicting ones are written with
+// conflict markers labeled with the paths of the rules files.
+func mergeRulesFiles(base, ours, theirs *rulesFile) ([]byte, *mergeReport) {
+	baseObjs, _, _ := mergeObjects(base)
+	oursObjs, preamble, footer := mergeObjects(ours)
+	theirsObjs, _, _ := mergeObjects(theirs)
+	index := func(objs []*mergeObject) map[string]*mergeObject {
+		res := make(map[string]*mergeObject)
+		for _, o := range objs {
+			res[o.Key] = o
+		}
+		return res
+	}
+	baseIdx, oursIdx, theirsIdx := index(baseObjs), index(oursObjs), index(theirsObjs)
+
+	// objects of ours in order, with the ones added by theirs after the
+	// object preceding them in theirs
+	var order []string
+	for _, o := range oursObjs {
+		order = append(order, o.Key)
+	}
+	last := -1
+	for _, o := range theirsObjs {
+		if _, ok := oursIdx[o.Key]; ok {
+			for i, k := range order {
+				if k == o.Key {
+					last = i
+				}
+			}
+			continue
+		}
+		order = append(order[:last+1], append([]string{o.Key}, order[last+1:]...)...)
+		last++
+	}
+
+	report := &mergeReport{}
+	out := append([]string{}, preamble...)
+	for _, key := range order {
+		b, o, t := baseIdx[key], oursIdx[key], theirsIdx[key]
+		desc := o
+		if desc == nil {
+			desc = t
+		}
+		gap := []string{""}
+		if len(out) == 0 {
+			gap = nil
+		}
+		if o != nil && o.Gap != nil {
+			gap = o.Gap
+		} else if o == nil && len(t.Gap) > 0 {
+			gap = t.Gap
+		}
+
+		res, ok := mergeChoice(b.Version(), o.Version(), t.Version())
+		if ok {
+			if res != nil {
+				out = append(append(out, gap...), res.Lines...)
+			}
+			if !mergeVersionsEqual(res, o.Version()) {
+				switch {
+				case o == nil:
+					report.Theirs = append(report.Theirs, desc.Describe()+" has been added")
+				case res == nil:
+					report.Theirs = append(report.Theirs, desc.Describe()+" has been removed")
+				default:
+					report.Theirs = append(report.Theirs, desc.Describe()+" has been changed")
+				}
+			}
+			continue
+		}
+
+		conflict := &mergeConflict{Object: desc.Describe(), Reason: "changed on both sides"}
+		switch {
+		case o == nil:
+			conflict.Reason = "removed by ours and changed by theirs"
+		case t == nil:
+			conflict.Reason = "changed by ours and removed by theirs"
+		case b == nil:
+			conflict.Reason = "added differently on both sides"
+		}
+		if o != nil && t != nil {
+			lines, keys := mergeObjectKeys(b, o, t)
+			if len(keys) == 0 {
+				out = append(append(out, gap...), lines...)
+				report.Merged = append(report.Merged, desc.Describe())
+				continue
+			}
+			conflict.Keys = keys
+		}
+		report.Conflicts = append(report.Conflicts, conflict)
+		out = append(out, gap...)
+		out = append(out, "<<<<<<< "+ours.Path)
+		out = append(out, o.Version().linesOrNil()...)
+		out = append(out, "||||||| "+base.Path)
+		out = append(out, b.Version().linesOrNil()...)
+		out = append(out, "=======")
+		out = append(out, t.Version().linesOrNil()...)
+		out = append(out, ">>>>>>> "+theirs.Path)
+	}
+	out = append(out, footer...)
+	if len(footer) == 0 {
+		out = append(out, "")
+	}
+	return []byte(strings.Join(out, "\n")), report
+}
+
+func (v *mergeVersion) linesOrNil() []string {
+	if v == nil {
+		return nil
+	}
+	return v.Lines
+}
+
+// mergeObjectKeys merges an object changed on both sides key by key, and
+// returns the merged lines, or the conflicting keys if any.
+func mergeObjectKeys(base, ours, theirs *mergeObject) ([]string, []string) {
+	var baseKeys map[string]*mergeVersion
+	if base != nil {
+		_, baseKeys = base.Keys()
+	}
+	oursOrder, oursKeys := ours.Keys()
+	theirsOrder, theirsKeys := theirs.Keys()
+	order := oursOrder
+	for _, k := range theirsOrder {
+		if !strSliceContains(order, k) {
+			order = append(order, k)
+		}
+	}
+
+	var res, conflicts []string
+	for _, k := range order {
+		v, ok := mergeChoice(baseKeys[k], oursKeys[k], theirsKeys[k])
+		if !ok {
+			conflicts = append(conflicts, k)
+		} else if v != nil {
+			res = append(res, v.Lines...)
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, conflicts
+	}
+
+	// restoring the dash of the sequence entry on the first key
+	dash := ours.Item.Node.Content[0].Column - 1
+	for i, l := range res {
+		if !strings.HasPrefix(strings.TrimSpace(l), "#") {
+			res[i] = ours.Lines[ours.Item.Loc.Line-1-ours.Offset][:dash] + l[dash:]
+			break
+		}
+	}
+	return res, nil
+}
+
+func writeMergeReport(w io.Writer, report *mergeReport) {
+	if len(report.Theirs) > 0 {
+		fmt.Fprintln(w, "Changes from theirs:")
+		for _, s := range report.Theirs {
+			fmt.Fprintln(w, "* "+s)
+		}
+		fmt.Fprintln(w)
+	}
+	if len(report.Merged) > 0 {
+		fmt.Fprintln(w, "Merged key by key:")
+		for _, s := range report.Merged {
+			fmt.Fprintln(w, "* "+s)
+		}
+		fmt.Fprintln(w)
+	}
+	if len(report.Conflicts) > 0 {
+		fmt.Fprintln(w, "**Conflicts**:")
+		for _, c := range report.Conflicts {
+			if len(c.Keys) > 0 {
+				fmt.Fprintf(w, "* %s %s (keys: %s)\n", c.Object, c.Reason, strings.Join(c.Keys, ", "))
+			} else {
+				fmt.Fprintf(w, "* %s %s\n", c.Object, c.Reason)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+	if len(report.Theirs)+len(report.Merged)+len(report.Conflicts) == 0 {
+		fmt.Fprintln(w, "No changes from theirs")
+	}
+}
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge",
+	Short: "Three-way merge of rules files at the granularity of lists, macros, and rules",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		basePath, err := cmd.Flags().GetString("base")
+		if err != nil {
+			return err
+		}
+
+		oursPath, err := cmd.Flags().GetString("ours")
+		if err != nil {
+			return err
+		}
+
+		theirsPath, err := cmd.Flags().GetString("theirs")
+		if err != nil {
+			return err
+		}
+
+		if len(basePath) == 0 || len(oursPath) == 0 || len(theirsPath) == 0 {
+			return fmt.Errorf("you must specify the base, ours, and theirs rules files")
+		}
+
+		writePath, err := cmd.Flags().GetString("write")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		var files []*rulesFile
+		for _, path := range []string{basePath, oursPath, theirsPath} {
+			f, err := loadRulesFile(path)
+			if err != nil {
+				return err
+			}
+			files = append(files, f)
+		}
+
+		content, report := mergeRulesFiles(files[0], files[1], files[2])
+
+		// the report goes to stderr if the merged content is printed
+		w := cmd.OutOrStdout()
+		if len(writePath) > 0 {
+			if err := os.WriteFile(writePath, content, 0644); err != nil {
+				return err
+			}
+		} else {
+			cmd.OutOrStdout().Write(content)
+			w = cmd.ErrOrStderr()
+		}
+
+		switch format {
+		case "text":
+			writeMergeReport(w, report)
+		case "json":
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+
+		if len(report.Conflicts) > 0 {
+			return fmt.Errorf("merge has %d conflicts", len(report.Conflicts))
+		}
+		return nil
+	},
+}
+
+func init() {
+	mergeCmd.Flags().String("base", "", "Rules file both sides derive from, such as the previous upstream release")
+	mergeCmd.Flags().String("ours", "", "Rules file with the local changes, such as a fork of the previous upstream release")
+	mergeCmd.Flags().String("theirs", "", "Rules file with the changes to be merged, such as the new upstream release")
+	mergeCmd.Flags().StringP("write", "w", "", "File to write the merged rules file to, instead of printing it")
+	mergeCmd.Flags().StringP("output", "o", "text", "Output format of the merge report, one of: text, json")
+	rootCmd.AddCommand(mergeCmd)
+}
@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeRulesFiles(t *testing.T) {
+	t.Parallel()
+
+	base := `# header
+
+- list: l1
+  items: [a, b]
+
+- macro: m1
+  condition: evt.type = open
+
+# rule r1
+- rule: r1
+  desc: rule 1
+  condition: m1
+  output: out
+  priority: WARNING
+
+- rule: r2
+  desc: rule 2
+  condition: m1
+  output: out
+  priority: WARNING
+`
+
+	t.Run("identity", func(t *testing.T) {
+		t.Parallel()
+		files := testParseRulesFiles(t, base, base, base)
+		content, report := mergeRulesFiles(files[0], files[1], files[2])
+		assert.Equal(t, base, string(content))
+		assert.Empty(t, report.Theirs)
+		assert.Empty(t, report.Conflicts)
+	})
+
+	t.Run("auto-merge", func(t *testing.T) {
+		t.Parallel()
+		files := testParseRulesFiles(t, base, `# header
+
+- list: l1
+  items: [a, b, c]
+
+- macro: m1
+  condition: evt.type = open
+
+# rule r1
+- rule: r1
+  desc: rule 1
+  condition: m1
+  output: out
+  priority: WARNING
+  tags: [mine]
+
+- rule: r2
+  desc: rule 2
+  condition: m1
+  output: out
+  priority: WARNING
+`, `# header
+
+- list: l1
+  items: [a, b]
+
+- macro: m1
+  condition: evt.type = open
+
+- macro: m2
+  condition: evt.type = close
+
+# rule r1
+- rule: r1
+  desc: rule 1
+  condition: m1 and m2
+  output: out
+  priority: WARNING
+`)
+		content, report := mergeRulesFiles(files[0], files[1], files[2])
+		assert.Equal(t, `# header
+
+- list: l1
+  items: [a, b, c]
+
+- macro: m1
+  condition: evt.type = open
+
+- macro: m2
+  condition: evt.type = close
+
+# rule r1
+- rule: r1
+  desc: rule 1
+  condition: m1 and m2
+  output: out
+  priority: WARNING
+  tags: [mine]
+`, string(content))
+		assert.Equal(t, []string{"Macro `m2` has been added", "Rule `r2` has been removed"}, report.Theirs)
+		assert.Equal(t, []string{"Rule `r1`"}, report.Merged)
+		assert.Empty(t, report.Conflicts)
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		t.Parallel()
+		files := testParseRulesFiles(t, base, `# header
+
+- list: l1
+  items: [a, b, c]
+
+- macro: m1
+  condition: evt.type = open
+
+# rule r1
+- rule: r1
+  desc: rule 1
+  condition: m1
+  output: out
+  priority: WARNING
+
+- rule: r2
+  desc: rule 2
+  condition: m1
+  output: out
+  priority: ERROR
+`, `# header
+
+- list: l1
+  items: [a, b, d]
+
+- macro: m1
+  condition: evt.type = open
+
+# rule r1
+- rule: r1
+  desc: rule 1
+  condition: m1
+  output: out
+  priority: WARNING
+`)
+		content, report := mergeRulesFiles(files[0], files[1], files[2])
+		assert.Equal(t, `# header
+
+<<<<<<< b.yaml
+- list: l1
+  items: [a, b, c]
+||||||| a.yaml
+- list: l1
+  items: [a, b]
+=======
+- list: l1
+  items: [a, b, d]
+>>>>>>> c.yaml
+
+- macro: m1
+  condition: evt.type = open
+
+# rule r1
+- rule: r1
+  desc: rule 1
+  condition: m1
+  output: out
+  priority: WARNING
+
+<<<<<<< b.yaml
+- rule: r2
+  desc: rule 2
+  condition: m1
+  output: out
+  priority: ERROR
+||||||| a.yaml
+- rule: r2
+  desc: rule 2
+  condition: m1
+  output: out
+  priority: WARNING
+=======
+>>>>>>> c.yaml
+`, string(content))
+		require.Len(t, report.Conflicts, 2)
+		assert.Equal(t, &mergeConflict{Object: "List `l1`", Reason: "changed on both sides", Keys: []string{"items"}}, report.Conflicts[0])
+		assert.Equal(t, &mergeConflict{Object: "Rule `r2`", Reason: "changed by ours and removed by theirs"}, report.Conflicts[1])
+	})
+}
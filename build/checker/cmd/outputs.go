@@ -0,0 +1,295 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFieldsProfile lists the fields that the output of a rule must
+// reference, and the fields it can reference. Known fields ending with a dot
+// are prefixes matching all the fields of a class.
+type outputFieldsProfile struct {
+	Required []string `yaml:"required"`
+	Known    []string `yaml:"known"`
+}
+
+// outputProfile contains the output fields profiles of the rules of each
+// event source, and of the rules having each tag. A rule must satisfy the
+// profile of its source and the ones of all its tags, such as a `container`
+// tag profile requiring `container.id`.
+type outputProfile struct {
+	Sources map[string]outputFieldsProfile `yaml:"sources"`
+	Tags    map[string]outputFieldsProfile `yaml:"tags"`
+}
+
+// defaultOutputProfile follows the context fields that the rules of the
+// Falco ecosystem include in their outputs. The fields provided by plugins
+// are known from the plugins table.
+var defaultOutputProfile = outputProfile{
+	Sources: map[string]outputFieldsProfile{
+		defaultRuleSource: {
+			Required: []string{
+				"evt.type",
+				"user.name",
+				"user.uid",
+				"user.loginuid",
+				"proc.name",
+				"proc.exepath",
+				"proc.pname",
+				"proc.cmdline",
+				"proc.tty",
+			},
+			Known: []string{"evt.", "proc.", "thread.", "user.", "group.", "fd.", "fdlist.", "fs.", "syslog.", "span."},
+		},
+	},
+}
+
+func loadOutputProfile(path string) (*outputProfile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res outputProfile
+	if err := yaml.Unmarshal(content, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// outputMissingField is a field required by a profile that is not referenced
+// by the output of a rule.
+type outputMissingField struct {
+	Field string `json:"field"`
+	// RequiredBy describes the profile requiring the field, such as
+	// "source `syscall`" or "tag `container`"
+	RequiredBy string `json:"required_by"`
+}
+
+// ruleOutputReport reports the issues of the fields referenced by the
+// output of a rule.
+type ruleOutputReport struct {
+	Rule       string               `json:"rule"`
+	Location   string               `json:"location"`
+	Missing    []outputMissingField `json:"missing"`
+	Unknown    []string             `json:"unknown"`
+	Duplicated []string             `json:"duplicated"`
+}
+
+// knownOutputFields returns the known fields and field prefixes of the
+// rules of an event source and with the given tags, or nil if no profile
+// nor plugin declares any field for them.
+func knownOutputFields(profile *outputProfile, plugins []pluginInfo, source string, tags []string) []string {
+	var res []string
+	res = append(res, profile.Sources[source].Known...)
+	for _, t := range tags {
+		res = append(res, profile.Tags[t].Known...)
+	}
+	for _, p := range plugins {
+		if strSliceContains(p.Sources, source) || strSliceContains(p.ExtractSources, source) {
+			res = append(res, p.Fields...)
+		}
+	}
+	return res
+}
+
+// isKnownField returns true if a field is in a list of known fields and
+// field prefixes.
+func isKnownField(known []string, field string) bool {
+	for _, k := range known {
+		if k == field || (strings.HasSuffix(k, ".") && strings.HasPrefix(field, k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeRuleOutput checks the fields referenced by the output of a rule
+// against the profiles of its source and tags, and returns nil if there are
+// no issues.
+func analyzeRuleOutput(r *rulesetEntry, profile *outputProfile, plugins []pluginInfo) *ruleOutputReport {
+	rep := &ruleOutputReport{Rule: r.Name(), Location: r.Loc.String()}
+	known := knownOutputFields(profile, plugins, r.RuleSource(), r.Tags)
+	counts := make(map[string]int)
+	for _, f := range outputFields(r.Output) {
+		counts[f.String()]++
+		if counts[f.String()] == 2 {
+			rep.Duplicated = append(rep.Duplicated, f.String())
+		}
+		if counts[f.String()] > 1 {
+			continue
+		}
+		if len(known) > 0 && !isKnownField(known, f.Name) {
+			rep.Unknown = append(rep.Unknown, f.String())
+		}
+	}
+
+	require := func(fields []string, by string) {
+		for _, f := range fields {
+			if counts[f] == 0 {
+				rep.Missing = append(rep.Missing, outputMissingField{Field: f, RequiredBy: by})
+				// avoid reporting the same field for more than one profile
+				counts[f] = -1
+			}
+		}
+	}
+	require(profile.Sources[r.RuleSource()].Required, fmt.Sprintf("source `%s`", r.RuleSource()))
+	tags := append([]string{}, r.Tags...)
+	sort.Strings(tags)
+	for _, t := range tags {
+		require(profile.Tags[t].Required, fmt.Sprintf("tag `%s`", t))
+	}
+
+	if len(rep.Missing) == 0 && len(rep.Unknown) == 0 && len(rep.Duplicated) == 0 {
+		return nil
+	}
+	return rep
+}
+
+// analyzeRulesOutputs returns the reports of the rules of a ruleset whose
+// outputs have issues.
+func analyzeRulesOutputs(rs *ruleset, profile *outputProfile, plugins []pluginInfo) []*ruleOutputReport {
+	var res []*ruleOutputReport
+	for _, r := range rs.Rules {
+		if rep := analyzeRuleOutput(r, profile, plugins); rep != nil {
+			res = append(res, rep)
+		}
+	}
+	return res
+}
+
+func writeRulesOutputReports(w io.Writer, reports []*ruleOutputReport) {
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "No issues detected")
+		return
+	}
+	for _, rep := range reports {
+		fmt.Fprintf(w, "## %s (%s)\n\n", rep.Rule, rep.Location)
+		for _, m := range rep.Missing {
+			fmt.Fprintf(w, "* Missing field `%%%s`, required by %s\n", m.Field, m.RequiredBy)
+		}
+		for _, f := range rep.Unknown {
+			fmt.Fprintf(w, "* Unknown field `%%%s`\n", f)
+		}
+		for _, f := range rep.Duplicated {
+			fmt.Fprintf(w, "* Field `%%%s` is referenced more than once\n", f)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+var outputsCmd = &cobra.Command{
+	Use:   "outputs",
+	Short: "Lint the fields referenced by rule outputs against a profile of required and known fields per source and tag",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		profilePath, err := cmd.Flags().GetString("profile")
+		if err != nil {
+			return err
+		}
+
+		tablePath, err := cmd.Flags().GetString("plugins-table")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		profile := &defaultOutputProfile
+		if len(profilePath) > 0 {
+			profile, err = loadOutputProfile(profilePath)
+			if err != nil {
+				return err
+			}
+		}
+
+		plugins := defaultPluginsTable
+		if len(tablePath) > 0 {
+			plugins, err = loadPluginsTable(tablePath)
+			if err != nil {
+				return err
+			}
+		}
+
+		rs, err := loadRuleset(rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+
+		reports := analyzeRulesOutputs(rs, profile, plugins)
+		switch format {
+		case "text":
+			writeRulesOutputReports(cmd.OutOrStdout(), reports)
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(reports); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+
+		if strict {
+			for _, rep := range reports {
+				err = errAppend(err, fmt.Errorf("rule `%s` has output issues", rep.Rule))
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	outputsCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	outputsCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	outputsCmd.Flags().String("profile", "", "YAML file listing the required and known output fields per source and tag (uses the bundled profile if empty)")
+	outputsCmd.Flags().String("plugins-table", "", "YAML file listing the name, fields, and event sources of each plugin (uses the bundled table if empty)")
+	outputsCmd.Flags().StringP("output", "o", "text", "Output format, one of: text, json")
+	outputsCmd.Flags().Bool("strict", false, "Fail if any rule output has issues")
+	rootCmd.AddCommand(outputsCmd)
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeRulesOutputs(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- rule: complete
+  desc: test
+  condition: evt.type = execve
+  output: ok (evt_type=%evt.type user=%user.name proc=%proc.name container=%container.id)
+  priority: NOTICE
+  tags: [container]
+
+- rule: incomplete
+  desc: test
+  condition: evt.type = execve
+  output: ko (evt_type=%evt.type proc=%proc.name again=%proc.name foo=%foo.bar gparent=%proc.aname[2] parent=%proc.aname[2])
+  priority: NOTICE
+  tags: [container, process]
+
+- rule: audit
+  desc: test
+  condition: ka.verb = create
+  output: audit (verb=%ka.verb name=%jevt.value[/name] unknown=%proc.name)
+  priority: INFO
+  source: k8s_audit
+
+- rule: custom
+  desc: test
+  condition: custom.field = 1
+  output: custom (field=%custom.field)
+  priority: INFO
+  source: custom
+`)
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+
+	profile := &outputProfile{
+		Sources: map[string]outputFieldsProfile{
+			defaultRuleSource: {
+				Required: []string{"evt.type", "user.name", "proc.name"},
+				Known:    []string{"evt.", "proc.", "user."},
+			},
+		},
+		Tags: map[string]outputFieldsProfile{
+			"container": {Required: []string{"container.id", "user.name"}},
+		},
+	}
+	reports := analyzeRulesOutputs(rs, profile, defaultPluginsTable)
+	require.Len(t, reports, 2)
+
+	assert.Equal(t, "incomplete", reports[0].Rule)
+	assert.Equal(t, []outputMissingField{
+		{Field: "user.name", RequiredBy: "source `syscall`"},
+		{Field: "container.id", RequiredBy: "tag `container`"},
+	}, reports[0].Missing)
+	assert.Equal(t, []string{"foo.bar"}, reports[0].Unknown)
+	assert.Equal(t, []string{"proc.name", "proc.aname[2]"}, reports[0].Duplicated)
+
+	assert.Equal(t, "audit", reports[1].Rule)
+	assert.Empty(t, reports[1].Missing)
+	assert.Equal(t, []string{"proc.name"}, reports[1].Unknown)
+
+	var buf bytes.Buffer
+	writeRulesOutputReports(&buf, reports[:1])
+	assert.Equal(t, "## incomplete (a.yaml:9)\n\n"+
+		"* Missing field `%user.name`, required by source `syscall`\n"+
+		"* Missing field `%container.id`, required by tag `container`\n"+
+		"* Unknown field `%foo.bar`\n"+
+		"* Field `%proc.name` is referenced more than once\n"+
+		"* Field `%proc.aname[2]` is referenced more than once\n\n", buf.String())
+
+	buf.Reset()
+	writeRulesOutputReports(&buf, nil)
+	assert.Equal(t, "No issues detected\n", buf.String())
+}
+
+func TestLoadOutputProfile(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "profile.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+sources:
+  syscall:
+    required: [evt.type]
+    known: [evt.]
+tags:
+  container:
+    required: [container.id]
+`), 0644))
+	p, err := loadOutputProfile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"evt.type"}, p.Sources["syscall"].Required)
+	assert.Equal(t, []string{"evt."}, p.Sources["syscall"].Known)
+	assert.Equal(t, []string{"container.id"}, p.Tags["container"].Required)
+}
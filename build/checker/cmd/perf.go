@@ -0,0 +1,421 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// costs of the condition operators relative to a simple comparison
+var condOperatorCosts = map[string]int{
+	"contains":    2,
+	"icontains":   2,
+	"bcontains":   2,
+	"startswith":  2,
+	"bstartswith": 2,
+	"endswith":    2,
+	"pmatch":      3,
+	"glob":        5,
+	"iglob":       5,
+	"regex":       10,
+}
+
+// condExpensiveOperators are the operators that should be evaluated after
+// the cheaper checks of the same condition
+var condExpensiveOperators = map[string]bool{
+	"glob":  true,
+	"iglob": true,
+	"regex": true,
+}
+
+// condAncestryFields are the fields walking the ancestors of a process,
+// whose cost grows with the number of ancestors that are looked up
+var condAncestryFields = map[string]bool{
+	"proc.aname":    true,
+	"proc.apid":     true,
+	"proc.aexe":     true,
+	"proc.aexepath": true,
+	"proc.acmdline": true,
+}
+
+// unboundedAncestryDepth is the number of ancestors assumed for ancestry
+// fields used without an index, which walk all the ancestors of a process
+const unboundedAncestryDepth = 10
+
+// allEventTypesCostFactor multiplies the cost of the rules that match all
+// event types, since their condition is evaluated for every event
+const allEventTypesCostFactor = 10
+
+// perfOptions are the thresholds of the performance analysis.
+type perfOptions struct {
+	MaxListSize       int
+	MaxAncestryDepth  int
+	IgnoreEventTypes  bool
+	IncludeNoIssues   bool
+	SkipDisabledRules bool
+}
+
+// rulePerfReport contains the performance issues of a rule and the estimated
+// relative cost of evaluating its condition.
+type rulePerfReport struct {
+	Rule     string   `json:"rule"`
+	Location string   `json:"location"`
+	Cost     int      `json:"cost"`
+	Issues   []string `json:"issues"`
+}
+
+// ancestryDepth returns the number of ancestors looked up by a field, or zero
+// if the field does not walk the ancestors of a process.
+func ancestryDepth(f condField) int {
+	if !condAncestryFields[f.Name] {
+		return 0
+	}
+	if n, err := strconv.Atoi(f.Arg); err == nil && n > 0 {
+		return n
+	}
+	return unboundedAncestryDepth
+}
+
+// checkCost returns the estimated relative cost of a field comparison.
+func checkCost(c *condCheckExpr) int {
+	cost := 1
+	if v, ok := condOperatorCosts[c.Op]; ok {
+		cost = v
+	}
+	if d := ancestryDepth(c.Field); d > 0 {
+		cost *= d
+	}
+	if len(c.Field.Transformer) > 0 {
+		cost++
+	}
+	return cost
+}
+
+// conditionCost returns the estimated relative cost of a condition, as the
+// sum of the cost of all its field comparisons.
+func conditionCost(e condExpr) int {
+	res := 0
+	for _, c := range conditionChecks(e) {
+		res += checkCost(c)
+	}
+	return res
+}
+
+// isEvtTypeCheck returns true if a field comparison filters the event type.
+func isEvtTypeCheck(c *condCheckExpr) bool {
+	return c.Field.Name == "evt.type" && len(c.Field.Transformer) == 0
+}
+
+// isNegatedEvtTypeCheck returns true if a field comparison matches all the
+// event types except the ones it lists, considering whether it is evaluated
+// within an odd number of `not` operators.
+func isNegatedEvtTypeCheck(c *condCheckExpr, negated bool) bool {
+	switch c.Op {
+	case "=", "==", "in":
+		return negated
+	case "!=":
+		return !negated
+	}
+	return false
+}
+
+// restrictsEventTypes returns true if a condition can only match a subset
+// of the event types, similarly to how the Falco engine infers the event
+// types of a rule from its condition.
+func restrictsEventTypes(e condExpr, negated bool) bool {
+	switch v := e.(type) {
+	case *condAndExpr, *condOrExpr:
+		exprs, isAnd := flattenCondition(v)
+		// a negated or behaves like an and, and vice versa
+		if isAnd != negated {
+			for _, c := range exprs {
+				if restrictsEventTypes(c, negated) {
+					return true
+				}
+			}
+			return false
+		}
+		for _, c := range exprs {
+			if !restrictsEventTypes(c, negated) {
+				return false
+			}
+		}
+		return true
+	case *condNotExpr:
+		return restrictsEventTypes(v.Expr, !negated)
+	case *condCheckExpr:
+		switch v.Op {
+		case "=", "==", "in", "!=":
+			return isEvtTypeCheck(v) && !isNegatedEvtTypeCheck(v, negated)
+		}
+	}
+	return false
+}
+
+// flattenCondition returns the operands of an and or or expression, merging
+// the ones of nested expressions of the same kind, and whether the expression
+// is an and.
+func flattenCondition(e condExpr) ([]condExpr, bool) {
+	var res []condExpr
+	switch v := e.(type) {
+	case *condAndExpr:
+		for _, c := range v.Exprs {
+			if nested, ok := c.(*condAndExpr); ok {
+				exprs, _ := flattenCondition(nested)
+				res = append(res, exprs...)
+			} else {
+				res = append(res, c)
+			}
+		}
+		return res, true
+	case *condOrExpr:
+		for _, c := range v.Exprs {
+			if nested, ok := c.(*condOrExpr); ok {
+				exprs, _ := flattenCondition(nested)
+				res = append(res, exprs...)
+			} else {
+				res = append(res, c)
+			}
+		}
+		return res, false
+	}
+	return []condExpr{e}, false
+}
+
+// expensiveCheck returns the first comparison of a condition using an
+// expensive operator, or nil if there is none.
+func expensiveCheck(e condExpr) *condCheckExpr {
+	for _, c := range conditionChecks(e) {
+		if condExpensiveOperators[c.Op] {
+			return c
+		}
+	}
+	return nil
+}
+
+// conditionPerfIssues returns the performance issues of a condition with all
+// its macros and lists inlined.
+func conditionPerfIssues(e condExpr, opts perfOptions) []string {
+	var res []string
+	add := func(issue string) {
+		if !strSliceContains(res, issue) {
+			res = append(res, issue)
+		}
+	}
+	if !opts.IgnoreEventTypes && !restrictsEventTypes(e, false) {
+		add("Condition matches all event types")
+	}
+
+	var visit func(e condExpr, negated bool)
+	visit = func(e condExpr, negated bool) {
+		switch v := e.(type) {
+		case *condNotExpr:
+			visit(v.Expr, !negated)
+		case *condAndExpr, *condOrExpr:
+			exprs, isAnd := flattenCondition(v)
+			if isAnd {
+				for i, c := range exprs {
+					exp := expensiveCheck(c)
+					if exp == nil {
+						continue
+					}
+					for _, next := range exprs[i+1:] {
+						if check, ok := next.(*condCheckExpr); ok && checkCost(check) == 1 {
+							add(fmt.Sprintf("Expensive operator `%s` in `%s` is evaluated before the cheaper check `%s`", exp.Op, exp.String(), check.String()))
+							break
+						}
+					}
+					break
+				}
+			}
+			for _, c := range exprs {
+				visit(c, negated)
+			}
+		case *condCheckExpr:
+			if !opts.IgnoreEventTypes && isEvtTypeCheck(v) && isNegatedEvtTypeCheck(v, negated) {
+				s := v.String()
+				if negated {
+					s = "not " + s
+				}
+				add(fmt.Sprintf("Negated event type filter `%s` matches all the other event types", s))
+			}
+			if d := ancestryDepth(v.Field); d > opts.MaxAncestryDepth {
+				if len(v.Field.Arg) == 0 {
+					add(fmt.Sprintf("Ancestry field `%s` walks all the ancestors of the process", v.Field.String()))
+				} else {
+					add(fmt.Sprintf("Ancestry field `%s` looks up %d ancestors of the process", v.Field.String(), d))
+				}
+			}
+			if condListOperators[v.Op] && len(v.Values) > opts.MaxListSize {
+				add(fmt.Sprintf("Operator `%s` on `%s` compares against %d values", v.Op, v.Field.String(), len(v.Values)))
+			}
+		}
+	}
+	visit(e, false)
+	return res
+}
+
+// analyzeRulesPerf returns the performance reports of the rules of a
+// ruleset, sorted by decreasing estimated cost.
+func analyzeRulesPerf(rs *ruleset, opts perfOptions) ([]*rulePerfReport, error) {
+	var res []*rulePerfReport
+	for _, r := range rs.Rules {
+		if opts.SkipDisabledRules && !r.IsEnabled() {
+			continue
+		}
+		c, err := rs.ExpandCondition(r)
+		if err != nil {
+			return nil, err
+		}
+		ruleOpts := opts
+		ruleOpts.IgnoreEventTypes = opts.IgnoreEventTypes || r.RuleSource() != defaultRuleSource
+		rep := &rulePerfReport{
+			Rule:     r.Name(),
+			Location: r.Loc.String(),
+			Cost:     conditionCost(c),
+			Issues:   conditionPerfIssues(c, ruleOpts),
+		}
+		if !ruleOpts.IgnoreEventTypes && !restrictsEventTypes(c, false) {
+			rep.Cost *= allEventTypesCostFactor
+		}
+		if len(rep.Issues) == 0 && !opts.IncludeNoIssues {
+			continue
+		}
+		res = append(res, rep)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Cost > res[j].Cost })
+	return res, nil
+}
+
+func writeRulesPerfReports(w io.Writer, reports []*rulePerfReport) {
+	if len(reports) == 0 {
+		fmt.Fprintln(w, "No issues detected")
+		return
+	}
+	for _, rep := range reports {
+		fmt.Fprintf(w, "## %s (%s)\n\n", rep.Rule, rep.Location)
+		fmt.Fprintf(w, "Estimated relative cost: %d\n", rep.Cost)
+		for _, i := range rep.Issues {
+			fmt.Fprintln(w, "* "+i)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+var perfCmd = &cobra.Command{
+	Use:   "perf",
+	Short: "Statically analyze rule conditions for performance pitfalls and estimate their relative cost",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		var opts perfOptions
+		opts.MaxListSize, err = cmd.Flags().GetInt("max-list-size")
+		if err != nil {
+			return err
+		}
+
+		opts.MaxAncestryDepth, err = cmd.Flags().GetInt("max-ancestry-depth")
+		if err != nil {
+			return err
+		}
+
+		opts.IncludeNoIssues, err = cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+
+		skipDisabled, err := cmd.Flags().GetBool("skip-disabled")
+		if err != nil {
+			return err
+		}
+		opts.SkipDisabledRules = skipDisabled
+
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		rs, err := loadRuleset(rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+
+		reports, err := analyzeRulesPerf(rs, opts)
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case "text":
+			writeRulesPerfReports(cmd.OutOrStdout(), reports)
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(reports); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+
+		if strict {
+			for _, rep := range reports {
+				if len(rep.Issues) > 0 {
+					err = errAppend(err, fmt.Errorf("rule `%s` has performance issues", rep.Rule))
+				}
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	perfCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	perfCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	perfCmd.Flags().StringP("output", "o", "text", "Output format, one of: text, json")
+	perfCmd.Flags().Int("max-list-size", 100, "Maximum number of values compared by a list operator")
+	perfCmd.Flags().Int("max-ancestry-depth", 3, "Maximum number of ancestors looked up by fields such as proc.aname")
+	perfCmd.Flags().Bool("all", false, "Report the estimated cost of all the rules, including the ones without issues")
+	perfCmd.Flags().Bool("skip-disabled", false, "Skip the rules that are disabled at default")
+	perfCmd.Flags().Bool("strict", false, "Fail if any rule has performance issues")
+	rootCmd.AddCommand(perfCmd)
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestrictsEventTypes(t *testing.T) {
+	t.Parallel()
+	tests := map[string]bool{
+		"evt.type = execve":                              true,
+		"evt.type in (open, openat) and fd.name = x":     true,
+		"proc.name = x":                                  false,
+		"evt.type = execve or proc.name = x":             false,
+		"evt.type = execve or evt.type in (open)":        true,
+		"evt.type != execve":                             false,
+		"not evt.type = execve":                          false,
+		"not evt.type != execve":                         true,
+		"not (evt.type != execve or proc.name = x)":      true,
+		"not (evt.type = execve and proc.name = x)":      false,
+		"proc.name = x and not evt.type in (open, read)": false,
+	}
+	for cond, expected := range tests {
+		c, err := parseCondition(cond)
+		require.NoError(t, err)
+		assert.Equal(t, expected, restrictsEventTypes(c, false), cond)
+	}
+}
+
+func TestConditionPerfIssues(t *testing.T) {
+	t.Parallel()
+	opts := perfOptions{MaxListSize: 3, MaxAncestryDepth: 3}
+	issues := func(cond string) []string {
+		c, err := parseCondition(cond)
+		require.NoError(t, err)
+		return conditionPerfIssues(c, opts)
+	}
+
+	assert.Empty(t, issues("evt.type = execve and proc.name in (a, b) and proc.aname[3] = c"))
+	assert.Equal(t, []string{
+		"Condition matches all event types",
+		"Negated event type filter `evt.type!=switch` matches all the other event types",
+	}, issues("evt.type != switch"))
+	assert.Equal(t, []string{
+		"Negated event type filter `not evt.type in (open, read)` matches all the other event types",
+	}, issues("evt.type = execve or not evt.type in (open, read)")[1:])
+	assert.Equal(t, []string{
+		"Expensive operator `regex` in `proc.cmdline regex \"a.*\"` is evaluated before the cheaper check `proc.name=x`",
+	}, issues("evt.type = execve and proc.cmdline regex \"a.*\" and proc.name = x"))
+	assert.Empty(t, issues("evt.type = execve and proc.name = x and proc.cmdline glob \"a*\""))
+	assert.Equal(t, []string{
+		"Ancestry field `proc.aname` walks all the ancestors of the process",
+		"Ancestry field `proc.apid[5]` looks up 5 ancestors of the process",
+	}, issues("evt.type = execve and proc.aname = x and proc.apid[5] = 1 and proc.aname = x"))
+	assert.Equal(t, []string{
+		"Operator `in` on `proc.name` compares against 4 values",
+	}, issues("evt.type = execve and proc.name in (a, b, c, d)"))
+}
+
+func TestAnalyzeRulesPerf(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- list: many
+  items: [a, b, c, d]
+
+- macro: spawned_process
+  condition: evt.type = execve
+
+- rule: cheap
+  desc: test
+  condition: spawned_process and proc.name = x
+  output: test
+  priority: INFO
+
+- rule: all_events
+  desc: test
+  condition: proc.name in (many)
+  output: test
+  priority: INFO
+
+- rule: plugin_rule
+  desc: test
+  condition: ka.verb regex "x.*"
+  output: test
+  priority: INFO
+  source: k8s_audit
+`)
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+
+	reports, err := analyzeRulesPerf(rs, perfOptions{MaxListSize: 3, MaxAncestryDepth: 3})
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, "all_events", reports[0].Rule)
+	assert.Equal(t, "a.yaml:14", reports[0].Location)
+	assert.Equal(t, 1*allEventTypesCostFactor, reports[0].Cost)
+	assert.Equal(t, []string{
+		"Condition matches all event types",
+		"Operator `in` on `proc.name` compares against 4 values",
+	}, reports[0].Issues)
+
+	reports, err = analyzeRulesPerf(rs, perfOptions{MaxListSize: 10, MaxAncestryDepth: 3, IncludeNoIssues: true})
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+	assert.Equal(t, "all_events", reports[0].Rule)
+	assert.Equal(t, "plugin_rule", reports[1].Rule)
+	assert.Equal(t, 10, reports[1].Cost)
+	assert.Empty(t, reports[1].Issues)
+	assert.Equal(t, "cheap", reports[2].Rule)
+	assert.Equal(t, 2, reports[2].Cost)
+
+	var buf bytes.Buffer
+	writeRulesPerfReports(&buf, reports[:1])
+	assert.Equal(t, "## all_events (a.yaml:14)\n\nEstimated relative cost: 10\n* Condition matches all event types\n\n", buf.String())
+}
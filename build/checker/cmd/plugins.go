@@ -0,0 +1,308 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// pluginInfo describes the fields and event sources provided by a plugin.
+// Fields ending with a dot are prefixes matching all the fields of a class.
+// The fields can be extracted from the events of the plugin sources, and of
+// the other sources listed in ExtractSources.
+type pluginInfo struct {
+	Name           string   `yaml:"name"`
+	Fields         []string `yaml:"fields"`
+	Sources        []string `yaml:"sources"`
+	ExtractSources []string `yaml:"extract_sources"`
+}
+
+// defaultPluginsTable contains the plugins used by the rules files of the
+// Falco ecosystem.
+var defaultPluginsTable = []pluginInfo{
+	{Name: "container", Fields: []string{"container.", "k8s.pod.", "k8s.ns."}, ExtractSources: []string{"syscall"}},
+	{Name: "k8smeta", Fields: []string{"k8smeta."}, ExtractSources: []string{"syscall"}},
+	{Name: "k8saudit", Fields: []string{"ka."}, Sources: []string{"k8s_audit"}},
+	{Name: "json", Fields: []string{"json.", "jevt."}, ExtractSources: []string{"k8s_audit"}},
+	{Name: "cloudtrail", Fields: []string{"ct.", "s3.", "ec2."}, Sources: []string{"aws_cloudtrail"}},
+	{Name: "okta", Fields: []string{"okta."}, Sources: []string{"okta"}},
+	{Name: "github", Fields: []string{"github."}, Sources: []string{"github"}},
+	{Name: "gcpaudit", Fields: []string{"gcp."}, Sources: []string{"gcp_auditlog"}},
+}
+
+func loadPluginsTable(path string) ([]pluginInfo, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res []pluginInfo
+	if err := yaml.Unmarshal(content, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// fieldPlugin returns the name of the plugin providing a field, using the
+// longest matching prefix, or an empty string if no plugin provides it.
+func fieldPlugin(plugins []pluginInfo, field string) string {
+	res, longest := "", 0
+	for _, p := range plugins {
+		for _, f := range p.Fields {
+			matches := f == field || (strings.HasSuffix(f, ".") && strings.HasPrefix(field, f))
+			if matches && len(f) > longest {
+				res, longest = p.Name, len(f)
+			}
+		}
+	}
+	return res
+}
+
+// sourcePlugin returns the name of the plugin providing an event source, or
+// an empty string if no plugin provides it.
+func sourcePlugin(plugins []pluginInfo, source string) string {
+	for _, p := range plugins {
+		if strSliceContains(p.Sources, source) {
+			return p.Name
+		}
+	}
+	return ""
+}
+
+// pluginRequirementMatches returns true if a plugin requirement, or one of
+// its alternatives, is for the given plugin.
+func pluginRequirementMatches(r rulesFilePluginRequirement, name string) bool {
+	if r.Name == name {
+		return true
+	}
+	for _, a := range r.Alternatives {
+		if a.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginUsage is the usage of the fields or event source of a plugin by the
+// items of a rules file.
+type pluginUsage struct {
+	Plugin string
+	// Uses contains the descriptions of what requires the plugin, such as
+	// "field `container.id`" or "source `k8s_audit`"
+	Uses  []string
+	Items []*rulesFileItem
+}
+
+func (u *pluginUsage) String() string {
+	var users []string
+	for _, i := range u.Items {
+		users = append(users, fmt.Sprintf("%s `%s` (%s)", i.Kind, i.Name(), i.Loc.String()))
+	}
+	return fmt.Sprintf("Plugin `%s` is required by %s, used by %s", u.Plugin, strings.Join(u.Uses, ", "), strings.Join(users, ", "))
+}
+
+// pluginsReport reports the plugins whose fields or event sources are used
+// by a rules file without a matching required_plugin_versions entry, and
+// the plugin requirements not needed by any item of the rules file.
+type pluginsReport struct {
+	Path        string
+	Missing     []*pluginUsage
+	Superfluous []rulesFilePluginRequirement
+}
+
+// analyzePluginRequirements loads the given rules files in order, and checks
+// for each of them that its plugin requirements match the plugin fields and
+// event sources it uses.
+func analyzePluginRequirements(files []*rulesFile, plugins []pluginInfo) ([]*pluginsReport, error) {
+	var res []*pluginsReport
+	rs := newRuleset()
+	for _, f := range files {
+		if err := rs.Add(f); err != nil {
+			return nil, err
+		}
+		rep := &pluginsReport{Path: f.Path}
+		var requirements []rulesFilePluginRequirement
+		var usages []*pluginUsage
+		use := func(plugin, what string, item *rulesFileItem) {
+			var u *pluginUsage
+			for _, cur := range usages {
+				if cur.Plugin == plugin {
+					u = cur
+				}
+			}
+			if u == nil {
+				u = &pluginUsage{Plugin: plugin}
+				usages = append(usages, u)
+			}
+			if !strSliceContains(u.Uses, what) {
+				u.Uses = append(u.Uses, what)
+			}
+			if len(u.Items) == 0 || u.Items[len(u.Items)-1] != item {
+				u.Items = append(u.Items, item)
+			}
+		}
+
+		for _, item := range f.Items {
+			if item.Kind == itemKindPlugin {
+				requirements = append(requirements, item.RequiredPluginVersions...)
+				continue
+			}
+			if item.Kind == itemKindRule && item.Keys["source"] {
+				if p := sourcePlugin(plugins, item.RuleSource()); len(p) > 0 {
+					use(p, fmt.Sprintf("source `%s`", item.RuleSource()), item)
+				}
+			}
+			uses, err := itemEngineFeatures(item, rs.ListLookup())
+			if err != nil {
+				return nil, err
+			}
+			for _, u := range uses {
+				if u.Kind != engineFeatureField {
+					continue
+				}
+				if p := fieldPlugin(plugins, u.Name); len(p) > 0 {
+					use(p, fmt.Sprintf("field `%s`", u.Name), item)
+				}
+			}
+		}
+
+		for _, u := range usages {
+			found := false
+			for _, r := range requirements {
+				found = found || pluginRequirementMatches(r, u.Plugin)
+			}
+			if !found {
+				rep.Missing = append(rep.Missing, u)
+			}
+		}
+		for _, r := range requirements {
+			used := false
+			for _, u := range usages {
+				used = used || pluginRequirementMatches(r, u.Plugin)
+			}
+			if !used {
+				rep.Superfluous = append(rep.Superfluous, r)
+			}
+		}
+		res = append(res, rep)
+	}
+	return res, nil
+}
+
+func writePluginsReports(w io.Writer, reports []*pluginsReport) {
+	for _, rep := range reports {
+		fmt.Fprintln(w, "## "+rep.Path)
+		fmt.Fprintln(w)
+		if len(rep.Missing) == 0 && len(rep.Superfluous) == 0 {
+			fmt.Fprintln(w, "No issues detected")
+			fmt.Fprintln(w)
+			continue
+		}
+		if len(rep.Missing) > 0 {
+			fmt.Fprintln(w, "**Missing** plugin requirements:")
+			for _, u := range rep.Missing {
+				fmt.Fprintln(w, "* "+u.String())
+			}
+			fmt.Fprintln(w)
+		}
+		if len(rep.Superfluous) > 0 {
+			fmt.Fprintln(w, "**Superfluous** plugin requirements:")
+			for _, r := range rep.Superfluous {
+				fmt.Fprintf(w, "* Plugin `%s` (version %s) is required, but none of its fields or event sources is used\n", r.Name, r.Version)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "Check that the plugin fields and event sources used by rules files are covered by their plugin requirements",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		tablePath, err := cmd.Flags().GetString("plugins-table")
+		if err != nil {
+			return err
+		}
+
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		plugins := defaultPluginsTable
+		if len(tablePath) > 0 {
+			plugins, err = loadPluginsTable(tablePath)
+			if err != nil {
+				return err
+			}
+		}
+
+		var files []*rulesFile
+		for _, p := range rulesFilesPaths {
+			f, err := loadRulesFile(p)
+			if err != nil {
+				return err
+			}
+			files = append(files, f)
+		}
+
+		reports, err := analyzePluginRequirements(files, plugins)
+		if err != nil {
+			return err
+		}
+		writePluginsReports(cmd.OutOrStdout(), reports)
+
+		for _, rep := range reports {
+			if len(rep.Missing) > 0 {
+				err = errAppend(err, fmt.Errorf("%s has missing plugin requirements", rep.Path))
+			}
+			if strict && len(rep.Superfluous) > 0 {
+				err = errAppend(err, fmt.Errorf("%s has superfluous plugin requirements", rep.Path))
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	pluginsCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	pluginsCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	pluginsCmd.Flags().String("plugins-table", "", "YAML file listing the name, fields, and event sources of each plugin (uses the bundled table if empty)")
+	pluginsCmd.Flags().Bool("strict", false, "Fail if any plugin requirement is superfluous")
+	rootCmd.AddCommand(pluginsCmd)
+}
@@ -0,0 +1,114 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldPlugin(t *testing.T) {
+	t.Parallel()
+	plugins := []pluginInfo{
+		{Name: "container", Fields: []string{"container.", "k8s.pod."}},
+		{Name: "k8smeta", Fields: []string{"k8s.pod.labels", "k8smeta."}},
+	}
+	assert.Equal(t, "container", fieldPlugin(plugins, "container.id"))
+	assert.Equal(t, "container", fieldPlugin(plugins, "k8s.pod.name"))
+	assert.Equal(t, "k8smeta", fieldPlugin(plugins, "k8s.pod.labels"))
+	assert.Equal(t, "k8smeta", fieldPlugin(plugins, "k8smeta.pod.name"))
+	assert.Empty(t, fieldPlugin(plugins, "proc.name"))
+	assert.Empty(t, fieldPlugin(plugins, "containers.id"))
+}
+
+func TestAnalyzePluginRequirements(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- required_plugin_versions:
+    - name: container
+      version: 0.4.0
+    - name: json
+      version: 0.7.0
+
+- rule: uses_container
+  desc: test
+  condition: container.id != host
+  output: "%container.name"
+  priority: INFO
+`, `
+- rule: uses_k8s
+  desc: test
+  condition: k8s.ns.name = default
+  output: "%proc.name"
+  priority: INFO
+
+- rule: audit
+  desc: test
+  condition: ka.verb = create
+  output: "%ka.user.name"
+  priority: INFO
+  source: k8s_audit
+`, `
+- required_plugin_versions:
+    - name: k8saudit
+      version: 0.1.0
+      alternatives:
+        - name: k8saudit-eks
+          version: 0.1.0
+
+- rule: audit_eks
+  desc: test
+  condition: evt.num > 0
+  output: test
+  priority: INFO
+  source: k8s_audit
+`)
+	plugins := []pluginInfo{
+		{Name: "container", Fields: []string{"container.", "k8s.ns."}},
+		{Name: "json", Fields: []string{"json."}},
+		{Name: "k8saudit-eks", Fields: []string{"ka."}, Sources: []string{"k8s_audit"}},
+	}
+	reports, err := analyzePluginRequirements(files, plugins)
+	require.NoError(t, err)
+	require.Len(t, reports, 3)
+
+	assert.Empty(t, reports[0].Missing)
+	require.Len(t, reports[0].Superfluous, 1)
+	assert.Equal(t, "json", reports[0].Superfluous[0].Name)
+
+	require.Len(t, reports[1].Missing, 2)
+	assert.Equal(t, "container", reports[1].Missing[0].Plugin)
+	assert.Equal(t, []string{"field `k8s.ns.name`"}, reports[1].Missing[0].Uses)
+	assert.Equal(t, "k8saudit-eks", reports[1].Missing[1].Plugin)
+	assert.Equal(t, []string{"source `k8s_audit`", "field `ka.verb`", "field `ka.user.name`"}, reports[1].Missing[1].Uses)
+	require.Len(t, reports[1].Missing[1].Items, 1)
+	assert.Empty(t, reports[1].Superfluous)
+
+	// requirements are matched through their alternatives too
+	assert.Empty(t, reports[2].Missing)
+	assert.Empty(t, reports[2].Superfluous)
+
+	var buf bytes.Buffer
+	writePluginsReports(&buf, reports)
+	assert.Contains(t, buf.String(), "* Plugin `json` (version 0.7.0) is required, but none of its fields or event sources is used")
+	assert.Contains(t, buf.String(), "* Plugin `container` is required by field `k8s.ns.name`, used by rule `uses_k8s` (b.yaml:2)")
+	assert.Contains(t, buf.String(), "No issues detected")
+}
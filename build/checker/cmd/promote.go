@@ -0,0 +1,581 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ruleMaturities are the maturity levels of the rules, in promotion order.
+var ruleMaturities = []string{"sandbox", "incubating", "stable"}
+
+const maturityDeprecated = "deprecated"
+
+// maturityRulesfiles are the names of the rules files of the registry
+// containing the rules of each maturity level.
+var maturityRulesfiles = map[string]string{
+	"stable":           "falco-rules",
+	"incubating":       "falco-incubating-rules",
+	"sandbox":          "falco-sandbox-rules",
+	maturityDeprecated: "falco-deprecated-rules",
+}
+
+var maturityTagRegex = regexp.MustCompile(`\bmaturity_[a-z]+\b`)
+
+// rulesFileItemSpans returns the indexes of the first and last lines of each
+// item of a rules file, including the comments right above the item.
+func rulesFileItemSpans(f *rulesFile) [][2]int {
+	blank := func(i int) bool { return len(strings.TrimSpace(f.Lines[i])) == 0 }
+	res := make([][2]int, len(f.Items))
+	for i, item := range f.Items {
+		start := item.Loc.Line - 1
+		end := len(f.Lines) - 1
+		if i+1 < len(f.Items) {
+			end = f.Items[i+1].Loc.Line - 2
+		}
+		// comments at the beginning of a line belong to the next item
+		for end > start && (blank(end) || strings.HasPrefix(f.Lines[end], "#")) {
+			end--
+		}
+		prevEnd := -1
+		if i > 0 {
+			prevEnd = res[i-1][1]
+		}
+		for start-1 > prevEnd && strings.HasPrefix(strings.TrimSpace(f.Lines[start-1]), "#") {
+			start--
+		}
+		res[i] = [2]int{start, end}
+	}
+	return res
+}
+
+// setRuleMaturity updates the lines of a rule definition to the given
+// maturity level, by replacing its maturity tag and by enabling stable rules
+// and disabling deprecated ones at default.
+func setRuleMaturity(lines []string, maturity string) []string {
+	res := append([]string{}, lines...)
+	first := 0
+	for first < len(res) && !strings.HasPrefix(strings.TrimSpace(res[first]), "-") {
+		first++
+	}
+	if first == len(res) {
+		return res
+	}
+	indent := strings.Repeat(" ", strings.Index(res[first], "-")+2)
+	keyRegex := regexp.MustCompile(`^` + indent + `[A-Za-z_]+:`)
+	keyLine := func(key string) (int, int) {
+		for i := first; i < len(res); i++ {
+			if (i == first || keyRegex.MatchString(res[i])) && strings.HasPrefix(strings.TrimLeft(res[i], "- "), key+":") {
+				end := i + 1
+				for end < len(res) && !keyRegex.MatchString(res[end]) {
+					end++
+				}
+				return i, end
+			}
+		}
+		return -1, -1
+	}
+
+	tag := "maturity_" + maturity
+	if start, end := keyLine("tags"); start < 0 {
+		res = append(res, indent+"tags: ["+tag+"]")
+	} else {
+		found := false
+		for i := start; i < end; i++ {
+			if maturityTagRegex.MatchString(res[i]) {
+				res[i] = maturityTagRegex.ReplaceAllString(res[i], tag)
+				found = true
+			}
+		}
+		switch {
+		case found:
+		case strings.Contains(res[start], "[]"):
+			res[start] = strings.Replace(res[start], "[]", "["+tag+"]", 1)
+		case strings.Contains(res[start], "["):
+			res[start] = strings.Replace(res[start], "[", "["+tag+", ", 1)
+		default:
+			// block style items can be preceded by comments or blank lines
+			item := -1
+			for i := start + 1; i < end && item < 0; i++ {
+				if t := strings.TrimSpace(res[i]); t == "-" || strings.HasPrefix(t, "- ") {
+					item = i
+				}
+			}
+			if item >= 0 {
+				line := res[item][:strings.Index(res[item], "-")] + "- " + tag
+				res = append(res[:item], append([]string{line}, res[item:]...)...)
+			} else {
+				res[start] = strings.TrimRight(res[start], " ") + " [" + tag + "]"
+			}
+		}
+	}
+
+	switch maturity {
+	case "stable":
+		if start, end := keyLine("enabled"); start >= 0 {
+			res = append(res[:start], res[end:]...)
+		}
+	case maturityDeprecated:
+		if start, end := keyLine("enabled"); start >= 0 {
+			res = append(res[:start], append([]string{indent + "enabled: false"}, res[end:]...)...)
+		} else {
+			res = append(res, indent+"enabled: false")
+		}
+	}
+	return res
+}
+
+// ruleMove describes the move of a rule from a rules file to another one.
+type ruleMove struct {
+	Rule     string
+	Maturity string
+	Src      *rulesFile
+	// Dst is nil if the destination rules file doesn't exist yet
+	Dst *rulesFile
+	// Available returns true if a macro or list is defined in the rules files
+	// loaded before the destination one
+	Available func(kind, name string) bool
+	// UsedElsewhere returns true if a macro or list of the source rules file
+	// is used by other rules files
+	UsedElsewhere func(kind, name string) bool
+	IsList        func(name string) bool
+}
+
+// ruleMoveResult contains the new contents of the source and destination
+// rules files of a rule move, and the macros and lists moved or copied along
+// with the rule.
+type ruleMoveResult struct {
+	Src    []byte
+	Dst    []byte
+	Moved  []string
+	Copied []string
+}
+
+// moveRule moves a rule along with the macros and lists it needs that are
+// defined in the source rules file. The ones that are private to the rule are
+// moved, while the ones that are still used in the source rules file or by
+// other rules files are copied, unless already available to the destination.
+func moveRule(m *ruleMove) (*ruleMoveResult, error) {
+	src := m.Src
+	defIndex := func(kind, name string) int {
+		for i, item := range src.Items {
+			if item.Kind == kind && item.Name() == name && !item.IsOverride() {
+				return i
+			}
+		}
+		return -1
+	}
+	if defIndex(itemKindRule, m.Rule) < 0 {
+		return nil, fmt.Errorf("%s: no rule found with name `%s`", src.Path, m.Rule)
+	}
+
+	isRule := make(map[int]bool)
+	var stack []int
+	for i, item := range src.Items {
+		if item.Kind == itemKindRule && item.Name() == m.Rule {
+			isRule[i] = true
+			stack = append(stack, i)
+		}
+	}
+
+	// macros and lists of the source rules file needed by the rule
+	deps := make(map[int]bool)
+	for len(stack) > 0 {
+		item := src.Items[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		refs, err := itemCrossRefs(item, m.IsList)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			if ref.Kind != itemKindMacro && ref.Kind != itemKindList {
+				continue
+			}
+			j := defIndex(ref.Kind, ref.Name)
+			if j < 0 || deps[j] || (m.Dst != nil && fileDefines(m.Dst, ref.Kind, ref.Name)) {
+				continue
+			}
+			deps[j] = true
+			stack = append(stack, j)
+		}
+	}
+
+	// dependencies still used after the move, including by other dependencies
+	// that are still used
+	shared := make(map[int]bool)
+	for j := range deps {
+		if m.UsedElsewhere(src.Items[j].Kind, src.Items[j].Name()) {
+			shared[j] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for i, item := range src.Items {
+			if isRule[i] || (deps[i] && !shared[i]) {
+				continue
+			}
+			refs, err := itemCrossRefs(item, m.IsList)
+			if err != nil {
+				return nil, err
+			}
+			for _, ref := range refs {
+				if j := defIndex(ref.Kind, ref.Name); j >= 0 && deps[j] && !shared[j] {
+					shared[j] = true
+					changed = true
+				}
+			}
+		}
+	}
+
+	res := &ruleMoveResult{}
+	spans := rulesFileItemSpans(src)
+	text := func(i int) []string {
+		return src.Lines[spans[i][0] : spans[i][1]+1]
+	}
+	var blocks []string
+	for i, item := range src.Items {
+		if !deps[i] {
+			continue
+		}
+		desc := fmt.Sprintf("%s `%s`", item.Kind, item.Name())
+		switch {
+		case !shared[i]:
+			res.Moved = append(res.Moved, desc)
+		case !m.Available(item.Kind, item.Name()):
+			res.Copied = append(res.Copied, desc)
+		default:
+			continue
+		}
+		blocks = append(blocks, strings.Join(text(i), "\n"))
+	}
+	defined := false
+	for i, item := range src.Items {
+		if !isRule[i] {
+			continue
+		}
+		lines := text(i)
+		if !item.IsOverride() && !defined {
+			lines = setRuleMaturity(lines, m.Maturity)
+			defined = true
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+
+	// removing the moved items from the source, along with one of the blank
+	// lines surrounding each of them
+	removed := make([]bool, len(src.Lines))
+	blank := func(i int) bool {
+		return i >= 0 && i < len(src.Lines) && len(strings.TrimSpace(src.Lines[i])) == 0
+	}
+	for i := range src.Items {
+		if !isRule[i] && !(deps[i] && !shared[i]) {
+			continue
+		}
+		for l := spans[i][0]; l <= spans[i][1]; l++ {
+			removed[l] = true
+		}
+		if blank(spans[i][0]-1) && blank(spans[i][1]+1) {
+			removed[spans[i][1]+1] = true
+		}
+	}
+	var lines []string
+	for i, l := range src.Lines {
+		if !removed[i] {
+			lines = append(lines, l)
+		}
+	}
+	res.Src = []byte(strings.Join(lines, "\n"))
+
+	var dst string
+	if m.Dst != nil {
+		dst = strings.Join(m.Dst.Lines, "\n")
+	} else {
+		dst = strings.Join(rulesFilePreamble(src), "\n")
+	}
+	dst = strings.TrimRight(dst, "\n")
+	if len(dst) > 0 {
+		dst += "\n\n"
+	}
+	res.Dst = []byte(dst + strings.Join(blocks, "\n\n") + "\n")
+	return res, nil
+}
+
+// rulesFilePreamble returns the lines of a rules file preceding its first
+// list, macro, or rule, such as the license header and the version
+// requirements.
+func rulesFilePreamble(f *rulesFile) []string {
+	spans := rulesFileItemSpans(f)
+	end := len(f.Lines)
+	for i, item := range f.Items {
+		if item.Kind == itemKindList || item.Kind == itemKindMacro || item.Kind == itemKindRule {
+			end = spans[i][0]
+			break
+		}
+	}
+	return f.Lines[:end]
+}
+
+// runRuleMove moves a rule to the rules file of the registry of the given
+// maturity level, and writes the compare classification of the changes of
+// the affected rules files.
+func runRuleMove(w io.Writer, registryPath, rule, maturity string, allowed func(from string) error, dryRun bool) error {
+	reg, err := loadRegistry(registryPath)
+	if err != nil {
+		return err
+	}
+
+	// loading all the rules files of the registry, in order
+	var files []*rulesFile
+	var src, dst *rulesFile
+	var srcMaturity string
+	dstIndex := -1
+	dstRulesfile, ok := reg.Rulesfile(maturityRulesfiles[maturity])
+	if !ok {
+		return fmt.Errorf("no rules file found in registry for maturity level '%s'", maturity)
+	}
+	for _, rf := range reg.Rulesfiles {
+		rf, _ = reg.Rulesfile(rf.Name)
+		if rf.Name == dstRulesfile.Name {
+			dstIndex = len(files)
+		}
+		if _, err := os.Stat(rf.Path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return err
+		}
+		f, err := loadRulesFile(rf.Path)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		if rf.Name == dstRulesfile.Name {
+			dst = f
+		}
+		for m, name := range maturityRulesfiles {
+			if rf.Name == name && fileDefines(f, itemKindRule, rule) {
+				src = f
+				srcMaturity = m
+			}
+		}
+	}
+	if src == nil {
+		return fmt.Errorf("no rule found with name `%s` in the rules files of the registry", rule)
+	}
+	if err := allowed(srcMaturity); err != nil {
+		return err
+	}
+
+	isList := func(name string) bool {
+		for _, f := range files {
+			if fileDefines(f, itemKindList, name) {
+				return true
+			}
+		}
+		return false
+	}
+	var refsErr error
+	move := &ruleMove{
+		Rule:     rule,
+		Maturity: maturity,
+		Src:      src,
+		Dst:      dst,
+		IsList:   isList,
+		Available: func(kind, name string) bool {
+			for i, f := range files {
+				if i < dstIndex && fileDefines(f, kind, name) {
+					return true
+				}
+			}
+			return false
+		},
+		UsedElsewhere: func(kind, name string) bool {
+			for _, f := range files {
+				if f == src || fileDefines(f, kind, name) {
+					continue
+				}
+				for _, item := range f.Items {
+					refs, err := itemCrossRefs(item, isList)
+					if err != nil {
+						refsErr = errAppend(refsErr, err)
+					}
+					for _, ref := range refs {
+						if ref.Kind == kind && ref.Name == name {
+							return true
+						}
+					}
+				}
+			}
+			return false
+		},
+	}
+	res, err := moveRule(move)
+	if err != nil {
+		return err
+	}
+	if refsErr != nil {
+		return refsErr
+	}
+
+	fmt.Fprintf(w, "Moving rule `%s` from %s to %s\n", rule, src.Path, dstRulesfile.Path)
+	for _, s := range res.Moved {
+		fmt.Fprintf(w, "* Moving %s\n", s)
+	}
+	for _, s := range res.Copied {
+		fmt.Fprintf(w, "* Copying %s\n", s)
+	}
+	fmt.Fprintln(w)
+
+	// both results are parsed before writing any of them
+	changes := []struct {
+		before *rulesFile
+		path   string
+		after  []byte
+	}{
+		{dst, dstRulesfile.Path, res.Dst},
+		{src, src.Path, res.Src},
+	}
+	var parsed []*rulesFile
+	for _, c := range changes {
+		after, err := parseRulesFile(c.path, c.after)
+		if err != nil {
+			return fmt.Errorf("moving rule `%s` would make %s invalid: %s", rule, c.path, err.Error())
+		}
+		parsed = append(parsed, after)
+	}
+	if !fileDefines(parsed[0], itemKindRule, rule) || fileDefines(parsed[1], itemKindRule, rule) {
+		return fmt.Errorf("moving rule `%s` failed, no rules file has been changed", rule)
+	}
+	// the changes are reported starting from the source
+	for _, c := range []int{1, 0} {
+		before := &rulesFile{Path: changes[c].path}
+		if changes[c].before != nil {
+			before = changes[c].before
+		}
+		fmt.Fprintf(w, "## %s\n\n", changes[c].path)
+		if !writeCompareResult(w, rulesFileDescription(before), rulesFileDescription(parsed[c])) {
+			fmt.Fprintln(w, "No changes detected")
+			fmt.Fprintln(w)
+		}
+	}
+	if dryRun {
+		return nil
+	}
+
+	// the destination is written first, so that the rule is never lost if
+	// writing the source fails
+	for _, c := range changes {
+		if err := writeFileAtomic(c.path, c.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote <rule>",
+	Short: "Promote a rule to the next maturity level, moving it and its private dependencies between the rules files of the registry",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		target := -1
+		for i, m := range ruleMaturities {
+			if m == to {
+				target = i
+			}
+		}
+		if target <= 0 {
+			return fmt.Errorf("rules can only be promoted to incubating or stable, got '%s'", to)
+		}
+
+		allowed := func(from string) error {
+			current := -1
+			for i, m := range ruleMaturities {
+				if m == from {
+					current = i
+				}
+			}
+			switch {
+			case current < 0:
+				return fmt.Errorf("%s rules can't be promoted", from)
+			case current >= target:
+				return fmt.Errorf("rule `%s` is already %s", args[0], from)
+			case current < target-1:
+				return fmt.Errorf("rule `%s` must be promoted to %s first", args[0], ruleMaturities[current+1])
+			}
+			return nil
+		}
+		return runRuleMove(cmd.OutOrStdout(), registryPath, args[0], to, allowed, dryRun)
+	},
+}
+
+var deprecateCmd = &cobra.Command{
+	Use:   "deprecate <rule>",
+	Short: "Deprecate a rule, moving it and its private dependencies to the deprecated rules file of the registry",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		allowed := func(from string) error {
+			if from == maturityDeprecated {
+				return fmt.Errorf("rule `%s` is already deprecated", args[0])
+			}
+			return nil
+		}
+		return runRuleMove(cmd.OutOrStdout(), registryPath, args[0], maturityDeprecated, allowed, dryRun)
+	},
+}
+
+func init() {
+	promoteCmd.Flags().String("to", "", "Maturity level to promote the rule to, either incubating or stable")
+	promoteCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	promoteCmd.Flags().Bool("dry-run", false, "Only print the changes without modifying the rules files")
+	rootCmd.AddCommand(promoteCmd)
+
+	deprecateCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	deprecateCmd.Flags().Bool("dry-run", false, "Only print the changes without modifying the rules files")
+	rootCmd.AddCommand(deprecateCmd)
+}
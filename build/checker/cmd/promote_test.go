@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRuleMaturity(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input    string
+		maturity string
+		expected string
+	}{
+		"replace-tag": {
+			input: `- rule: r1
+  enabled: false
+  tags: [maturity_sandbox, host]`,
+			maturity: "incubating",
+			expected: `- rule: r1
+  enabled: false
+  tags: [maturity_incubating, host]`,
+		},
+		"stable-enabled": {
+			input: `# comment
+- rule: r1
+  enabled: false
+  tags: [maturity_incubating, host]`,
+			maturity: "stable",
+			expected: `# comment
+- rule: r1
+  tags: [maturity_stable, host]`,
+		},
+		"deprecated-disabled": {
+			input: `- rule: r1
+  tags: [host]`,
+			maturity: "deprecated",
+			expected: `- rule: r1
+  tags: [maturity_deprecated, host]
+  enabled: false`,
+		},
+		"block-tags": {
+			input: `- rule: r1
+  tags:
+    - host
+  enabled: true`,
+			maturity: "deprecated",
+			expected: `- rule: r1
+  tags:
+    - maturity_deprecated
+    - host
+  enabled: false`,
+		},
+		"block-tags-comment": {
+			input: `- rule: r1
+  tags:
+  # the maturity goes first
+    - host`,
+			maturity: "stable",
+			expected: `- rule: r1
+  tags:
+  # the maturity goes first
+    - maturity_stable
+    - host`,
+		},
+		"block-tags-empty": {
+			input: `- rule: r1
+  tags:
+  # no tags yet
+  priority: INFO`,
+			maturity: "sandbox",
+			expected: `- rule: r1
+  tags: [maturity_sandbox]
+  # no tags yet
+  priority: INFO`,
+		},
+		"no-tags": {
+			input:    `- rule: r1`,
+			maturity: "incubating",
+			expected: `- rule: r1
+  tags: [maturity_incubating]`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			res := setRuleMaturity(strings.Split(test.input, "\n"), test.maturity)
+			assert.Equal(t, test.expected, strings.Join(res, "\n"))
+		})
+	}
+}
+
+func TestMoveRule(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, `# header
+
+- list: l1
+  items: [a]
+
+- macro: m1
+  condition: proc.name in (l1)
+
+- macro: m2
+  condition: evt.type = open
+
+# my rule
+- rule: r1
+  desc: my rule
+  condition: m1 and m2 and m3
+  output: test
+  priority: WARNING
+  enabled: false
+  tags: [maturity_incubating, host]
+
+- rule: r2
+  desc: other rule
+  condition: m2
+  output: test
+  priority: WARNING
+  tags: [maturity_incubating, host]
+`, `- macro: m3
+  condition: evt.type = execve
+`)
+
+	move := &ruleMove{
+		Rule:          "r1",
+		Maturity:      "stable",
+		Src:           files[0],
+		Dst:           files[1],
+		Available:     func(kind, name string) bool { return false },
+		UsedElsewhere: func(kind, name string) bool { return false },
+		IsList:        func(name string) bool { return name == "l1" },
+	}
+	res, err := moveRule(move)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"list `l1`", "macro `m1`"}, res.Moved)
+	assert.Equal(t, []string{"macro `m2`"}, res.Copied)
+	assert.Equal(t, `# header
+
+- macro: m2
+  condition: evt.type = open
+
+- rule: r2
+  desc: other rule
+  condition: m2
+  output: test
+  priority: WARNING
+  tags: [maturity_incubating, host]
+`, string(res.Src))
+	assert.Equal(t, `- macro: m3
+  condition: evt.type = execve
+
+- list: l1
+  items: [a]
+
+- macro: m1
+  condition: proc.name in (l1)
+
+- macro: m2
+  condition: evt.type = open
+
+# my rule
+- rule: r1
+  desc: my rule
+  condition: m1 and m2 and m3
+  output: test
+  priority: WARNING
+  tags: [maturity_stable, host]
+`, string(res.Dst))
+
+	// new destination file, shared macros already available
+	move.Dst = nil
+	move.Available = func(kind, name string) bool { return true }
+	res, err = moveRule(move)
+	require.NoError(t, err)
+	assert.Empty(t, res.Copied)
+	assert.True(t, strings.HasPrefix(string(res.Dst), "# header\n\n- list: l1\n"))
+
+	move.Rule = "r3"
+	_, err = moveRule(move)
+	assert.Error(t, err)
+}
+
+func TestRunRuleMove(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "rules"), 0755))
+	registryPath := filepath.Join(dir, "registry.yaml")
+	require.NoError(t, os.WriteFile(registryPath, []byte(`rulesfiles:
+  - name: falco-rules
+    path: rules/falco_rules.yaml
+  - name: falco-incubating-rules
+    path: rules/falco-incubating_rules.yaml
+`), 0644))
+	stablePath := filepath.Join(dir, "rules", "falco_rules.yaml")
+	incubatingPath := filepath.Join(dir, "rules", "falco-incubating_rules.yaml")
+	require.NoError(t, os.WriteFile(stablePath, []byte(`- macro: spawned_process
+  condition: evt.type = execve
+`), 0644))
+	incubating := []byte(`- list: shells
+  items: [sh]
+
+- rule: r1
+  desc: test
+  condition: spawned_process and proc.name in (shells)
+  output: test
+  priority: WARNING
+  enabled: false
+  tags: [maturity_incubating]
+`)
+	require.NoError(t, os.WriteFile(incubatingPath, incubating, 0644))
+	allowed := func(string) error { return nil }
+
+	var buf bytes.Buffer
+	require.NoError(t, runRuleMove(&buf, registryPath, "r1", "stable", allowed, true))
+	content, err := os.ReadFile(incubatingPath)
+	require.NoError(t, err)
+	assert.Equal(t, incubating, content)
+	assert.Less(t, strings.Index(buf.String(), "## "+incubatingPath), strings.Index(buf.String(), "## "+stablePath))
+
+	buf.Reset()
+	require.NoError(t, runRuleMove(&buf, registryPath, "r1", "stable", allowed, false))
+	content, err = os.ReadFile(incubatingPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "r1")
+	assert.NotContains(t, string(content), "shells")
+	content, err = os.ReadFile(stablePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "- rule: r1")
+	assert.Contains(t, string(content), "- list: shells")
+	entries, err := os.ReadDir(filepath.Join(dir, "rules"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
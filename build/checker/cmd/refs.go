@@ -0,0 +1,300 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// crossRef is a reference from a rule or macro to a macro or list, or from
+// an override to the object it overrides.
+type crossRef struct {
+	// From is the item containing the reference
+	From *rulesFileItem
+	// Kind is the kind of the referenced object
+	Kind string
+	// Name is the name of the referenced object
+	Name string
+	// ResolvedIn is the path of the first rules file defining the referenced
+	// object, or empty if the object is not defined in any file
+	ResolvedIn string
+	// Forward is true if the object is defined in a rules file that is
+	// loaded after the one containing the reference
+	Forward bool
+}
+
+func (r *crossRef) String() string {
+	if r.From.Kind == r.Kind && r.From.Name() == r.Name {
+		s := fmt.Sprintf("%s `%s` overridden at %s", capitalize(r.Kind), r.Name, r.From.Loc.String())
+		if len(r.ResolvedIn) == 0 {
+			return s + " is not defined in any rules file"
+		}
+		return s + " is defined in " + r.ResolvedIn
+	}
+	s := fmt.Sprintf("%s `%s` referenced by %s `%s` (%s)",
+		capitalize(r.Kind), r.Name, r.From.Kind, r.From.Name(), r.From.Loc.String())
+	if len(r.ResolvedIn) == 0 {
+		return s + " is not defined in any rules file"
+	}
+	s += " is defined in " + r.ResolvedIn
+	if r.Forward {
+		s += ", which is loaded later"
+	}
+	return s
+}
+
+// unusedRef is a macro or list that no rule of the same rules file uses.
+type unusedRef struct {
+	Item *rulesFileItem
+	// UsedBy contains the paths of the other rules files containing rules
+	// that use the macro or list, if any
+	UsedBy []string
+}
+
+func (u *unusedRef) String() string {
+	s := fmt.Sprintf("%s `%s` (%s)", capitalize(u.Item.Kind), u.Item.Name(), u.Item.Loc.String())
+	if len(u.UsedBy) == 0 {
+		return s + " is not used by any rule"
+	}
+	return s + " is only used by rules in " + strings.Join(u.UsedBy, ", ")
+}
+
+// fileRefsReport reports the cross-file references of a rules file when
+// loaded along with other rules files.
+type fileRefsReport struct {
+	Path      string
+	Unused    []*unusedRef
+	External  []*crossRef
+	Undefined []*crossRef
+}
+
+// itemCrossRefs returns the macros and lists referenced by a rules file item,
+// including the object the item overrides, if any.
+func itemCrossRefs(item *rulesFileItem, isList func(string) bool) ([]*crossRef, error) {
+	var res []*crossRef
+	if item.IsOverride() {
+		res = append(res, &crossRef{From: item, Kind: item.Kind, Name: item.Name()})
+	}
+	switch item.Kind {
+	case itemKindList:
+		for _, i := range item.Items {
+			if isList(i) {
+				res = append(res, &crossRef{From: item, Kind: itemKindList, Name: i})
+			}
+		}
+	case itemKindMacro, itemKindRule:
+		if !item.Keys["condition"] {
+			return nil, nil
+		}
+		c, err := parseConditionFragment(item.Condition)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s `%s` has an invalid condition: %s", item.Loc.String(), item.Kind, item.Name(), err.Error())
+		}
+		for _, m := range conditionMacroRefs(c) {
+			res = append(res, &crossRef{From: item, Kind: itemKindMacro, Name: m})
+		}
+		for _, l := range conditionListRefs(c, isList) {
+			res = append(res, &crossRef{From: item, Kind: itemKindList, Name: l})
+		}
+	}
+	return res, nil
+}
+
+// analyzeFileRefs loads the given rules files in order, and reports for each
+// of them the macros and lists that its rules don't use, and the references
+// that resolve only thanks to other files, or that don't resolve at all.
+func analyzeFileRefs(files []*rulesFile) ([]*fileRefsReport, error) {
+	rs := newRuleset()
+	definedIn := make(map[string]int)
+	for i, f := range files {
+		if err := rs.Add(f); err != nil {
+			return nil, err
+		}
+		for _, item := range f.Items {
+			id := depGraphNodeID(item.Kind, item.Name())
+			if _, ok := definedIn[id]; !ok && !item.IsOverride() {
+				definedIn[id] = i
+			}
+		}
+	}
+	isList := func(name string) bool { return rs.List(name) != nil }
+
+	g, err := buildDepGraph(rs)
+	if err != nil {
+		return nil, err
+	}
+
+	// files containing the rules that use each macro and list
+	usedBy := make(map[string]map[string]bool)
+	for _, r := range rs.Rules {
+		for id := range g.reachable([]string{depGraphNodeID(itemKindRule, r.Name())}, false) {
+			if usedBy[id] == nil {
+				usedBy[id] = make(map[string]bool)
+			}
+			for _, o := range r.Origins {
+				usedBy[id][o.Loc.File] = true
+			}
+		}
+	}
+
+	var res []*fileRefsReport
+	for i, f := range files {
+		rep := &fileRefsReport{Path: f.Path}
+		for _, item := range f.Items {
+			refs, err := itemCrossRefs(item, isList)
+			if err != nil {
+				return nil, err
+			}
+			for _, ref := range refs {
+				idx, ok := definedIn[depGraphNodeID(ref.Kind, ref.Name)]
+				switch {
+				case !ok:
+					rep.Undefined = append(rep.Undefined, ref)
+				case idx != i && !fileDefines(f, ref.Kind, ref.Name):
+					ref.ResolvedIn = files[idx].Path
+					ref.Forward = idx > i
+					rep.External = append(rep.External, ref)
+				}
+			}
+		}
+		for _, item := range f.Items {
+			if (item.Kind != itemKindMacro && item.Kind != itemKindList) || item.IsOverride() {
+				continue
+			}
+			users := usedBy[depGraphNodeID(item.Kind, item.Name())]
+			if users[f.Path] {
+				continue
+			}
+			u := &unusedRef{Item: item}
+			for p := range users {
+				u.UsedBy = append(u.UsedBy, p)
+			}
+			sort.Strings(u.UsedBy)
+			rep.Unused = append(rep.Unused, u)
+		}
+		res = append(res, rep)
+	}
+	return res, nil
+}
+
+// fileDefines returns true if a rules file defines an object, without
+// appending to or overriding it.
+func fileDefines(f *rulesFile, kind, name string) bool {
+	for _, item := range f.Items {
+		if item.Kind == kind && item.Name() == name && !item.IsOverride() {
+			return true
+		}
+	}
+	return false
+}
+
+func writeFileRefsReports(w io.Writer, reports []*fileRefsReport) {
+	for _, rep := range reports {
+		fmt.Fprintln(w, "## "+rep.Path)
+		fmt.Fprintln(w)
+		if len(rep.Unused) == 0 && len(rep.External) == 0 && len(rep.Undefined) == 0 {
+			fmt.Fprintln(w, "No issues detected")
+			fmt.Fprintln(w)
+			continue
+		}
+		if len(rep.Undefined) > 0 {
+			fmt.Fprintln(w, "**Undefined** references:")
+			for _, r := range rep.Undefined {
+				fmt.Fprintln(w, "* "+r.String())
+			}
+			fmt.Fprintln(w)
+		}
+		if len(rep.External) > 0 {
+			fmt.Fprintln(w, "**External** references:")
+			for _, r := range rep.External {
+				fmt.Fprintln(w, "* "+r.String())
+			}
+			fmt.Fprintln(w)
+		}
+		if len(rep.Unused) > 0 {
+			fmt.Fprintln(w, "**Unused** macros and lists:")
+			for _, u := range rep.Unused {
+				fmt.Fprintln(w, "* "+u.String())
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
+
+var refsCmd = &cobra.Command{
+	Use:   "refs",
+	Short: "Report unused macros and lists, and references resolving only across rules files",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		var files []*rulesFile
+		for _, p := range rulesFilesPaths {
+			f, err := loadRulesFile(p)
+			if err != nil {
+				return err
+			}
+			files = append(files, f)
+		}
+
+		reports, err := analyzeFileRefs(files)
+		if err != nil {
+			return err
+		}
+		writeFileRefsReports(cmd.OutOrStdout(), reports)
+
+		for _, rep := range reports {
+			if len(rep.Undefined) > 0 {
+				err = errAppend(err, fmt.Errorf("%s has undefined references", rep.Path))
+			}
+			if strict && len(rep.Unused) > 0 {
+				err = errAppend(err, fmt.Errorf("%s has unused macros or lists", rep.Path))
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	refsCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	refsCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	refsCmd.Flags().Bool("strict", false, "Fail if any macro or list is unused")
+	rootCmd.AddCommand(refsCmd)
+}
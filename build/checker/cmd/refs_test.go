@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testParseRulesFiles(t *testing.T, contents ...string) []*rulesFile {
+	var res []*rulesFile
+	for i, c := range contents {
+		f, err := parseRulesFile(string(rune('a'+i))+".yaml", []byte(c))
+		require.NoError(t, err)
+		res = append(res, f)
+	}
+	return res
+}
+
+func TestAnalyzeFileRefs(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- list: shells
+  items: [bash]
+
+- macro: spawned_process
+  condition: evt.type = execve
+
+- macro: only_used_by_b
+  condition: proc.name in (shells)
+
+- macro: never_used
+  condition: evt.num = 0
+
+- rule: rule_a
+  desc: rule a
+  condition: spawned_process and proc.name in (shells) and forward_macro
+  output: a
+  priority: INFO
+`, `
+- macro: forward_macro
+  condition: evt.num > 0
+
+- rule: rule_b
+  desc: rule b
+  condition: spawned_process and only_used_by_b and undefined_macro
+  output: b
+  priority: INFO
+`)
+	reports, err := analyzeFileRefs(files)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+
+	a := reports[0]
+	assert.Empty(t, a.Undefined)
+	require.Len(t, a.External, 1)
+	assert.Equal(t, "forward_macro", a.External[0].Name)
+	assert.Equal(t, "b.yaml", a.External[0].ResolvedIn)
+	assert.True(t, a.External[0].Forward)
+	require.Len(t, a.Unused, 2)
+	assert.Equal(t, "only_used_by_b", a.Unused[0].Item.Name())
+	assert.Equal(t, []string{"b.yaml"}, a.Unused[0].UsedBy)
+	assert.Equal(t, "never_used", a.Unused[1].Item.Name())
+	assert.Empty(t, a.Unused[1].UsedBy)
+
+	b := reports[1]
+	require.Len(t, b.Undefined, 1)
+	assert.Equal(t, "undefined_macro", b.Undefined[0].Name)
+	require.Len(t, b.External, 2)
+	assert.False(t, b.External[0].Forward)
+	require.Len(t, b.Unused, 1)
+	assert.Equal(t, []string{"a.yaml"}, b.Unused[0].UsedBy)
+
+	var buf bytes.Buffer
+	writeFileRefsReports(&buf, reports)
+	assert.Contains(t, buf.String(), "* Macro `only_used_by_b` (a.yaml:8) is only used by rules in b.yaml\n")
+	assert.Contains(t, buf.String(), "* Macro `undefined_macro` referenced by rule `rule_b` (b.yaml:5) is not defined in any rules file\n")
+	assert.Contains(t, buf.String(), "* Macro `forward_macro` referenced by rule `rule_a` (a.yaml:14) is defined in b.yaml, which is loaded later\n")
+}
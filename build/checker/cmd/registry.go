@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+const defaultRegistryPath = "registry.yaml"
+
+// registryRulesfile is a rules file declared in the registry.
+type registryRulesfile struct {
+	Name     string `yaml:"name"`
+	Path     string `yaml:"path"`
+	Archived bool   `yaml:"archived"`
+	Reserved bool   `yaml:"reserved"`
+}
+
+// registry is the subset of the rules files registry used by the checker.
+// Rules files are meant to be loaded in the order in which they're declared.
+type registry struct {
+	Rulesfiles []registryRulesfile `yaml:"rulesfiles"`
+	root       string
+}
+
+func loadRegistry(path string) (*registry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseRegistry(path, content)
+}
+
+// parseRegistry parses the content of a registry file located at the given
+// path.
+func parseRegistry(path string, content []byte) (*registry, error) {
+	var res registry
+	if err := yaml.Unmarshal(content, &res); err != nil {
+		return nil, err
+	}
+	res.root = filepath.Dir(path)
+	return &res, nil
+}
+
+// ActiveRulesfiles returns the rules files that are not archived nor reserved
+// and that exist on disk, in load order. The paths are relative to the
+// directory containing the registry.
+func (r *registry) ActiveRulesfiles() []registryRulesfile {
+	var res []registryRulesfile
+	for _, rf := range r.Rulesfiles {
+		if rf.Archived || rf.Reserved {
+			continue
+		}
+		rf.Path = filepath.Join(r.root, rf.Path)
+		if _, err := os.Stat(rf.Path); err != nil {
+			logrus.Warnf("skipping rules file '%s' of registry: %s", rf.Name, err.Error())
+			continue
+		}
+		res = append(res, rf)
+	}
+	return res
+}
+
+// Rulesfile returns the rules file of the registry with the given name, with
+// its path relative to the directory containing the registry.
+func (r *registry) Rulesfile(name string) (registryRulesfile, bool) {
+	for _, rf := range r.Rulesfiles {
+		if rf.Name == name {
+			rf.Path = filepath.Join(r.root, rf.Path)
+			return rf, true
+		}
+	}
+	return registryRulesfile{}, false
+}
+
+// getRulesFilesPaths returns the rules files to be loaded by commands that
+// accept either a list of rules files or a registry, giving precedence to
+// the rules files.
+func getRulesFilesPaths(rulesFiles []string, registryPath string) ([]string, error) {
+	if len(rulesFiles) > 0 {
+		return rulesFiles, nil
+	}
+	reg, err := loadRegistry(registryPath)
+	if err != nil {
+		return nil, err
+	}
+	var res []string
+	for _, rf := range reg.ActiveRulesfiles() {
+		res = append(res, rf.Path)
+	}
+	return res, nil
+}
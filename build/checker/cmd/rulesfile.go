@@ -0,0 +1,535 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	itemKindRule   = "rule"
+	itemKindMacro  = "macro"
+	itemKindList   = "list"
+	itemKindEngine = "required_engine_version"
+	itemKindPlugin = "required_plugin_versions"
+)
+
+const defaultRuleSource = "syscall"
+
+// srcLoc is a location in a rules file.
+type srcLoc struct {
+	File string
+	Line int
+}
+
+func (l srcLoc) String() string {
+	return fmt.Sprintf("%s:%d", l.File, l.Line)
+}
+
+// sourceText is a piece of text read from one or more rules files, which
+// keeps track of the location each of its characters comes from.
+type sourceText struct {
+	Text string
+	locs []srcLoc
+}
+
+// LocAt returns the location of the character at the given offset.
+func (s *sourceText) LocAt(offset int) srcLoc {
+	if len(s.locs) == 0 {
+		return srcLoc{}
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(s.locs) {
+		offset = len(s.locs) - 1
+	}
+	return s.locs[offset]
+}
+
+// Append returns the concatenation of two source texts separated by a space,
+// which is how Falco appends conditions.
+func (s sourceText) Append(o sourceText) sourceText {
+	if len(s.Text) == 0 {
+		return o
+	}
+	res := sourceText{Text: s.Text + " " + o.Text}
+	res.locs = append(res.locs, s.locs...)
+	res.locs = append(res.locs, s.LocAt(len(s.Text)-1))
+	res.locs = append(res.locs, o.locs...)
+	return res
+}
+
+// newSourceText creates a source text for the value of a YAML scalar node,
+// by aligning the non-whitespace characters of the value with the raw lines
+// of the file. This works for all the YAML scalar styles, including the
+// folded ones in which line breaks are lost.
+func newSourceText(file string, lines []string, n *yaml.Node) sourceText {
+	res := sourceText{Text: n.Value, locs: make([]srcLoc, len(n.Value))}
+	line := n.Line - 1
+	col := n.Column - 1
+	if n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		line++
+		col = 0
+	}
+	cur := srcLoc{File: file, Line: n.Line}
+	for i := 0; i < len(n.Value); i++ {
+		c := n.Value[i]
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+			// bounded lookahead to be robust to escape sequences
+			for l, cl, steps := line, col, 0; l < len(lines) && steps < 256; steps++ {
+				if cl >= len(lines[l]) {
+					l, cl = l+1, 0
+					continue
+				}
+				if lines[l][cl] == c {
+					line, col = l, cl+1
+					cur.Line = l + 1
+					break
+				}
+				cl++
+			}
+		}
+		res.locs[i] = cur
+	}
+	return res
+}
+
+// rulesFilePluginRequirement is an entry of required_plugin_versions.
+type rulesFilePluginRequirement struct {
+	Name         string                       `yaml:"name"`
+	Version      string                       `yaml:"version"`
+	Alternatives []rulesFilePluginRequirement `yaml:"alternatives,omitempty"`
+}
+
+// ruleException is an entry of the exceptions of a rule.
+type ruleException struct {
+	Name   string      `yaml:"name"`
+	Fields interface{} `yaml:"fields"`
+	Comps  interface{} `yaml:"comps"`
+	Values interface{} `yaml:"values"`
+}
+
+// rulesFileItem is one of the top-level entries of a rules file.
+type rulesFileItem struct {
+	RequiredEngineVersion  string                       `yaml:"required_engine_version"`
+	RequiredPluginVersions []rulesFilePluginRequirement `yaml:"required_plugin_versions"`
+	List                   string                       `yaml:"list"`
+	Items                  []string                     `yaml:"items"`
+	Macro                  string                       `yaml:"macro"`
+	Rule                   string                       `yaml:"rule"`
+	Desc                   string                       `yaml:"desc"`
+	Condition              string                       `yaml:"condition"`
+	Output                 string                       `yaml:"output"`
+	Priority               string                       `yaml:"priority"`
+	Source                 string                       `yaml:"source"`
+	Tags                   []string                     `yaml:"tags"`
+	Enabled                *bool                        `yaml:"enabled"`
+	Exceptions             []ruleException              `yaml:"exceptions"`
+	Append                 bool                         `yaml:"append"`
+	Override               map[string]string            `yaml:"override"`
+
+	// Kind is one of the itemKind* constants
+	Kind string `yaml:"-"`
+	// Loc is the location of the item in its rules file
+	Loc srcLoc `yaml:"-"`
+	// Keys contains all the keys defined for the item
+	Keys map[string]bool `yaml:"-"`
+	// Node is the YAML node from which the item has been decoded
+	Node *yaml.Node `yaml:"-"`
+	// Cond is the item condition, tracking the locations of its characters
+	Cond sourceText `yaml:"-"`
+}
+
+// Name returns the name of a list, macro, or rule.
+func (i *rulesFileItem) Name() string {
+	switch i.Kind {
+	case itemKindRule:
+		return i.Rule
+	case itemKindMacro:
+		return i.Macro
+	case itemKindList:
+		return i.List
+	}
+	return ""
+}
+
+// IsOverride returns true if the item appends to or overrides an item with
+// the same name defined before.
+func (i *rulesFileItem) IsOverride() bool {
+	return i.Append || len(i.Override) > 0 || (i.Kind == itemKindRule && i.isEnabledOnly())
+}
+
+// isEnabledOnly returns true for rule items that only toggle whether the rule
+// is enabled, which Falco accepts without the override key.
+func (i *rulesFileItem) isEnabledOnly() bool {
+	return len(i.Keys) == 2 && i.Keys["rule"] && i.Keys["enabled"]
+}
+
+// IsEnabled returns true if a rule is enabled at default.
+func (i *rulesFileItem) IsEnabled() bool {
+	return i.Enabled == nil || *i.Enabled
+}
+
+// RuleSource returns the event source of a rule.
+func (i *rulesFileItem) RuleSource() string {
+	if len(i.Source) == 0 {
+		return defaultRuleSource
+	}
+	return i.Source
+}
+
+// rulesFile is a parsed rules file.
+type rulesFile struct {
+	Path  string
+	Items []*rulesFileItem
+	Lines []string
+	Root  *yaml.Node
+}
+
+// parseRulesFile parses the content of a rules file.
+func parseRulesFile(path string, content []byte) (*rulesFile, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	res := &rulesFile{
+		Path:  path,
+		Lines: strings.Split(string(content), "\n"),
+		Root:  &root,
+	}
+	if len(root.Content) == 0 {
+		return res, nil
+	}
+	seq := root.Content[0]
+	if seq.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s: rules content is not yaml array of objects", path)
+	}
+	for _, n := range seq.Content {
+		item, err := parseRulesFileItem(path, res.Lines, n)
+		if err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, item)
+	}
+	return res, nil
+}
+
+func parseRulesFileItem(path string, lines []string, n *yaml.Node) (*rulesFileItem, error) {
+	loc := srcLoc{File: path, Line: n.Line}
+	if n.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s: unexpected element type, expected an object", loc.String())
+	}
+	item := &rulesFileItem{Loc: loc, Node: n, Keys: make(map[string]bool)}
+	if err := n.Decode(item); err != nil {
+		return nil, fmt.Errorf("%s: %s", loc.String(), err.Error())
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key := n.Content[i].Value
+		item.Keys[key] = true
+		if key == "condition" {
+			item.Cond = newSourceText(path, lines, n.Content[i+1])
+		}
+	}
+	for _, k := range []string{itemKindRule, itemKindMacro, itemKindList, itemKindEngine, itemKindPlugin} {
+		if item.Keys[k] {
+			item.Kind = k
+			break
+		}
+	}
+	if len(item.Kind) == 0 {
+		return nil, fmt.Errorf("%s: unknown top level object", loc.String())
+	}
+	return item, nil
+}
+
+// loadRulesFile reads and parses a rules file from disk.
+func loadRulesFile(path string) (*rulesFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseRulesFile(path, content)
+}
+
+// rulesetEntry is a list, macro, or rule resulting from loading one or more
+// rules files, with all the appends and overrides applied in load order.
+type rulesetEntry struct {
+	*rulesFileItem
+	// Origins contains the defining item, followed by all the items that
+	// appended to it or overridden it
+	Origins []*rulesFileItem
+}
+
+// ruleset is the knowledge resulting from loading one or more rules files
+// in order, similarly to what the Falco engine does.
+type ruleset struct {
+	Files                  []*rulesFile
+	RequiredEngineVersion  string
+	RequiredPluginVersions []rulesFilePluginRequirement
+	Lists                  []*rulesetEntry
+	Macros                 []*rulesetEntry
+	Rules                  []*rulesetEntry
+	lists                  map[string]*rulesetEntry
+	macros                 map[string]*rulesetEntry
+	rules                  map[string]*rulesetEntry
+}
+
+func newRuleset() *ruleset {
+	return &ruleset{
+		lists:  make(map[string]*rulesetEntry),
+		macros: make(map[string]*rulesetEntry),
+		rules:  make(map[string]*rulesetEntry),
+	}
+}
+
+// loadRuleset loads one or more rules files in order.
+func loadRuleset(paths ...string) (*ruleset, error) {
+	rs := newRuleset()
+	for _, p := range paths {
+		f, err := loadRulesFile(p)
+		if err != nil {
+			return nil, err
+		}
+		if err := rs.Add(f); err != nil {
+			return nil, err
+		}
+	}
+	return rs, nil
+}
+
+// List returns the list with the given name, or nil if not found.
+func (r *ruleset) List(name string) *rulesetEntry {
+	return r.lists[name]
+}
+
+// Macro returns the macro with the given name, or nil if not found.
+func (r *ruleset) Macro(name string) *rulesetEntry {
+	return r.macros[name]
+}
+
+// Rule returns the rule with the given name, or nil if not found.
+func (r *ruleset) Rule(name string) *rulesetEntry {
+	return r.rules[name]
+}
+
+func (r *ruleset) entries(kind string) (*[]*rulesetEntry, map[string]*rulesetEntry) {
+	switch kind {
+	case itemKindList:
+		return &r.Lists, r.lists
+	case itemKindMacro:
+		return &r.Macros, r.macros
+	}
+	return &r.Rules, r.rules
+}
+
+// Add loads all the items of a rules file into the ruleset.
+func (r *ruleset) Add(f *rulesFile) error {
+	r.Files = append(r.Files, f)
+	for _, item := range f.Items {
+		var err error
+		switch item.Kind {
+		case itemKindEngine:
+			r.RequiredEngineVersion = item.RequiredEngineVersion
+		case itemKindPlugin:
+			r.RequiredPluginVersions = append(r.RequiredPluginVersions, item.RequiredPluginVersions...)
+		default:
+			err = r.addItem(item)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s", item.Loc.String(), err.Error())
+		}
+	}
+	return nil
+}
+
+func (r *ruleset) addItem(item *rulesFileItem) error {
+	list, byName := r.entries(item.Kind)
+	prev, exists := byName[item.Name()]
+	if !item.IsOverride() {
+		e := &rulesetEntry{rulesFileItem: copyRulesFileItem(item), Origins: []*rulesFileItem{item}}
+		if exists {
+			// redefining an item replaces it but keeps its original position
+			*prev = *e
+			return nil
+		}
+		byName[item.Name()] = e
+		*list = append(*list, e)
+		return nil
+	}
+	if !exists {
+		return fmt.Errorf("%s `%s` has 'append' or 'override' key but no %s by that name already exists",
+			item.Kind, item.Name(), item.Kind)
+	}
+	prev.Origins = append(prev.Origins, item)
+	for key := range item.Keys {
+		if key == item.Kind || key == "append" || key == "override" {
+			continue
+		}
+		mode := "replace"
+		if item.Append {
+			mode = "append"
+		} else if m, ok := item.Override[key]; ok {
+			mode = m
+		} else if !item.isEnabledOnly() {
+			return fmt.Errorf("%s `%s` has key '%s' that is not listed in 'override'", item.Kind, item.Name(), key)
+		}
+		if err := mergeRulesFileItemKey(prev.rulesFileItem, item, key, mode == "append"); err != nil {
+			return fmt.Errorf("%s `%s`: %s", item.Kind, item.Name(), err.Error())
+		}
+	}
+	return nil
+}
+
+func copyRulesFileItem(item *rulesFileItem) *rulesFileItem {
+	res := *item
+	res.Items = append([]string{}, item.Items...)
+	res.Tags = append([]string{}, item.Tags...)
+	res.Exceptions = append([]ruleException{}, item.Exceptions...)
+	res.Keys = make(map[string]bool)
+	for k, v := range item.Keys {
+		res.Keys[k] = v
+	}
+	return &res
+}
+
+func mergeRulesFileItemKey(dst, src *rulesFileItem, key string, isAppend bool) error {
+	dst.Keys[key] = true
+	switch key {
+	case "condition":
+		if isAppend {
+			dst.Cond = dst.Cond.Append(src.Cond)
+		} else {
+			dst.Cond = src.Cond
+		}
+		dst.Condition = dst.Cond.Text
+	case "items":
+		if isAppend {
+			dst.Items = append(dst.Items, src.Items...)
+		} else {
+			dst.Items = append([]string{}, src.Items...)
+		}
+	case "output":
+		if isAppend {
+			dst.Output = dst.Output + " " + src.Output
+		} else {
+			dst.Output = src.Output
+		}
+	case "desc":
+		if isAppend {
+			dst.Desc = dst.Desc + " " + src.Desc
+		} else {
+			dst.Desc = src.Desc
+		}
+	case "tags":
+		if isAppend {
+			dst.Tags = append(dst.Tags, src.Tags...)
+		} else {
+			dst.Tags = append([]string{}, src.Tags...)
+		}
+	case "exceptions":
+		if isAppend {
+			dst.Exceptions = appendRuleExceptions(dst.Exceptions, src.Exceptions)
+		} else {
+			dst.Exceptions = append([]ruleException{}, src.Exceptions...)
+		}
+	case "priority":
+		dst.Priority = src.Priority
+	case "enabled":
+		dst.Enabled = src.Enabled
+	case "source":
+		dst.Source = src.Source
+	default:
+		if isAppend {
+			return fmt.Errorf("key '%s' cannot be appended", key)
+		}
+	}
+	return nil
+}
+
+// appendRuleExceptions appends exceptions, merging the values of the ones
+// with a name already defined.
+func appendRuleExceptions(dst, src []ruleException) []ruleException {
+	res := append([]ruleException{}, dst...)
+	for _, s := range src {
+		found := false
+		for i := range res {
+			if res[i].Name == s.Name {
+				found = true
+				l, _ := res[i].Values.([]interface{})
+				r, _ := s.Values.([]interface{})
+				res[i].Values = append(append([]interface{}{}, l...), r...)
+			}
+		}
+		if !found {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// ParseCondition parses the condition of a macro or rule.
+func (e *rulesetEntry) ParseCondition() (condExpr, error) {
+	c, err := parseCondition(e.Cond.Text)
+	if err != nil {
+		return nil, fmt.Errorf("%s `%s` has an invalid condition: %s", e.Kind, e.Name(), err.Error())
+	}
+	return c, nil
+}
+
+// MacroLookup returns a function that parses and returns the condition of a
+// macro given its name, which can be used with expandCondition.
+func (r *ruleset) MacroLookup() func(string) (condExpr, error) {
+	return func(name string) (condExpr, error) {
+		m := r.Macro(name)
+		if m == nil {
+			return nil, fmt.Errorf("undefined macro `%s`", name)
+		}
+		return m.ParseCondition()
+	}
+}
+
+// ListLookup returns a function that returns the items of a list given its
+// name, which can be used with expandCondition.
+func (r *ruleset) ListLookup() func(string) ([]string, bool) {
+	return func(name string) ([]string, bool) {
+		l := r.List(name)
+		if l == nil {
+			return nil, false
+		}
+		return l.Items, true
+	}
+}
+
+// ExpandCondition parses the condition of a macro or rule and returns it
+// with all its macros and lists inlined.
+func (r *ruleset) ExpandCondition(e *rulesetEntry) (condExpr, error) {
+	c, err := e.ParseCondition()
+	if err != nil {
+		return nil, err
+	}
+	res, err := expandCondition(c, r.MacroLookup(), r.ListLookup())
+	if err != nil {
+		return nil, fmt.Errorf("%s `%s`: %s", e.Kind, e.Name(), err.Error())
+	}
+	return res, nil
+}
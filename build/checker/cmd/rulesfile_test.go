@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleRulesFile = `
+- required_engine_version: 0.57.0
+
+- required_plugin_versions:
+    - name: container
+      version: 0.4.0
+
+- list: shell_binaries
+  items: [bash, sh]
+
+- macro: spawned_process
+  condition: (evt.type in (execve, execveat))
+
+- macro: shell_procs
+  condition: proc.name in (shell_binaries)
+
+- rule: Run shell
+  desc: A shell is spawned
+  condition: >
+    spawned_process
+    and shell_procs
+  output: Shell spawned (user=%user.name command=%proc.cmdline)
+  priority: WARNING
+  tags: [maturity_stable, host, container, process, T1059]
+`
+
+const sampleOverridesFile = `
+- list: shell_binaries
+  items: [zsh]
+  override:
+    items: append
+
+- macro: shell_procs
+  condition: or proc.name = ash
+  override:
+    condition: append
+
+- rule: Run shell
+  enabled: false
+
+- rule: Run shell
+  priority: ERROR
+  override:
+    priority: replace
+`
+
+func testLoadSampleRuleset(t *testing.T, withOverrides bool) *ruleset {
+	rs := newRuleset()
+	f, err := parseRulesFile("rules.yaml", []byte(sampleRulesFile))
+	require.NoError(t, err)
+	require.NoError(t, rs.Add(f))
+	if withOverrides {
+		f, err = parseRulesFile("overrides.yaml", []byte(sampleOverridesFile))
+		require.NoError(t, err)
+		require.NoError(t, rs.Add(f))
+	}
+	return rs
+}
+
+func TestLoadRuleset(t *testing.T) {
+	t.Parallel()
+
+	t.Run("definitions", func(t *testing.T) {
+		t.Parallel()
+		rs := testLoadSampleRuleset(t, false)
+		assert.Equal(t, "0.57.0", rs.RequiredEngineVersion)
+		require.Len(t, rs.RequiredPluginVersions, 1)
+		assert.Equal(t, "container", rs.RequiredPluginVersions[0].Name)
+		assert.Len(t, rs.Lists, 1)
+		assert.Len(t, rs.Macros, 2)
+		require.Len(t, rs.Rules, 1)
+
+		r := rs.Rule("Run shell")
+		require.NotNil(t, r)
+		assert.True(t, r.IsEnabled())
+		assert.Equal(t, "syscall", r.RuleSource())
+		assert.Equal(t, srcLoc{File: "rules.yaml", Line: 17}, r.Loc)
+		assert.Equal(t, 20, r.Cond.LocAt(0).Line)
+		assert.Equal(t, 21, r.Cond.LocAt(len("spawned_process\nand")).Line)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		t.Parallel()
+		rs := testLoadSampleRuleset(t, true)
+		assert.Equal(t, []string{"bash", "sh", "zsh"}, rs.List("shell_binaries").Items)
+
+		m := rs.Macro("shell_procs")
+		assert.Equal(t, "proc.name in (shell_binaries) or proc.name = ash", m.Condition)
+		assert.Len(t, m.Origins, 2)
+		assert.Equal(t, "overrides.yaml", m.Cond.LocAt(len(m.Condition)-1).File)
+
+		r := rs.Rule("Run shell")
+		assert.False(t, r.IsEnabled())
+		assert.Equal(t, "ERROR", r.Priority)
+		assert.Len(t, r.Origins, 3)
+
+		c, err := rs.ExpandCondition(r)
+		require.NoError(t, err)
+		assert.Equal(t, "evt.type in (execve, execveat) and (proc.name in (bash, sh, zsh) or proc.name=ash)", c.String())
+	})
+
+	t.Run("invalid-overrides", func(t *testing.T) {
+		t.Parallel()
+		rs := newRuleset()
+		f, err := parseRulesFile("overrides.yaml", []byte(sampleOverridesFile))
+		require.NoError(t, err)
+		assert.Error(t, rs.Add(f))
+
+		rs = testLoadSampleRuleset(t, false)
+		f, err = parseRulesFile("overrides.yaml", []byte("- rule: Run shell\n  priority: ERROR\n  override:\n    desc: replace\n"))
+		require.NoError(t, err)
+		assert.Error(t, rs.Add(f))
+	})
+
+	t.Run("invalid-files", func(t *testing.T) {
+		t.Parallel()
+		for _, s := range []string{"rule: abc", "- abc", "- unknown: abc", "- rule: [abc"} {
+			_, err := parseRulesFile("rules.yaml", []byte(s))
+			assert.Error(t, err, s)
+		}
+	})
+}
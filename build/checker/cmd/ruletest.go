@@ -0,0 +1,505 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/falcosecurity/testing/pkg/falco"
+	"github.com/falcosecurity/testing/pkg/run"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultRuleTestsPath is the directory containing the rule test specs.
+const defaultRuleTestsPath = "tests"
+
+// ruleTestAlert is an expectation on the alerts produced in a test case.
+type ruleTestAlert struct {
+	// Rule defaults to the rule of the test spec
+	Rule     string `yaml:"rule"`
+	Priority string `yaml:"priority"`
+	// Count is the exact number of matching alerts, or at least one if nil
+	Count *int `yaml:"count"`
+	// OutputContains are substrings that the output of the matching alerts
+	// must contain
+	OutputContains []string `yaml:"output_contains"`
+}
+
+// ruleTestCase is a test case of a rule, running either a capture file with
+// Falco or a sequence of event fixtures with the native evaluator. A test
+// case without expected alerts expects no alerts of the rule under test.
+type ruleTestCase struct {
+	Name string `yaml:"name"`
+	// Capture is the path of a capture file, relative to the test spec
+	Capture string `yaml:"capture"`
+	// Events are event fixtures, evaluated in order
+	Events []condEvent `yaml:"events"`
+	// EventsFile is the path of a JSON or YAML file containing a list of
+	// event fixtures, relative to the test spec
+	EventsFile string          `yaml:"events_file"`
+	Alerts     []ruleTestAlert `yaml:"alerts"`
+}
+
+// IsNegative returns true if a test case expects the rule under test not to
+// produce any alert.
+func (c *ruleTestCase) IsNegative(rule string) bool {
+	for _, a := range c.Alerts {
+		if (len(a.Rule) == 0 || a.Rule == rule) && (a.Count == nil || *a.Count > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleTestSpec contains the test cases of a rule.
+type ruleTestSpec struct {
+	Rule  string         `yaml:"rule"`
+	Cases []ruleTestCase `yaml:"cases"`
+	// Path is the path of the test spec file
+	Path string `yaml:"-"`
+}
+
+func loadRuleTestSpec(path string) (*ruleTestSpec, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res ruleTestSpec
+	if err := yaml.Unmarshal(content, &res); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err.Error())
+	}
+	res.Path = path
+	if len(res.Rule) == 0 {
+		return nil, fmt.Errorf("%s: test spec has no rule", path)
+	}
+	for i := range res.Cases {
+		c := &res.Cases[i]
+		if len(c.Name) == 0 {
+			c.Name = fmt.Sprintf("case #%d", i+1)
+		}
+		if len(c.Capture) > 0 && !filepath.IsAbs(c.Capture) {
+			c.Capture = filepath.Join(filepath.Dir(path), c.Capture)
+		}
+		if len(c.EventsFile) > 0 {
+			if !filepath.IsAbs(c.EventsFile) {
+				c.EventsFile = filepath.Join(filepath.Dir(path), c.EventsFile)
+			}
+			content, err := os.ReadFile(c.EventsFile)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", path, err.Error())
+			}
+			var events []condEvent
+			if err := yaml.Unmarshal(content, &events); err != nil {
+				return nil, fmt.Errorf("%s: %s", c.EventsFile, err.Error())
+			}
+			c.Events = append(c.Events, events...)
+		}
+		if (len(c.Capture) > 0) == (len(c.Events) > 0) {
+			return nil, fmt.Errorf("%s: test case '%s' must have either a capture file or events", path, c.Name)
+		}
+	}
+	return &res, nil
+}
+
+// loadRuleTestSpecs loads the test specs from the given files and from all
+// the YAML files contained in the given directories.
+func loadRuleTestSpecs(paths ...string) ([]*ruleTestSpec, error) {
+	var res []*ruleTestSpec
+	for _, p := range paths {
+		err := filepath.Walk(p, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() || (path != p && !strings.HasSuffix(path, ".yaml") && !strings.HasSuffix(path, ".yml")) {
+				return nil
+			}
+			spec, err := loadRuleTestSpec(path)
+			if err != nil {
+				return err
+			}
+			res = append(res, spec)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// checkRuleTestAlerts returns the failures of a test case given the alerts
+// produced by running it.
+func checkRuleTestAlerts(rule string, c *ruleTestCase, alerts falco.Detections) []string {
+	var res []string
+	expected := c.Alerts
+	if len(expected) == 0 {
+		zero := 0
+		expected = []ruleTestAlert{{Rule: rule, Count: &zero}}
+	}
+	for _, exp := range expected {
+		name := exp.Rule
+		if len(name) == 0 {
+			name = rule
+		}
+		count := 0
+		for _, a := range alerts {
+			if a.Rule != name || (len(exp.Priority) > 0 && compareFalcoPriorities(a.Priority, exp.Priority) != 0) {
+				continue
+			}
+			matches := true
+			for _, s := range exp.OutputContains {
+				matches = matches && strings.Contains(a.Output, s)
+			}
+			if matches {
+				count++
+			}
+		}
+
+		desc := fmt.Sprintf("alerts of rule `%s`", name)
+		if len(exp.Priority) > 0 {
+			desc += " with priority " + exp.Priority
+		}
+		if len(exp.OutputContains) > 0 {
+			desc += fmt.Sprintf(" with output containing %s", strings.Join(exp.OutputContains, ", "))
+		}
+		if exp.Count == nil && count == 0 {
+			res = append(res, fmt.Sprintf("expected at least one of the %s, got none", desc))
+		} else if exp.Count != nil && *exp.Count != count {
+			res = append(res, fmt.Sprintf("expected %d %s, got %d", *exp.Count, desc, count))
+		}
+	}
+	return res
+}
+
+// errRuleTestSkipped is returned by test runners that skip a test case.
+var errRuleTestSkipped = errors.New("skipped")
+
+// ruleTestResult is the outcome of running a test case.
+type ruleTestResult struct {
+	Spec     *ruleTestSpec
+	Case     *ruleTestCase
+	Failures []string
+	Skipped  bool
+	Duration time.Duration
+}
+
+// Passed returns true if the test case had no failures.
+func (r *ruleTestResult) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// ruleTestRunner runs a test case and returns the alerts it produced.
+type ruleTestRunner func(spec *ruleTestSpec, c *ruleTestCase) (falco.Detections, error)
+
+// runRuleTests runs all the test cases of the given specs.
+func runRuleTests(specs []*ruleTestSpec, runner ruleTestRunner) []*ruleTestResult {
+	var res []*ruleTestResult
+	for _, s := range specs {
+		for i := range s.Cases {
+			c := &s.Cases[i]
+			start := time.Now()
+			r := &ruleTestResult{Spec: s, Case: c}
+			alerts, err := runner(s, c)
+			if err == errRuleTestSkipped {
+				r.Skipped = true
+			} else if err != nil {
+				r.Failures = append(r.Failures, err.Error())
+			} else {
+				r.Failures = checkRuleTestAlerts(s.Rule, c, alerts)
+			}
+			r.Duration = time.Since(start)
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+// newNativeRuleTestRunner returns a test runner evaluating the event
+// fixtures of each test case with the native evaluator, with the rule under
+// test enabled. Test cases with capture files are run with the fallback
+// runner, or skipped if it is nil.
+func newNativeRuleTestRunner(rs *ruleset, fallback ruleTestRunner) (ruleTestRunner, error) {
+	rules, err := compileRules(rs)
+	if err != nil {
+		return nil, err
+	}
+	x := newCondEvaluator()
+	return func(spec *ruleTestSpec, c *ruleTestCase) (falco.Detections, error) {
+		if len(c.Events) == 0 {
+			if fallback == nil {
+				return nil, errRuleTestSkipped
+			}
+			return fallback(spec, c)
+		}
+		return x.EvalRules(rules, c.Events, spec.Rule)
+	}, nil
+}
+
+// newFalcoRuleTestRunner returns a test runner replaying the capture file
+// of each test case with Falco, with the rule under test enabled.
+func newFalcoRuleTestRunner(falcoImage, falcoConfigPath string, rulesFilesPaths, falcoFilesPaths []string) ruleTestRunner {
+	return func(spec *ruleTestSpec, c *ruleTestCase) (falco.Detections, error) {
+		if len(c.Capture) == 0 {
+			return nil, fmt.Errorf("test case has no capture file")
+		}
+
+		options := []falco.TestOption{
+			falco.WithOutputJSON(),
+			falco.WithCaptureFile(run.NewLocalFileAccessor(c.Capture, c.Capture)),
+			falco.WithArgs("-o", "rules[].enable.rule="+spec.Rule),
+		}
+		for _, rf := range rulesFilesPaths {
+			options = append(options, falco.WithRules(run.NewLocalFileAccessor(rf, rf)))
+		}
+		if len(falcoConfigPath) > 0 {
+			options = append(options, falco.WithConfig(run.NewLocalFileAccessor(falcoConfigPath, falcoConfigPath)))
+		}
+		for _, path := range falcoFilesPaths {
+			options = append(options, falco.WithExtraFiles(run.NewLocalFileAccessor(path, path)))
+		}
+
+		runner, err := run.NewDockerRunner(falcoImage, defaultFalcoDockerEntrypoint, nil)
+		if err != nil {
+			return nil, err
+		}
+		res := falco.Test(runner, options...)
+		err = res.Err()
+		if res.ExitCode() != 0 {
+			err = errAppend(err, fmt.Errorf("unexpected exit code (%d)", res.ExitCode()))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("running falco: %s", err.Error())
+		}
+		return res.Detections(), nil
+	}
+}
+
+func writeRuleTestResults(w io.Writer, results []*ruleTestResult) {
+	var spec *ruleTestSpec
+	passed, skipped := 0, 0
+	for _, r := range results {
+		if r.Spec != spec {
+			if spec != nil {
+				fmt.Fprintln(w)
+			}
+			spec = r.Spec
+			fmt.Fprintf(w, "## %s (%s)\n\n", spec.Rule, spec.Path)
+		}
+		status := "PASS"
+		switch {
+		case r.Skipped:
+			status = "SKIP"
+			skipped++
+		case r.Passed():
+			passed++
+		default:
+			status = "FAIL"
+		}
+		fmt.Fprintf(w, "* %s %s\n", status, r.Case.Name)
+		for _, f := range r.Failures {
+			fmt.Fprintf(w, "  * %s\n", f)
+		}
+	}
+	if spec != nil {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "%d passed, %d failed, %d skipped\n", passed, len(results)-passed-skipped, skipped)
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct{}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+// writeRuleTestResultsJUnit writes the test results in the JUnit XML format,
+// with a test suite for each rule.
+func writeRuleTestResultsJUnit(w io.Writer, results []*ruleTestResult) error {
+	var res junitTestSuites
+	var durations []time.Duration
+	for _, r := range results {
+		if len(res.Suites) == 0 || res.Suites[len(res.Suites)-1].Name != r.Spec.Rule {
+			res.Suites = append(res.Suites, junitTestSuite{Name: r.Spec.Rule})
+			durations = append(durations, 0)
+		}
+		s := &res.Suites[len(res.Suites)-1]
+		tc := junitTestCase{
+			Name:      r.Case.Name,
+			ClassName: r.Spec.Rule,
+			Time:      fmt.Sprintf("%.3f", r.Duration.Seconds()),
+		}
+		if r.Skipped {
+			tc.Skipped = &junitSkipped{}
+			s.Skipped++
+		} else if !r.Passed() {
+			tc.Failure = &junitFailure{Message: r.Failures[0], Text: strings.Join(r.Failures, "\n")}
+			s.Failures++
+		}
+		s.Tests++
+		s.TestCases = append(s.TestCases, tc)
+		durations[len(durations)-1] += r.Duration
+	}
+	for i := range res.Suites {
+		res.Suites[i].Time = fmt.Sprintf("%.3f", durations[i].Seconds())
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(res); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+var ruleTestCmd = &cobra.Command{
+	Use:   "test [flags] [test specs files or directories...]",
+	Short: "Test that rules produce the expected alerts on capture files run with Falco, or on event fixtures",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		falcoImage, err := cmd.Flags().GetString("falco-image")
+		if err != nil {
+			return err
+		}
+
+		falcoConfigPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		falcoFilesPaths, err := cmd.Flags().GetStringArray("file")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		nativeOnly, err := cmd.Flags().GetBool("native-only")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err = getRulesFilesPaths(rulesFilesPaths, registryPath)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			args = []string{defaultRuleTestsPath}
+		}
+		specs, err := loadRuleTestSpecs(args...)
+		if err != nil {
+			return err
+		}
+
+		rs, err := loadRuleset(rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+		for _, s := range specs {
+			if rs.Rule(s.Rule) == nil {
+				return fmt.Errorf("%s: no rule found with name `%s`", s.Path, s.Rule)
+			}
+		}
+
+		var fallback ruleTestRunner
+		if !nativeOnly {
+			fallback = newFalcoRuleTestRunner(falcoImage, falcoConfigPath, rulesFilesPaths, falcoFilesPaths)
+		}
+		runner, err := newNativeRuleTestRunner(rs, fallback)
+		if err != nil {
+			return err
+		}
+		results := runRuleTests(specs, runner)
+
+		switch format {
+		case "text":
+			writeRuleTestResults(cmd.OutOrStdout(), results)
+		case "junit":
+			if err := writeRuleTestResultsJUnit(cmd.OutOrStdout(), results); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+
+		for _, r := range results {
+			if !r.Passed() {
+				err = errAppend(err, fmt.Errorf("rule `%s` failed test case '%s'", r.Spec.Rule, r.Case.Name))
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	ruleTestCmd.Flags().StringP("falco-image", "i", defaultFalcoDockerImage, "Docker image of Falco to be used for running the tests")
+	ruleTestCmd.Flags().StringP("config", "c", "", "Config file to be used for running Falco")
+	ruleTestCmd.Flags().StringArrayP("file", "f", []string{}, "Extra files required by Falco for running")
+	ruleTestCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	ruleTestCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	ruleTestCmd.Flags().StringP("output", "o", "text", "Output format, one of: text, junit")
+	ruleTestCmd.Flags().Bool("native-only", false, "Only run the test cases with event fixtures, skipping the ones requiring Falco")
+	rootCmd.AddCommand(ruleTestCmd)
+}
@@ -0,0 +1,237 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/falcosecurity/testing/pkg/falco"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleRuleTestSpec = `
+rule: Run shell untrusted
+cases:
+  - name: shell spawned by web server
+    capture: captures/shell.scap
+    alerts:
+      - count: 1
+        priority: NOTICE
+        output_contains: [proc.name=bash]
+  - name: shell spawned by ssh
+    capture: captures/ssh.scap
+  - capture: /tmp/other.scap
+    alerts:
+      - rule: Other rule
+`
+
+func TestLoadRuleTestSpecs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "shell"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shell", "run_shell.yaml"), []byte(sampleRuleTestSpec), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "shell", "README.md"), []byte("not a spec"), 0644))
+
+	specs, err := loadRuleTestSpecs(dir)
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	s := specs[0]
+	assert.Equal(t, "Run shell untrusted", s.Rule)
+	assert.Equal(t, filepath.Join(dir, "shell", "run_shell.yaml"), s.Path)
+	require.Len(t, s.Cases, 3)
+	assert.Equal(t, filepath.Join(dir, "shell", "captures", "shell.scap"), s.Cases[0].Capture)
+	assert.Equal(t, "/tmp/other.scap", s.Cases[2].Capture)
+	assert.Equal(t, "case #3", s.Cases[2].Name)
+	assert.False(t, s.Cases[0].IsNegative(s.Rule))
+	assert.True(t, s.Cases[1].IsNegative(s.Rule))
+	assert.True(t, s.Cases[2].IsNegative(s.Rule))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("cases: []"), 0644))
+	_, err = loadRuleTestSpecs(dir)
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("rule: test\ncases: [{name: empty}]"), 0644))
+	_, err = loadRuleTestSpecs(dir)
+	assert.ErrorContains(t, err, "test case 'empty' must have either a capture file or events")
+}
+
+func TestLoadRuleTestSpecEventsFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	spec := `
+rule: test
+cases:
+  - name: from file
+    events_file: fixtures/events.json
+    events:
+      - proc.name: inline
+`
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "fixtures"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.yaml"), []byte(spec), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "fixtures", "events.json"), []byte(`[{"proc.name": "bash", "proc.aname": ["sshd", "systemd"], "fd.num": 3}]`), 0644))
+
+	s, err := loadRuleTestSpec(filepath.Join(dir, "spec.yaml"))
+	require.NoError(t, err)
+	require.Len(t, s.Cases, 1)
+	require.Len(t, s.Cases[0].Events, 2)
+	assert.Equal(t, "inline", s.Cases[0].Events[0]["proc.name"])
+	assert.Equal(t, "bash", s.Cases[0].Events[1]["proc.name"])
+	assert.Equal(t, []interface{}{"sshd", "systemd"}, s.Cases[0].Events[1]["proc.aname"])
+}
+
+func TestNativeRuleTestRunner(t *testing.T) {
+	t.Parallel()
+	files := testParseRulesFiles(t, `
+- macro: spawned_process
+  condition: evt.type = execve
+
+- rule: shell_spawned
+  desc: test
+  condition: spawned_process and proc.name in (bash, sh)
+  output: shell spawned (proc.name=%proc.name parent=%proc.pname)
+  priority: NOTICE
+
+- rule: disabled_rule
+  desc: test
+  condition: spawned_process
+  output: disabled
+  priority: INFO
+  enabled: false
+`)
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+	runner, err := newNativeRuleTestRunner(rs, nil)
+	require.NoError(t, err)
+
+	zero := 0
+	specs := []*ruleTestSpec{
+		{Rule: "shell_spawned", Cases: []ruleTestCase{
+			{Name: "positive", Events: []condEvent{{"evt.type": "execve", "proc.name": "bash"}}, Alerts: []ruleTestAlert{
+				{OutputContains: []string{"proc.name=bash parent=<NA>"}},
+				{Rule: "disabled_rule", Count: &zero},
+			}},
+			{Name: "negative", Events: []condEvent{{"evt.type": "execve", "proc.name": "ls"}}},
+			{Name: "capture", Capture: "capture.scap"},
+		}},
+		{Rule: "disabled_rule", Cases: []ruleTestCase{
+			{Name: "enabled when under test", Events: []condEvent{{"evt.type": "execve"}}, Alerts: []ruleTestAlert{{}}},
+		}},
+	}
+	results := runRuleTests(specs, runner)
+	require.Len(t, results, 4)
+	assert.True(t, results[0].Passed(), results[0].Failures)
+	assert.True(t, results[1].Passed(), results[1].Failures)
+	assert.True(t, results[2].Skipped)
+	assert.True(t, results[3].Passed(), results[3].Failures)
+
+	var buf bytes.Buffer
+	writeRuleTestResults(&buf, results)
+	assert.Contains(t, buf.String(), "* SKIP capture\n")
+	assert.Contains(t, buf.String(), "3 passed, 0 failed, 1 skipped\n")
+
+	buf.Reset()
+	for _, r := range results {
+		r.Duration = 0
+	}
+	require.NoError(t, writeRuleTestResultsJUnit(&buf, results))
+	assert.Contains(t, buf.String(), `<testcase name="capture" classname="shell_spawned" time="0.000">`+"\n      <skipped></skipped>")
+}
+
+func TestCheckRuleTestAlerts(t *testing.T) {
+	t.Parallel()
+	one := 1
+	alerts := falco.Detections{
+		{Rule: "test", Priority: "Notice", Output: "shell spawned (proc.name=bash)"},
+		{Rule: "test", Priority: "Notice", Output: "shell spawned (proc.name=sh)"},
+		{Rule: "other", Priority: "Warning", Output: "other"},
+	}
+
+	c := &ruleTestCase{Alerts: []ruleTestAlert{{Count: &one, Priority: "NOTICE", OutputContains: []string{"proc.name=bash"}}}}
+	assert.Empty(t, checkRuleTestAlerts("test", c, alerts))
+
+	c = &ruleTestCase{Alerts: []ruleTestAlert{{Count: &one}, {Rule: "missing"}, {Rule: "other", Priority: "ERROR"}}}
+	assert.Equal(t, []string{
+		"expected 1 alerts of rule `test`, got 2",
+		"expected at least one of the alerts of rule `missing`, got none",
+		"expected at least one of the alerts of rule `other` with priority ERROR, got none",
+	}, checkRuleTestAlerts("test", c, alerts))
+
+	c = &ruleTestCase{}
+	assert.Equal(t, []string{"expected 0 alerts of rule `test`, got 2"}, checkRuleTestAlerts("test", c, alerts))
+	assert.Empty(t, checkRuleTestAlerts("missing", c, alerts))
+}
+
+func TestRunRuleTests(t *testing.T) {
+	t.Parallel()
+	specs := []*ruleTestSpec{
+		{Rule: "test", Path: "tests/test.yaml", Cases: []ruleTestCase{
+			{Name: "positive", Capture: "positive.scap", Alerts: []ruleTestAlert{{}}},
+			{Name: "negative", Capture: "negative.scap"},
+			{Name: "broken", Capture: "broken.scap"},
+		}},
+	}
+	runner := func(s *ruleTestSpec, c *ruleTestCase) (falco.Detections, error) {
+		switch c.Capture {
+		case "positive.scap", "negative.scap":
+			return falco.Detections{{Rule: s.Rule, Output: "output"}}, nil
+		}
+		return nil, errors.New("capture not found")
+	}
+	results := runRuleTests(specs, runner)
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Passed())
+	assert.False(t, results[1].Passed())
+	assert.Equal(t, []string{"capture not found"}, results[2].Failures)
+
+	var buf bytes.Buffer
+	writeRuleTestResults(&buf, results)
+	assert.Equal(t, `## test (tests/test.yaml)
+
+* PASS positive
+* FAIL negative
+  * expected 0 alerts of rule `+"`test`"+`, got 1
+* FAIL broken
+  * capture not found
+
+1 passed, 2 failed, 0 skipped
+`, buf.String())
+
+	buf.Reset()
+	for _, r := range results {
+		r.Duration = 0
+	}
+	require.NoError(t, writeRuleTestResultsJUnit(&buf, results))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="test" tests="3" failures="2" skipped="0" time="0.000">
+    <testcase name="positive" classname="test" time="0.000"></testcase>
+    <testcase name="negative" classname="test" time="0.000">
+      <failure message="expected 0 alerts of rule `+"`test`"+`, got 1">expected 0 alerts of rule `+"`test`"+`, got 1</failure>
+    </testcase>
+    <testcase name="broken" classname="test" time="0.000">
+      <failure message="capture not found">capture not found</failure>
+    </testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/falcosecurity/testing/pkg/falco"
+	"gopkg.in/yaml.v3"
+)
+
+// ruleTestCaseKey returns a string identifying the content of a test case,
+// independently of the directory from which its test spec was loaded. Capture
+// files are identified by their content, so that updating one is considered
+// as updating the test case.
+func ruleTestCaseKey(spec *ruleTestSpec, c *ruleTestCase) string {
+	tmp := *c
+	tmp.EventsFile = ""
+	if len(tmp.Capture) > 0 {
+		if content, err := os.ReadFile(tmp.Capture); err == nil {
+			sum := sha256.Sum256(content)
+			tmp.Capture = hex.EncodeToString(sum[:])
+		} else if rel, err := filepath.Rel(filepath.Dir(spec.Path), tmp.Capture); err == nil {
+			tmp.Capture = rel
+		}
+	}
+	out, err := yaml.Marshal(&tmp)
+	if err != nil {
+		// should never happen, fallback to a key that is never shared
+		return fmt.Sprintf("%p", c)
+	}
+	return string(out)
+}
+
+// newRuleTestCases returns the test cases of each rule in the given test specs
+// that are not present with the same content in the base test specs.
+func newRuleTestCases(specs, baseSpecs []*ruleTestSpec) map[string][]*ruleTestCase {
+	base := make(map[string]bool)
+	for _, s := range baseSpecs {
+		for i := range s.Cases {
+			base[s.Rule+"\n"+ruleTestCaseKey(s, &s.Cases[i])] = true
+		}
+	}
+	res := make(map[string][]*ruleTestCase)
+	for _, s := range specs {
+		for i := range s.Cases {
+			if !base[s.Rule+"\n"+ruleTestCaseKey(s, &s.Cases[i])] {
+				res[s.Rule] = append(res[s.Rule], &s.Cases[i])
+			}
+		}
+	}
+	return res
+}
+
+// normalizeCondition collapses the whitespace of a condition, so that
+// reformatting it is not considered as a change.
+func normalizeCondition(c string) string {
+	return strings.Join(strings.Fields(c), " ")
+}
+
+// expandedRuleConditions returns the conditions of the rules of a ruleset
+// description with all their macros and lists inlined, so that changing a
+// macro or list used by a rule is considered as a change of the rule
+// condition. Conditions that can't be expanded are only normalized.
+func expandedRuleConditions(desc *falco.RulesetDescription) map[string]string {
+	macros := make(map[string]string)
+	for _, m := range desc.Macros {
+		macros[m.Info.Name] = m.Info.Condition
+	}
+	lists := make(map[string][]string)
+	for _, l := range desc.Lists {
+		lists[l.Info.Name] = l.Info.Items
+	}
+	macro := func(name string) (condExpr, error) {
+		c, ok := macros[name]
+		if !ok {
+			return nil, fmt.Errorf("undefined macro `%s`", name)
+		}
+		return parseCondition(c)
+	}
+	list := func(name string) ([]string, bool) {
+		items, ok := lists[name]
+		return items, ok
+	}
+
+	res := make(map[string]string)
+	for _, r := range desc.Rules {
+		res[r.Info.Name] = normalizeCondition(r.Info.Condition)
+		c, err := parseCondition(r.Info.Condition)
+		if err != nil {
+			continue
+		}
+		if c, err = expandCondition(c, macro, list); err == nil {
+			res[r.Info.Name] = c.String()
+		}
+	}
+	return res
+}
+
+// compareRulesTests returns the rules that have been added or that had their
+// condition changed, including through the macros and lists it uses,
+// without adding or updating at least one positive and one negative test
+// case. Rules in the untested set are ignored.
+func compareRulesTests(left, right *falco.RulesetDescription, specs, baseSpecs []*ruleTestSpec, untested map[string]bool) (res []string) {
+	leftConds := expandedRuleConditions(left)
+	rightConds := expandedRuleConditions(right)
+	cases := newRuleTestCases(specs, baseSpecs)
+	for _, r := range right.Rules {
+		name := r.Info.Name
+		if untested[name] {
+			continue
+		}
+		change := "has been added"
+		if cond, ok := leftConds[name]; ok {
+			if cond == rightConds[name] {
+				continue
+			}
+			change = "changed its condition"
+		}
+		positive, negative := false, false
+		for _, c := range cases[name] {
+			if c.IsNegative(name) {
+				negative = true
+			} else {
+				positive = true
+			}
+		}
+		var missing []string
+		if !positive {
+			missing = append(missing, "positive")
+		}
+		if !negative {
+			missing = append(missing, "negative")
+		}
+		if len(missing) > 0 {
+			res = append(res, fmt.Sprintf("Rule `%s` %s without new or updated %s test cases", name, change, strings.Join(missing, " and ")))
+		}
+	}
+	return
+}
+
+// untestedRuleNames returns the names of the rules defined in the given rules
+// files whose base name is in the allowlist.
+func untestedRuleNames(paths, allowlist []string) (map[string]bool, error) {
+	res := make(map[string]bool)
+	for _, p := range paths {
+		if !strSliceContains(allowlist, filepath.Base(p)) {
+			continue
+		}
+		f, err := loadRulesFile(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range f.Items {
+			if item.Kind == itemKindRule {
+				res[item.Rule] = true
+			}
+		}
+	}
+	return res, nil
+}
+
+// loadExistingRuleTestSpecs is like loadRuleTestSpecs, but ignores the paths
+// that don't exist, such as the tests directory of older versions.
+func loadExistingRuleTestSpecs(paths ...string) ([]*ruleTestSpec, error) {
+	var existing []string
+	for _, p := range paths {
+		if _, err := os.Stat(p); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		existing = append(existing, p)
+	}
+	return loadRuleTestSpecs(existing...)
+}
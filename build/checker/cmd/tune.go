@@ -0,0 +1,542 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/falcosecurity/testing/pkg/falco"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultTuneFields are the fields used to build a new exception for the
+// rules of the syscall source when none is specified, if present in the
+// alerts.
+var defaultTuneFields = []string{"container.image.repository", "proc.name", "proc.pname"}
+
+var tuneNameRegex = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// tuneException is an exception of a generated override item.
+type tuneException struct {
+	Name   string        `yaml:"name"`
+	Fields interface{}   `yaml:"fields,omitempty"`
+	Comps  interface{}   `yaml:"comps,omitempty"`
+	Values []interface{} `yaml:"values"`
+}
+
+// tuneOverride is a generated override item that suppresses the alerts of a
+// rule, either by appending values to an exception of the rule, by
+// defining a new exception, or by appending items to a list.
+type tuneOverride struct {
+	Rule       string            `yaml:"rule,omitempty"`
+	List       string            `yaml:"list,omitempty"`
+	Items      []string          `yaml:"items,omitempty"`
+	Exceptions []tuneException   `yaml:"exceptions,omitempty"`
+	Override   map[string]string `yaml:"override"`
+	// Summary describes the override
+	Summary string `yaml:"-"`
+}
+
+// loadTuneAlerts reads the alerts of a file containing the JSON output of
+// Falco, either one alert per line or a JSON array of alerts. Lines that
+// are not JSON objects are ignored.
+func loadTuneAlerts(path string) (falco.Detections, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var res falco.Detections
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &res); err != nil {
+			return nil, fmt.Errorf("%s: %s", path, err.Error())
+		}
+		return res, nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(nil, len(content)+1)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 || text[0] != '{' {
+			continue
+		}
+		var a falco.Alert
+		if err := json.Unmarshal(text, &a); err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", path, line, err.Error())
+		}
+		res = append(res, &a)
+	}
+	return res, scanner.Err()
+}
+
+// tuneValueText returns a value as a quoted string of a condition.
+func tuneValueText(v string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(v, `\`, `\\`), `"`, `\"`) + `"`
+}
+
+// tuneListItem returns a value as a list item, quoted if it contains
+// characters that would split it in a condition.
+func tuneListItem(v string) string {
+	if strings.ContainsAny(v, " \t(),=<>!\"'") {
+		return tuneValueText(v)
+	}
+	return v
+}
+
+// tuneEventValues returns the values of the given fields in each event,
+// deduplicated, or false if some event has no value for a field.
+func tuneEventValues(events []condEvent, fields []string) ([][]string, bool) {
+	var res [][]string
+	seen := make(map[string]bool)
+	for _, evt := range events {
+		var tuple []string
+		for _, f := range fields {
+			v, ok := evt[f]
+			if !ok || v == nil {
+				return nil, false
+			}
+			if _, isList := v.([]interface{}); isList {
+				return nil, false
+			}
+			tuple = append(tuple, condEventValueString(v))
+		}
+		key := strings.Join(tuple, "\x00")
+		if !seen[key] {
+			seen[key] = true
+			res = append(res, tuple)
+		}
+	}
+	return res, true
+}
+
+// tuneExceptionOverride returns an override appending values to an exception
+// of a rule, with the exception definition included if it is new, and false
+// if the events have no values for its fields. Each event is checked to be
+// suppressed by the exception.
+func tuneExceptionOverride(rule string, e ruleException, isNew bool, events []condEvent) (*tuneOverride, bool, error) {
+	shape, issues := exceptionShapeOf(e)
+	if len(issues) > 0 {
+		return nil, false, fmt.Errorf("exception `%s` of rule `%s` %s", e.Name, rule, strings.Join(issues, ", "))
+	}
+	tuples, ok := tuneEventValues(events, shape.Fields)
+	if !ok {
+		return nil, false, nil
+	}
+
+	x := newCondEvaluator()
+	exc := tuneException{Name: e.Name}
+	if isNew {
+		exc.Fields = e.Fields
+		exc.Comps = e.Comps
+	}
+	for _, tuple := range tuples {
+		var checks []string
+		var value []interface{}
+		for i, v := range tuple {
+			if condListOperators[shape.Comps[i]] {
+				checks = append(checks, fmt.Sprintf("%s %s (%s)", shape.Fields[i], shape.Comps[i], tuneValueText(v)))
+				value = append(value, []interface{}{tuneListItem(v)})
+			} else {
+				checks = append(checks, fmt.Sprintf("%s %s %s", shape.Fields[i], shape.Comps[i], tuneValueText(v)))
+				value = append(value, v)
+			}
+		}
+		if shape.Single {
+			exc.Values = append(exc.Values, tuneListItem(tuple[0]))
+		} else {
+			exc.Values = append(exc.Values, value)
+		}
+
+		cond, err := parseCondition(strings.Join(checks, " and "))
+		if err != nil {
+			return nil, false, err
+		}
+		for _, evt := range events {
+			if vs, _ := tuneEventValues([]condEvent{evt}, shape.Fields); strings.Join(vs[0], "\x00") != strings.Join(tuple, "\x00") {
+				continue
+			}
+			if ok, err := x.Eval(cond, evt); err != nil || !ok {
+				return nil, false, err
+			}
+		}
+	}
+
+	summary := fmt.Sprintf("Appending values to exception `%s` of rule `%s`", e.Name, rule)
+	if isNew {
+		summary = fmt.Sprintf("Adding exception `%s` to rule `%s`", e.Name, rule)
+	}
+	return &tuneOverride{
+		Rule:       rule,
+		Exceptions: []tuneException{exc},
+		Override:   map[string]string{"exceptions": "append"},
+		Summary:    summary,
+	}, true, nil
+}
+
+// tuneListCheck is a check of a condition that compares a field with the
+// items of a list, and that makes the whole condition false when matching.
+type tuneListCheck struct {
+	List  string
+	Check *condCheckExpr
+}
+
+// suppressingListChecks returns the checks of a condition comparing a field
+// with a list such that the condition can't be true if the check is. Those
+// are the negated checks reachable through conjunctions, considering
+// disjunctions as conjunctions when negated.
+func suppressingListChecks(e condExpr, negated bool, macro func(string) (condExpr, error), isList func(string) bool, visiting []string) ([]tuneListCheck, error) {
+	var res []tuneListCheck
+	var exprs []condExpr
+	switch v := e.(type) {
+	case *condAndExpr:
+		if !negated {
+			exprs = v.Exprs
+		}
+	case *condOrExpr:
+		if negated {
+			exprs = v.Exprs
+		}
+	case *condNotExpr:
+		return suppressingListChecks(v.Expr, !negated, macro, isList, visiting)
+	case *condIdentExpr:
+		if strSliceContains(visiting, v.Name) {
+			return nil, fmt.Errorf("macro `%s` has a circular reference", v.Name)
+		}
+		m, err := macro(v.Name)
+		if err != nil {
+			return nil, err
+		}
+		return suppressingListChecks(m, negated, macro, isList, append(visiting, v.Name))
+	case *condCheckExpr:
+		if negated && (v.Op == "in" || v.Op == "pmatch") && len(v.Field.Transformer) == 0 {
+			for _, val := range v.Values {
+				if val.Quote == 0 && val.Field == nil && isList(val.Text) {
+					res = append(res, tuneListCheck{List: val.Text, Check: v})
+				}
+			}
+		}
+	}
+	for _, c := range exprs {
+		checks, err := suppressingListChecks(c, negated, macro, isList, visiting)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, checks...)
+	}
+	return res, nil
+}
+
+// tuneListPrefixes are the name prefixes of the lists meant to be extended
+// by users to allow known activity, such as user_known_* and allowed_* lists.
+var tuneListPrefixes = []string{"user_known_", "allowed_", "known_", "trusted_"}
+
+func isTuneList(name string) bool {
+	for _, p := range tuneListPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// tuneListOverride returns an override appending items to one of the lists
+// that suppress the alerts of a rule when containing the values of the
+// events, and false if none can be used. Only the lists meant for user
+// extension are considered, and not the ones used by other rules, as
+// appending to them would also affect those rules.
+func tuneListOverride(rs *ruleset, rule *rulesetEntry, events []condEvent) (*tuneOverride, bool, error) {
+	c, err := rule.ParseCondition()
+	if err != nil {
+		return nil, false, err
+	}
+	isList := func(name string) bool { return rs.List(name) != nil }
+	checks, err := suppressingListChecks(c, false, rs.MacroLookup(), isList, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("rule `%s`: %s", rule.Name(), err.Error())
+	}
+	g, err := buildDepGraph(rs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	x := newCondEvaluator()
+	ruleID := depGraphNodeID(itemKindRule, rule.Name())
+	for _, check := range checks {
+		if !isTuneList(check.List) {
+			continue
+		}
+		shared := false
+		for id := range g.reachable([]string{depGraphNodeID(itemKindList, check.List)}, true) {
+			if id != ruleID && g.Node(id).Kind == itemKindRule {
+				shared = true
+			}
+		}
+		tuples, ok := tuneEventValues(events, []string{check.Check.Field.FullName()})
+		if shared || !ok {
+			continue
+		}
+
+		var items []string
+		for _, tuple := range tuples {
+			items = append(items, tuneListItem(tuple[0]))
+		}
+		lookup := func(name string) ([]string, bool) {
+			if name == check.List {
+				return append(append([]string{}, rs.List(name).Items...), items...), true
+			}
+			return rs.ListLookup()(name)
+		}
+		expanded, err := expandCondition(check.Check, rs.MacroLookup(), lookup)
+		if err != nil {
+			return nil, false, err
+		}
+		suppressed := true
+		for _, evt := range events {
+			ok, err := x.Eval(expanded, evt)
+			if err != nil {
+				return nil, false, err
+			}
+			suppressed = suppressed && ok
+		}
+		if !suppressed {
+			continue
+		}
+		return &tuneOverride{
+			List:     check.List,
+			Items:    items,
+			Override: map[string]string{"items": "append"},
+			Summary:  fmt.Sprintf("Appending items to list `%s` used by rule `%s`", check.List, rule.Name()),
+		}, true, nil
+	}
+	return nil, false, nil
+}
+
+// tuneRule generates an override that suppresses the given alerts of a rule.
+// If no fields are specified, the existing exceptions of the rule are
+// preferred, then the lists meant for user extension used only by the rule,
+// and lastly a new exception on default fields. Otherwise, a new exception on
+// the given fields is added, unless the rule already has one with the same
+// fields.
+func tuneRule(rs *ruleset, rule string, alerts falco.Detections, fields []string) (*tuneOverride, error) {
+	entry := rs.Rule(rule)
+	if entry == nil {
+		return nil, fmt.Errorf("no rule found with name `%s`", rule)
+	}
+	var events []condEvent
+	for _, a := range alerts {
+		if a.Rule == rule {
+			events = append(events, condEvent(a.OutputFields))
+		}
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no alerts of rule `%s` found", rule)
+	}
+
+	for _, e := range entry.Exceptions {
+		shape, issues := exceptionShapeOf(e)
+		if len(issues) > 0 || (len(fields) > 0 && strings.Join(shape.Fields, ",") != strings.Join(fields, ",")) {
+			continue
+		}
+		o, ok, err := tuneExceptionOverride(rule, e, false, events)
+		if err != nil || ok {
+			return o, err
+		}
+	}
+
+	if len(fields) == 0 {
+		o, ok, err := tuneListOverride(rs, entry, events)
+		if err != nil || ok {
+			return o, err
+		}
+		if entry.RuleSource() == defaultRuleSource {
+			for _, f := range defaultTuneFields {
+				if _, ok := tuneEventValues(events, []string{f}); ok {
+					fields = append(fields, f)
+				}
+			}
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("no exception or list of rule `%s` can suppress the alerts, fields must be specified to add a new exception", rule)
+		}
+	}
+
+	var fieldValues, comps []interface{}
+	for _, f := range fields {
+		fieldValues = append(fieldValues, f)
+		comps = append(comps, "=")
+	}
+	e := ruleException{
+		Name:   "tuned_" + strings.Trim(tuneNameRegex.ReplaceAllString(strings.Join(fields, "_"), "_"), "_"),
+		Fields: fieldValues,
+		Comps:  comps,
+	}
+	for _, existing := range entry.Exceptions {
+		if existing.Name == e.Name {
+			return nil, fmt.Errorf("rule `%s` already has an exception named `%s` with different fields", rule, e.Name)
+		}
+	}
+	o, ok, err := tuneExceptionOverride(rule, e, true, events)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("some alerts of rule `%s` have no value for fields %s", rule, strings.Join(fields, ", "))
+	}
+	return o, nil
+}
+
+// tuneOverrideYAML returns the content of a rules file containing an
+// override, in canonical style.
+func tuneOverrideYAML(o *tuneOverride) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode([]*tuneOverride{o}); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return formatRulesFile(buf.Bytes(), defaultFmtWidth)
+}
+
+var tuneCmd = &cobra.Command{
+	Use:   "tune <rule>",
+	Short: "Generate an override file that suppresses the given alerts of a rule",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		alertsPaths, err := cmd.Flags().GetStringArray("alerts")
+		if err != nil {
+			return err
+		}
+
+		if len(alertsPaths) == 0 {
+			return fmt.Errorf("you must specify at least one alerts file")
+		}
+
+		fields, err := cmd.Flags().GetStringArray("field")
+		if err != nil {
+			return err
+		}
+
+		writePath, err := cmd.Flags().GetString("write")
+		if err != nil {
+			return err
+		}
+
+		skipValidation, err := cmd.Flags().GetBool("skip-validation")
+		if err != nil {
+			return err
+		}
+
+		falcoImage, err := cmd.Flags().GetString("falco-image")
+		if err != nil {
+			return err
+		}
+
+		falcoConfigPath, err := cmd.Flags().GetString("config")
+		if err != nil {
+			return err
+		}
+
+		falcoFilesPaths, err := cmd.Flags().GetStringArray("file")
+		if err != nil {
+			return err
+		}
+
+		rules, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		rulesFilesPaths, err := getRulesFilesPaths(rules, registryPath)
+		if err != nil {
+			return err
+		}
+
+		rs, err := loadRuleset(rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+
+		var alerts falco.Detections
+		for _, path := range alertsPaths {
+			a, err := loadTuneAlerts(path)
+			if err != nil {
+				return err
+			}
+			alerts = append(alerts, a...)
+		}
+
+		o, err := tuneRule(rs, args[0], alerts, fields)
+		if err != nil {
+			return err
+		}
+		content, err := tuneOverrideYAML(o)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.ErrOrStderr(), o.Summary)
+
+		overridePath := writePath
+		if len(overridePath) == 0 {
+			cmd.OutOrStdout().Write(content)
+			if skipValidation {
+				return nil
+			}
+			f, err := os.CreateTemp("", "tune-*.yaml")
+			if err != nil {
+				return err
+			}
+			defer os.Remove(f.Name())
+			overridePath = f.Name()
+			f.Close()
+		}
+		if err := os.WriteFile(overridePath, content, 0644); err != nil {
+			return err
+		}
+		if skipValidation {
+			return nil
+		}
+		return validateRulesFiles(falcoImage, falcoConfigPath, append(rulesFilesPaths, overridePath), falcoFilesPaths, cmd.ErrOrStderr(), cmd.ErrOrStderr())
+	},
+}
+
+func init() {
+	tuneCmd.Flags().StringArray("alerts", []string{}, "Files containing the JSON alerts of Falco to be suppressed")
+	tuneCmd.Flags().StringArray("field", []string{}, "Fields of a new exception to be added to the rule, instead of tuning its existing exceptions or lists")
+	tuneCmd.Flags().StringP("write", "w", "", "File to write the override to, instead of printing it")
+	tuneCmd.Flags().Bool("skip-validation", false, "Skip the validation of the override with Falco")
+	tuneCmd.Flags().StringP("falco-image", "i", defaultFalcoDockerImage, "Docker image of Falco to be used for validation")
+	tuneCmd.Flags().StringP("config", "c", "", "Config file to be used for running Falco")
+	tuneCmd.Flags().StringArrayP("file", "f", []string{}, "Extra files required by Falco for running")
+	tuneCmd.Flags().StringArrayP("rule", "r", []string{}, "Rules files to be loaded, in order (defaults to the rules files of the registry)")
+	tuneCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	rootCmd.AddCommand(tuneCmd)
+}
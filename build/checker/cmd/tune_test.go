@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/falcosecurity/testing/pkg/falco"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTuneRule(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, `
+- list: allowed_hosts
+  items: [host1]
+
+- list: shell_binaries
+  items: [bash, sh]
+
+- list: gitlab_binaries
+  items: [gitlab-shell]
+
+- macro: allowed_ssh
+  condition: fd.sip.name in (allowed_hosts)
+
+- rule: ssh
+  desc: ssh connection
+  condition: evt.type = connect and not allowed_ssh
+  output: ssh %fd.sip.name
+  priority: NOTICE
+
+- rule: shell
+  desc: shell spawned
+  condition: evt.type = execve and not (proc.name = sh or proc.pname in (shell_binaries))
+  output: shell %proc.name
+  priority: NOTICE
+
+- rule: gitlab
+  desc: gitlab shell spawned
+  condition: evt.type = execve and not proc.name in (gitlab_binaries)
+  output: shell %proc.name
+  priority: NOTICE
+
+- rule: shell_in_container
+  desc: shell spawned in container
+  condition: evt.type = execve and proc.name in (shell_binaries)
+  output: shell %proc.name
+  priority: NOTICE
+  exceptions:
+    - name: images
+      fields: [container.image.repository, proc.cmdline]
+      comps: [=, startswith]
+`)
+	rs := newRuleset()
+	require.NoError(t, rs.Add(files[0]))
+
+	alert := func(rule string, fields map[string]interface{}) *falco.Alert {
+		return &falco.Alert{Rule: rule, OutputFields: fields}
+	}
+
+	t.Run("list", func(t *testing.T) {
+		t.Parallel()
+		o, err := tuneRule(rs, "ssh", falco.Detections{
+			alert("ssh", map[string]interface{}{"fd.sip.name": "host2"}),
+			alert("ssh", map[string]interface{}{"fd.sip.name": "my host"}),
+			alert("ssh", map[string]interface{}{"fd.sip.name": "host2"}),
+			alert("shell", map[string]interface{}{"proc.name": "ls"}),
+		}, nil)
+		require.NoError(t, err)
+		content, err := tuneOverrideYAML(o)
+		require.NoError(t, err)
+		assert.Equal(t, `- list: allowed_hosts
+  items: [host2, '"my host"']
+  override:
+    items: append
+`, string(content))
+	})
+
+	t.Run("shared-list", func(t *testing.T) {
+		t.Parallel()
+		// shell_binaries is used by other rules, so a new exception is added
+		o, err := tuneRule(rs, "shell", falco.Detections{
+			alert("shell", map[string]interface{}{"proc.name": nil, "proc.pname": "node"}),
+		}, nil)
+		require.NoError(t, err)
+		content, err := tuneOverrideYAML(o)
+		require.NoError(t, err)
+		assert.Equal(t, `- rule: shell
+  exceptions:
+    - name: tuned_proc_pname
+      fields: [proc.pname]
+      comps: [=]
+      values:
+        - [node]
+  override:
+    exceptions: append
+`, string(content))
+	})
+
+	t.Run("private-list", func(t *testing.T) {
+		t.Parallel()
+		// gitlab_binaries is used only by the rule, but it's not meant for
+		// user extension, so a new exception is added
+		o, err := tuneRule(rs, "gitlab", falco.Detections{
+			alert("gitlab", map[string]interface{}{"proc.name": "sh"}),
+		}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, o.List)
+		assert.NotEmpty(t, o.Exceptions)
+		assert.Equal(t, "Adding exception `tuned_proc_name` to rule `gitlab`", o.Summary)
+	})
+
+	t.Run("exception", func(t *testing.T) {
+		t.Parallel()
+		o, err := tuneRule(rs, "shell_in_container", falco.Detections{
+			alert("shell_in_container", map[string]interface{}{"container.image.repository": "nginx", "proc.cmdline": "sh -c ls", "proc.name": "sh"}),
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Appending values to exception `images` of rule `shell_in_container`", o.Summary)
+		content, err := tuneOverrideYAML(o)
+		require.NoError(t, err)
+		assert.Equal(t, `- rule: shell_in_container
+  exceptions:
+    - name: images
+      values:
+        - [nginx, sh -c ls]
+  override:
+    exceptions: append
+`, string(content))
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		t.Parallel()
+		o, err := tuneRule(rs, "shell_in_container", falco.Detections{
+			alert("shell_in_container", map[string]interface{}{"container.image.repository": "nginx", "proc.name": "sh"}),
+		}, []string{"proc.name"})
+		require.NoError(t, err)
+		assert.Equal(t, "Adding exception `tuned_proc_name` to rule `shell_in_container`", o.Summary)
+
+		_, err = tuneRule(rs, "shell_in_container", falco.Detections{
+			alert("shell_in_container", map[string]interface{}{"container.image.repository": "nginx"}),
+		}, []string{"proc.name"})
+		assert.Error(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := tuneRule(rs, "unknown", nil, nil)
+		assert.Error(t, err)
+		_, err = tuneRule(rs, "ssh", falco.Detections{alert("shell", nil)}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestLoadTuneAlerts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	lines := filepath.Join(dir, "lines.json")
+	require.NoError(t, os.WriteFile(lines, []byte(`Falco initialized
+{"rule": "r1", "output_fields": {"proc.name": "sh"}}
+
+{"rule": "r2", "output_fields": {}}
+`), 0644))
+	alerts, err := loadTuneAlerts(lines)
+	require.NoError(t, err)
+	require.Len(t, alerts, 2)
+	assert.Equal(t, "r1", alerts[0].Rule)
+	assert.Equal(t, "sh", alerts[0].OutputFields["proc.name"])
+
+	array := filepath.Join(dir, "array.json")
+	require.NoError(t, os.WriteFile(array, []byte(`[{"rule": "r1"}]`), 0644))
+	alerts, err = loadTuneAlerts(array)
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+}
@@ -0,0 +1,453 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// upgradeIssue is a dependency of an item of an override file on an upstream
+// list, macro, or rule that has been removed, renamed, or changed between
+// two releases.
+type upgradeIssue struct {
+	Location string `json:"location"`
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	// Usage is how the item depends on the upstream one, either append,
+	// override, redefinition, or reference
+	Usage string `json:"usage"`
+	// Change is either removed, renamed, or changed
+	Change     string   `json:"change"`
+	RenamedTo  string   `json:"renamed_to,omitempty"`
+	Details    []string `json:"details,omitempty"`
+	Suggestion string   `json:"suggestion"`
+}
+
+// upgradeReport contains the upgrade issues of an override file.
+type upgradeReport struct {
+	Path   string          `json:"path"`
+	Issues []*upgradeIssue `json:"issues"`
+}
+
+// releaseRulesFilesPaths returns the paths of the rules files of the
+// registry released with the given tags, or the ones of all the rules files
+// that are not archived if none of the tags is a release tag.
+func releaseRulesFilesPaths(reg *registry, tags ...string) ([]string, error) {
+	var res []string
+	for _, tag := range tags {
+		name, _, ok := parseReleaseTag(tag)
+		if !ok {
+			continue
+		}
+		rf, ok := reg.Rulesfile(name)
+		if !ok {
+			return nil, fmt.Errorf("no rules file named '%s' in registry for tag '%s'", name, tag)
+		}
+		if !strSliceContains(res, rf.Path) {
+			res = append(res, rf.Path)
+		}
+	}
+	if len(res) == 0 {
+		for _, rf := range reg.Rulesfiles {
+			if !rf.Archived {
+				rf, _ = reg.Rulesfile(rf.Name)
+				res = append(res, rf.Path)
+			}
+		}
+	}
+	return res, nil
+}
+
+// rulesetEntryOf returns the list, macro, or rule of a ruleset with the
+// given kind and name, or nil if not found.
+func rulesetEntryOf(rs *ruleset, kind, name string) *rulesetEntry {
+	switch kind {
+	case itemKindList:
+		return rs.List(name)
+	case itemKindMacro:
+		return rs.Macro(name)
+	case itemKindRule:
+		return rs.Rule(name)
+	}
+	return nil
+}
+
+// trivialMacroConditions are the conditions of the placeholder macros that
+// users are expected to override, which don't identify a macro.
+var trivialMacroConditions = []string{"never_true", "always_true"}
+
+// isTrivialRulesetEntry returns true if an entry has a placeholder content,
+// such as an empty list or a macro that is always true or always false.
+func isTrivialRulesetEntry(e *rulesetEntry) bool {
+	switch e.Kind {
+	case itemKindList:
+		return len(e.Items) == 0
+	case itemKindMacro:
+		return strSliceContains(trivialMacroConditions, strings.Trim(normalizeCondition(e.Condition), "() "))
+	}
+	return false
+}
+
+// sameRulesetEntryContent returns true if two entries of the same kind have
+// the same content regardless of their name, which hints a rename. Entries
+// with a placeholder content are never considered the same.
+func sameRulesetEntryContent(l, r *rulesetEntry) bool {
+	if isTrivialRulesetEntry(l) || isTrivialRulesetEntry(r) {
+		return false
+	}
+	switch l.Kind {
+	case itemKindList:
+		return len(diffStrSet(l.Items, r.Items)) == 0 && len(diffStrSet(r.Items, l.Items)) == 0
+	case itemKindMacro:
+		return normalizeCondition(l.Condition) == normalizeCondition(r.Condition)
+	case itemKindRule:
+		return normalizeCondition(l.Condition) == normalizeCondition(r.Condition) &&
+			normalizeCondition(l.Output) == normalizeCondition(r.Output)
+	}
+	return false
+}
+
+// findRenamedEntry returns the name of the entry added in the new ruleset
+// with the same content of an entry removed from the old one, if exactly one
+// such entry exists.
+func findRenamedEntry(from, to *ruleset, removed *rulesetEntry) string {
+	var res []string
+	entries, _ := to.entries(removed.Kind)
+	for _, e := range *entries {
+		if rulesetEntryOf(from, e.Kind, e.Name()) == nil && sameRulesetEntryContent(removed, e) {
+			res = append(res, e.Name())
+		}
+	}
+	if len(res) != 1 {
+		return ""
+	}
+	return res[0]
+}
+
+// rulesetEntryChanges returns the semantic changes of a list, macro, or rule
+// between two rulesets, including the ones reported by compare.
+func rulesetEntryChanges(l, r *rulesetEntry, compareDiff []string) []string {
+	var res []string
+	switch l.Kind {
+	case itemKindList:
+		if added := diffStrSet(r.Items, l.Items); len(added) > 0 {
+			res = append(res, "Items added upstream: "+strings.Join(sortedSetKeys(added), ", "))
+		}
+		if removed := diffStrSet(l.Items, r.Items); len(removed) > 0 {
+			res = append(res, "Items removed upstream: "+strings.Join(sortedSetKeys(removed), ", "))
+		}
+	case itemKindMacro, itemKindRule:
+		if normalizeCondition(l.Condition) != normalizeCondition(r.Condition) {
+			res = append(res, "Condition changed upstream")
+		}
+	}
+	for _, d := range compareDiff {
+		if compareDiffMentions(d, l.Kind, l.Name()) {
+			res = append(res, d)
+		}
+	}
+	return res
+}
+
+func sortedSetKeys(s map[string]bool) []string {
+	var res []string
+	for k := range s {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// exceptionFields returns the fields of the exception of a rule with the
+// given name, and false if the rule has no such exception.
+func exceptionFields(r *rulesetEntry, name string) ([]string, bool) {
+	for _, e := range r.Exceptions {
+		if e.Name == name {
+			return exceptionStrings(e.Fields), true
+		}
+	}
+	return nil, false
+}
+
+// checkOverridesUpgrade returns the issues of the items of override files
+// that depend on upstream lists, macros, or rules that have been removed,
+// renamed, or changed in the new upstream ruleset.
+func checkOverridesUpgrade(from, to *ruleset, overrides []*rulesFile) ([]*upgradeReport, error) {
+	fromDesc := rulesetDescription(from)
+	toDesc := rulesetDescription(to)
+	compareDiff := append(append(compareRulesMajor(fromDesc, toDesc), compareRulesMinor(fromDesc, toDesc)...), compareRulesPatch(fromDesc, toDesc)...)
+
+	definedLocally := func(kind, name string) bool {
+		for _, f := range overrides {
+			if fileDefines(f, kind, name) {
+				return true
+			}
+		}
+		return false
+	}
+	isList := func(name string) bool {
+		return from.List(name) != nil || definedLocally(itemKindList, name)
+	}
+
+	var res []*upgradeReport
+	for _, f := range overrides {
+		rep := &upgradeReport{Path: f.Path}
+		for _, item := range f.Items {
+			if item.Kind != itemKindRule && item.Kind != itemKindMacro && item.Kind != itemKindList {
+				continue
+			}
+
+			type target struct{ kind, name, usage string }
+			var targets []target
+			isAppend := item.Append || len(item.Override) > 0
+			for _, v := range item.Override {
+				isAppend = isAppend && v == "append"
+			}
+			switch {
+			case isAppend:
+				targets = append(targets, target{item.Kind, item.Name(), "append"})
+			case item.IsOverride():
+				targets = append(targets, target{item.Kind, item.Name(), "override"})
+			default:
+				targets = append(targets, target{item.Kind, item.Name(), "redefinition"})
+			}
+			refs, err := itemCrossRefs(item, isList)
+			if err != nil {
+				return nil, err
+			}
+			for _, ref := range refs {
+				if (ref.Kind == item.Kind && ref.Name == item.Name()) || definedLocally(ref.Kind, ref.Name) {
+					continue
+				}
+				targets = append(targets, target{ref.Kind, ref.Name, "reference"})
+			}
+
+			for _, t := range targets {
+				l := rulesetEntryOf(from, t.kind, t.name)
+				if l == nil {
+					continue
+				}
+				issue := &upgradeIssue{
+					Location: item.Loc.String(),
+					Kind:     t.kind,
+					Name:     t.name,
+					Usage:    t.usage,
+				}
+				r := rulesetEntryOf(to, t.kind, t.name)
+				if r == nil {
+					issue.Change = "removed"
+					if renamed := findRenamedEntry(from, to, l); len(renamed) > 0 {
+						issue.Change = "renamed"
+						issue.RenamedTo = renamed
+						issue.Suggestion = fmt.Sprintf("Replace `%s` with `%s`", t.name, renamed)
+					} else if t.usage == "redefinition" {
+						issue.Suggestion = fmt.Sprintf("Check whether the %s is still needed, as it now defines a new %s instead of replacing the upstream one", t.kind, t.kind)
+					} else if t.usage == "reference" {
+						issue.Suggestion = fmt.Sprintf("Define the %s in the override files or stop referencing it", t.kind)
+					} else {
+						issue.Suggestion = fmt.Sprintf("Remove the %s of the %s, or turn it into a definition", t.usage, t.kind)
+					}
+					rep.Issues = append(rep.Issues, issue)
+					continue
+				}
+
+				issue.Change = "changed"
+				issue.Details = rulesetEntryChanges(l, r, compareDiff)
+				switch t.usage {
+				case "reference":
+					issue.Suggestion = fmt.Sprintf("Check that the new definition of the %s still fits the %s `%s`", t.kind, item.Kind, item.Name())
+				case "redefinition":
+					issue.Suggestion = fmt.Sprintf("Merge the upstream changes into the redefinition of the %s", t.kind)
+				default:
+					issue.Suggestion = fmt.Sprintf("Check that the %s still applies to the new upstream definition", t.usage)
+				}
+				if t.kind == itemKindRule && t.name == item.Name() {
+					for _, e := range item.Exceptions {
+						fields := exceptionStrings(e.Fields)
+						oldFields, inFrom := exceptionFields(l, e.Name)
+						newFields, inTo := exceptionFields(r, e.Name)
+						switch {
+						case len(fields) > 0 || !inFrom:
+							// exceptions defined by the override itself
+						case !inTo:
+							issue.Details = append(issue.Details, fmt.Sprintf("Exception `%s` has been removed upstream", e.Name))
+							issue.Suggestion = fmt.Sprintf("Define the fields of exception `%s` in the override, or append its values to another exception", e.Name)
+						case strings.Join(oldFields, ",") != strings.Join(newFields, ","):
+							issue.Details = append(issue.Details, fmt.Sprintf("Exception `%s` changed its fields from [%s] to [%s]", e.Name, strings.Join(oldFields, ", "), strings.Join(newFields, ", ")))
+							issue.Suggestion = fmt.Sprintf("Update the values of exception `%s` to match its new fields", e.Name)
+						}
+					}
+				}
+				if len(issue.Details) > 0 {
+					rep.Issues = append(rep.Issues, issue)
+				}
+			}
+		}
+		res = append(res, rep)
+	}
+	return res, nil
+}
+
+func writeUpgradeReports(w io.Writer, reports []*upgradeReport) {
+	found := false
+	for _, rep := range reports {
+		if len(rep.Issues) == 0 {
+			continue
+		}
+		found = true
+		fmt.Fprintf(w, "## %s\n\n", rep.Path)
+		for _, i := range rep.Issues {
+			change := "has been " + i.Change + " upstream"
+			if len(i.RenamedTo) > 0 {
+				change = fmt.Sprintf("has been renamed upstream to `%s`", i.RenamedTo)
+			}
+			fmt.Fprintf(w, "* %s: %s `%s` (%s) %s\n", i.Location, capitalize(i.Kind), i.Name, i.Usage, change)
+			for _, d := range i.Details {
+				fmt.Fprintln(w, "  * "+d)
+			}
+			fmt.Fprintln(w, "  * Suggested fix: "+i.Suggestion)
+		}
+		fmt.Fprintln(w)
+	}
+	if !found {
+		fmt.Fprintln(w, "No issues detected")
+	}
+}
+
+var upgradeCheckCmd = &cobra.Command{
+	Use:   "upgrade-check",
+	Short: "Check the override files that would break when upgrading the rules files from a release to another one",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			return err
+		}
+
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+
+		if len(from) == 0 || len(to) == 0 {
+			return fmt.Errorf("you must specify both the revisions to upgrade from and to")
+		}
+
+		overridesPaths, err := cmd.Flags().GetStringArray("overrides")
+		if err != nil {
+			return err
+		}
+
+		if len(overridesPaths) == 0 {
+			return fmt.Errorf("you must specify at least one override file")
+		}
+
+		rulesFilesPaths, err := cmd.Flags().GetStringArray("rule")
+		if err != nil {
+			return err
+		}
+
+		registryPath, err := cmd.Flags().GetString("registry")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		strict, err := cmd.Flags().GetBool("strict")
+		if err != nil {
+			return err
+		}
+
+		if len(rulesFilesPaths) == 0 {
+			reg, err := loadRegistry(registryPath)
+			if err != nil {
+				return err
+			}
+			rulesFilesPaths, err = releaseRulesFilesPaths(reg, from, to)
+			if err != nil {
+				return err
+			}
+		}
+
+		fromRuleset, err := loadRulesetAt(from, rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+
+		toRuleset, err := loadRulesetAt(to, rulesFilesPaths...)
+		if err != nil {
+			return err
+		}
+
+		var overrides []*rulesFile
+		for _, path := range overridesPaths {
+			f, err := loadRulesFile(path)
+			if err != nil {
+				return err
+			}
+			overrides = append(overrides, f)
+		}
+
+		reports, err := checkOverridesUpgrade(fromRuleset, toRuleset, overrides)
+		if err != nil {
+			return err
+		}
+
+		switch format {
+		case "text":
+			writeUpgradeReports(cmd.OutOrStdout(), reports)
+		case "json":
+			enc := json.NewEncoder(cmd.OutOrStdout())
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(reports); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported output format '%s'", format)
+		}
+
+		if strict {
+			for _, rep := range reports {
+				if len(rep.Issues) > 0 {
+					err = errAppend(err, fmt.Errorf("override file %s has upgrade issues", rep.Path))
+				}
+			}
+		}
+		return err
+	},
+}
+
+func init() {
+	upgradeCheckCmd.Flags().String("from", "", "Git revision of the rules files currently in use, such as falco-rules-1.0.0")
+	upgradeCheckCmd.Flags().String("to", "", "Git revision of the rules files to upgrade to, such as falco-rules-2.0.0")
+	upgradeCheckCmd.Flags().StringArray("overrides", []string{}, "Override files to be checked, applied on top of the rules files")
+	upgradeCheckCmd.Flags().StringArrayP("rule", "r", []string{}, "Upstream rules files to be loaded at both revisions, in order (defaults to the rules files of the release tags, or of the registry)")
+	upgradeCheckCmd.Flags().String("registry", defaultRegistryPath, "Registry file declaring the rules files and their load order")
+	upgradeCheckCmd.Flags().StringP("output", "o", "text", "Output format, one of: text, json")
+	upgradeCheckCmd.Flags().Bool("strict", false, "Fail if any override file has upgrade issues")
+	rootCmd.AddCommand(upgradeCheckCmd)
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: Apache-2.0
+/*
+Copyright (C) 2026 The Falco Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReleaseTag(t *testing.T) {
+	t.Parallel()
+
+	name, v, ok := parseReleaseTag("falco-incubating-rules-1.2.3")
+	require.True(t, ok)
+	assert.Equal(t, "falco-incubating-rules", name)
+	assert.Equal(t, "1.2.3", v.String())
+
+	name, v, ok = parseReleaseTag("falco-rules-2.0.0-rc1")
+	require.True(t, ok)
+	assert.Equal(t, "falco-rules", name)
+	assert.Equal(t, "2.0.0-rc1", v.String())
+
+	_, _, ok = parseReleaseTag("main")
+	assert.False(t, ok)
+	_, _, ok = parseReleaseTag("falco-rules-1.2")
+	assert.False(t, ok)
+}
+
+func TestCheckOverridesUpgrade(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, `
+- list: shell_binaries
+  items: [bash, sh]
+- macro: user_known_shell
+  condition: (never_true)
+- macro: old_name
+  condition: proc.name = foo
+- macro: unchanged
+  condition: evt.type = execve
+- rule: Shell
+  desc: shell
+  condition: unchanged and proc.name in (shell_binaries) and not user_known_shell
+  output: shell %proc.name
+  priority: WARNING
+  exceptions:
+    - name: images
+      fields: [container.image.repository]
+`, `
+- list: shell_binaries
+  items: [bash, sh, zsh]
+- macro: new_name
+  condition: proc.name = foo
+- macro: unchanged
+  condition: evt.type = execve
+- rule: Shell
+  desc: shell
+  condition: unchanged and proc.name in (shell_binaries)
+  output: shell %proc.name
+  priority: WARNING
+`, `
+- macro: user_known_shell
+  condition: proc.pname = node
+- list: shell_binaries
+  items: [fish]
+  override:
+    items: append
+- rule: Shell
+  exceptions:
+    - name: images
+      values: [nginx]
+  override:
+    exceptions: append
+- rule: My rule
+  desc: mine
+  condition: unchanged and old_name
+  output: x
+  priority: INFO
+`)
+	from := newRuleset()
+	require.NoError(t, from.Add(files[0]))
+	to := newRuleset()
+	require.NoError(t, to.Add(files[1]))
+
+	reports, err := checkOverridesUpgrade(from, to, files[2:])
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	issues := reports[0].Issues
+	require.Len(t, issues, 4)
+
+	assert.Equal(t, "user_known_shell", issues[0].Name)
+	assert.Equal(t, "redefinition", issues[0].Usage)
+	assert.Equal(t, "removed", issues[0].Change)
+
+	assert.Equal(t, "shell_binaries", issues[1].Name)
+	assert.Equal(t, "append", issues[1].Usage)
+	assert.Equal(t, "changed", issues[1].Change)
+	assert.Contains(t, issues[1].Details, "Items added upstream: zsh")
+
+	assert.Equal(t, "Shell", issues[2].Name)
+	assert.Contains(t, issues[2].Details, "Condition changed upstream")
+	assert.Contains(t, issues[2].Details, "Exception `images` has been removed upstream")
+	assert.Contains(t, issues[2].Details, "Rule 'Shell' has some exceptions added or removed")
+
+	assert.Equal(t, "old_name", issues[3].Name)
+	assert.Equal(t, "reference", issues[3].Usage)
+	assert.Equal(t, "renamed", issues[3].Change)
+	assert.Equal(t, "new_name", issues[3].RenamedTo)
+	assert.Equal(t, "Replace `old_name` with `new_name`", issues[3].Suggestion)
+}
+
+func TestFindRenamedEntry(t *testing.T) {
+	t.Parallel()
+
+	files := testParseRulesFiles(t, `
+- list: old_empty
+  items: []
+- macro: old_placeholder
+  condition: (never_true)
+- macro: old_name
+  condition: proc.name = foo
+- macro: old_ambiguous
+  condition: proc.name = bar
+- rule: Old output
+  desc: old
+  condition: evt.type = execve
+  output: shell %proc.name
+  priority: WARNING
+- rule: Old rule
+  desc: old
+  condition: evt.type = open
+  output: open %fd.name
+  priority: WARNING
+`, `
+- list: new_empty
+  items: []
+- macro: new_placeholder
+  condition: (never_true)
+- macro: new_name
+  condition: proc.name = foo
+- macro: new_ambiguous_1
+  condition: proc.name = bar
+- macro: new_ambiguous_2
+  condition: proc.name = bar
+- rule: New output
+  desc: new
+  condition: evt.type = execveat
+  output: shell %proc.name
+  priority: WARNING
+- rule: New rule
+  desc: new
+  condition: evt.type = open
+  output: open %fd.name
+  priority: NOTICE
+`)
+	from := newRuleset()
+	require.NoError(t, from.Add(files[0]))
+	to := newRuleset()
+	require.NoError(t, to.Add(files[1]))
+
+	assert.Equal(t, "new_name", findRenamedEntry(from, to, from.Macro("old_name")))
+	assert.Equal(t, "New rule", findRenamedEntry(from, to, from.Rule("Old rule")))
+	// placeholders, multiple candidates, and rules sharing only the output
+	// are not renames
+	assert.Empty(t, findRenamedEntry(from, to, from.List("old_empty")))
+	assert.Empty(t, findRenamedEntry(from, to, from.Macro("old_placeholder")))
+	assert.Empty(t, findRenamedEntry(from, to, from.Macro("old_ambiguous")))
+	assert.Empty(t, findRenamedEntry(from, to, from.Rule("Old output")))
+}
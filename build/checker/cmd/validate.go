@@ -19,12 +19,70 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/falcosecurity/testing/pkg/falco"
 	"github.com/falcosecurity/testing/pkg/run"
 	"github.com/spf13/cobra"
 )
 
+// validateRulesFiles validates one or more rules files with Falco, writing
+// the validation output on failures. Returns nil if Falco reported no errors
+// or warnings.
+func validateRulesFiles(falcoImage, falcoConfigPath string, rulesFilesPaths, falcoFilesPaths []string, stdout, stderr io.Writer) error {
+	var ruleFiles []run.FileAccessor
+	for _, rf := range rulesFilesPaths {
+		f := run.NewLocalFileAccessor(rf, rf)
+		ruleFiles = append(ruleFiles, f)
+	}
+
+	falcoTestOptions := []falco.TestOption{
+		falco.WithOutputJSON(),
+		falco.WithRulesValidation(ruleFiles...),
+	}
+
+	if len(falcoConfigPath) > 0 {
+		config := run.NewLocalFileAccessor(falcoConfigPath, falcoConfigPath)
+		falcoTestOptions = append(falcoTestOptions, falco.WithConfig(config))
+	}
+
+	if len(falcoFilesPaths) > 0 {
+		for _, path := range falcoFilesPaths {
+			file := run.NewLocalFileAccessor(path, path)
+			falcoTestOptions = append(falcoTestOptions, falco.WithExtraFiles(file))
+		}
+	}
+
+	// run falco and collect/print validation issues
+	runner, err := run.NewDockerRunner(falcoImage, defaultFalcoDockerEntrypoint, nil)
+	if err != nil {
+		return err
+	}
+
+	res := falco.Test(runner, falcoTestOptions...)
+	if res.RuleValidation() == nil {
+		err = errAppend(err, fmt.Errorf("rules validation command failed"))
+	} else {
+		for _, r := range res.RuleValidation().Results {
+			if !r.Successful || len(r.Errors) > 0 || len(r.Warnings) > 0 {
+				err = errAppend(err, fmt.Errorf("rules validation had warning or errors"))
+				fmt.Fprintln(stdout, res.Stdout())
+				break
+			}
+		}
+	}
+
+	// collect errors
+	err = errAppend(err, res.Err())
+	if res.ExitCode() != 0 {
+		err = errAppend(err, fmt.Errorf("unexpected exit code (%d)", res.ExitCode()))
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, res.Stderr())
+	}
+	return err
+}
+
 var validateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate one or more rules file with a given Falco version",
@@ -43,65 +101,17 @@ var validateCmd = &cobra.Command{
 			return fmt.Errorf("you must specify at least one rules file")
 		}
 
-		var ruleFiles []run.FileAccessor
-		for _, rf := range rulesFilesPaths {
-			f := run.NewLocalFileAccessor(rf, rf)
-			ruleFiles = append(ruleFiles, f)
-		}
-
-		falcoTestOptions := []falco.TestOption{
-			falco.WithOutputJSON(),
-			falco.WithRulesValidation(ruleFiles...),
-		}
-
 		falcoConfigPath, err := cmd.Flags().GetString("config")
 		if err != nil {
 			return err
 		}
-		if len(falcoConfigPath) > 0 {
-			config := run.NewLocalFileAccessor(falcoConfigPath, falcoConfigPath)
-			falcoTestOptions = append(falcoTestOptions, falco.WithConfig(config))
-		}
 
 		falcoFilesPaths, err := cmd.Flags().GetStringArray("file")
 		if err != nil {
 			return err
 		}
-		if len(falcoFilesPaths) > 0 {
-			for _, path := range falcoFilesPaths {
-				file := run.NewLocalFileAccessor(path, path)
-				falcoTestOptions = append(falcoTestOptions, falco.WithExtraFiles(file))
-			}
-		}
-
-		// run falco and collect/print validation issues
-		runner, err := run.NewDockerRunner(falcoImage, defaultFalcoDockerEntrypoint, nil)
-		if err != nil {
-			return err
-		}
-
-		res := falco.Test(runner, falcoTestOptions...)
-		if res.RuleValidation() == nil {
-			err = errAppend(err, fmt.Errorf("rules validation command failed"))
-		} else {
-			for _, r := range res.RuleValidation().Results {
-				if !r.Successful || len(r.Errors) > 0 || len(r.Warnings) > 0 {
-					err = errAppend(err, fmt.Errorf("rules validation had warning or errors"))
-					fmt.Fprintln(cmd.OutOrStdout(), res.Stdout())
-					break
-				}
-			}
-		}
 
-		// collect errors
-		err = errAppend(err, res.Err())
-		if res.ExitCode() != 0 {
-			err = errAppend(err, fmt.Errorf("unexpected exit code (%d)", res.ExitCode()))
-		}
-		if err != nil {
-			fmt.Fprintln(cmd.ErrOrStderr(), res.Stderr())
-		}
-		return err
+		return validateRulesFiles(falcoImage, falcoConfigPath, rulesFilesPaths, falcoFilesPaths, cmd.OutOrStdout(), cmd.ErrOrStderr())
 	},
 }
 